@@ -0,0 +1,21 @@
+package transmission
+
+import "time"
+
+// IdleFor returns how long it has been since the torrent last sent or
+// received data. Returns 0 if there has been no activity yet.
+func (t Torrent) IdleFor() time.Duration {
+	if t.ActivityDate.IsZero() {
+		return 0
+	}
+	return time.Since(t.ActivityDate)
+}
+
+// SeedingFor returns how long ago the torrent finished downloading. Returns
+// 0 if it hasn't finished.
+func (t Torrent) SeedingFor() time.Duration {
+	if t.DoneDate.IsZero() {
+		return 0
+	}
+	return time.Since(t.DoneDate)
+}
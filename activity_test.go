@@ -0,0 +1,34 @@
+package transmission
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestActivityDates(t *testing.T) {
+	now := time.Now()
+	tSetup(fmt.Sprintf(`{"arguments":{"torrents":[{"id":1,
+  "activityDate":%d,"doneDate":%d,"startDate":%d,"editDate":0}]},
+  "result":"success"}`,
+		now.Add(-31*24*time.Hour).Unix(),
+		now.Add(-40*24*time.Hour).Unix(),
+		now.Add(-41*24*time.Hour).Unix()))
+	defer tTeardown()
+
+	Convey("Test the activity dates and idle helpers", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.StartDate.Before(torrent.DoneDate), ShouldBeTrue)
+		So(torrent.EditDate.IsZero(), ShouldBeTrue)
+		So(torrent.IdleFor(), ShouldBeGreaterThan, 30*24*time.Hour)
+		So(torrent.SeedingFor(), ShouldBeGreaterThan, 39*24*time.Hour)
+	})
+
+	Convey("Test the idle helpers without dates", t, func() {
+		So(Torrent{}.IdleFor(), ShouldEqual, 0)
+		So(Torrent{}.SeedingFor(), ShouldEqual, 0)
+	})
+}
@@ -0,0 +1,180 @@
+package transmission
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AddTorrentOptions configures AddTorrent.
+type AddTorrentOptions struct {
+	DownloadDir string
+	// DownloadDirTemplate is used when DownloadDir is empty, e.g.
+	// "/data/{label}/{yyyy}/{name}". {name} is the torrent name with path
+	// separators and other unsafe characters replaced, {label} the first
+	// label, and {yyyy}, {mm} and {dd} the current date.
+	DownloadDirTemplate string
+	Paused              bool
+	// Labels requires RPC version 17 (Transmission 4.0).
+	Labels []string
+	// Cookies are sent by the daemon when it fetches a torrent URL, for
+	// trackers that protect their .torrent downloads.
+	Cookies []*http.Cookie
+	// CookieJar supplies cookies for torrent URLs, in addition to Cookies.
+	// It is also used when FetchURL is set.
+	CookieJar http.CookieJar
+	// FetchURL makes the library download torrent URLs itself and upload
+	// them as metainfo, for daemons that can't reach the URL.
+	FetchURL bool
+}
+
+// AddTorrent add a torrent from a magnet link, an http(s) URL or a
+// .torrent file on the local filesystem.
+func (ac *TransmissionClient) AddTorrent(source string, opts AddTorrentOptions) (AddResult, error) {
+	cmd, err := ac.newAddCmd(context.Background(), source, opts)
+	if err != nil {
+		return AddResult{}, err
+	}
+	return ac.ExecuteAddCommand(cmd)
+}
+
+// AddByInfoHash add the torrent with a v1 info hash, in hex or base32, or
+// a v2 info hash in hex. The daemon has to find peers through the DHT or
+// PEX, as the magnet link it is added with names no trackers.
+func (ac *TransmissionClient) AddByInfoHash(hash string, opts AddTorrentOptions) (AddResult, error) {
+	magnet, err := infoHashMagnet(hash)
+	if err != nil {
+		return AddResult{}, err
+	}
+	return ac.AddTorrent(magnet, opts)
+}
+
+// infoHashMagnet returns the minimal magnet link for an info hash.
+func infoHashMagnet(hash string) (string, error) {
+	if isHex(hash, 64) {
+		return "magnet:?xt=urn:btmh:1220" + strings.ToLower(hash), nil
+	}
+	v1, ok := parseBTIH(hash)
+	if !ok {
+		return "", &ValidationError{"hash", hash, "must be 40 hex or 32 base32 characters, or 64 hex for v2"}
+	}
+	return "magnet:?xt=urn:btih:" + v1, nil
+}
+
+// newAddCmd builds the torrent-add command for source. ctx bounds the
+// download of a torrent URL when opts.FetchURL is set.
+func (ac *TransmissionClient) newAddCmd(ctx context.Context, source string, opts AddTorrentOptions) (*Command, error) {
+	var cmd *Command
+	var err error
+	switch {
+	case strings.HasPrefix(source, "magnet:"):
+		_, err = ParseMagnet(source)
+		if err != nil {
+			return nil, err
+		}
+		cmd, err = NewAddCmdByMagnet(source)
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		if opts.FetchURL {
+			cmd, err = ac.fetchAddCmd(ctx, source, opts.CookieJar)
+			break
+		}
+		cmd, err = NewAddCmdByURL(source)
+		if err == nil {
+			cmd.SetCookies(urlCookies(source, opts))
+		}
+	default:
+		cmd, err = NewAddCmdByFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = ac.applyAddOptions(cmd, opts)
+	if err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// applyAddOptions sets the arguments of cmd that don't depend on the
+// source of the torrent.
+func (ac *TransmissionClient) applyAddOptions(cmd *Command, opts AddTorrentOptions) error {
+	if len(opts.Labels) > 0 {
+		err := ac.requireRPCVersion("labels", 17)
+		if err != nil {
+			return err
+		}
+		cmd.Arguments.Labels = opts.Labels
+	}
+	dir := opts.DownloadDir
+	if dir == "" && opts.DownloadDirTemplate != "" {
+		dir = expandDownloadDir(opts.DownloadDirTemplate, addCmdName(cmd), opts.Labels, time.Now())
+	}
+	cmd.SetDownloadDir(dir)
+	cmd.Arguments.Paused = opts.Paused
+	return nil
+}
+
+// urlCookies returns opts.Cookies plus the jar's cookies for rawURL.
+func urlCookies(rawURL string, opts AddTorrentOptions) []*http.Cookie {
+	cookies := append([]*http.Cookie{}, opts.Cookies...)
+	if opts.CookieJar == nil {
+		return cookies
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return cookies
+	}
+	return append(cookies, opts.CookieJar.Cookies(u)...)
+}
+
+// maxFetchedTorrent bounds the .torrent files downloaded for FetchURL.
+const maxFetchedTorrent = 16 << 20
+
+// fetchAddCmd downloads the .torrent at rawURL and builds a torrent-add
+// command uploading it as metainfo. The download goes through the
+// transport or Doer of the client and is bounded by its timeout, but
+// carries none of its credentials.
+func (ac *TransmissionClient) fetchAddCmd(ctx context.Context, rawURL string, jar http.CookieJar) (*Command, error) {
+	if ac.apiclient.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ac.apiclient.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if jar != nil {
+		for _, cookie := range jar.Cookies(req.URL) {
+			req.AddCookie(cookie)
+		}
+	}
+	res, err := ac.apiclient.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if jar != nil {
+		jar.SetCookies(req.URL, res.Cookies())
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", rawURL, res.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxFetchedTorrent+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFetchedTorrent {
+		return nil, fmt.Errorf("fetching %s: larger than %d bytes", rawURL, maxFetchedTorrent)
+	}
+
+	cmd, _ := NewAddCmd()
+	cmd.Arguments.MetaInfo = base64.StdEncoding.EncodeToString(data)
+	return cmd, nil
+}
@@ -0,0 +1,138 @@
+package transmission
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAddTorrentCookies(t *testing.T) {
+	tSetup(`{"arguments":{"torrent-added":
+  {"hashString":"875a2d90068c32b4ce7992eaf56cd03f5be0d193",
+  "id":23,"name":"Test Name"}},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test cookies are passed to torrent-add", t, func() {
+		jar, _ := cookiejar.New(nil)
+		u, _ := url.Parse("https://tracker.example.com/download/1.torrent")
+		jar.SetCookies(u, []*http.Cookie{{Name: "pass", Value: "key"}})
+
+		opts := AddTorrentOptions{
+			Cookies:   []*http.Cookie{{Name: "uid", Value: "1"}},
+			CookieJar: jar,
+		}
+		cmd, err := transmissionClient.newAddCmd(context.Background(), u.String(), opts)
+		So(err, ShouldBeNil)
+		So(cmd.Arguments.Filename, ShouldEqual, u.String())
+		So(cmd.Arguments.Cookies, ShouldEqual, "uid=1; pass=key")
+
+		added, err := transmissionClient.AddTorrent(u.String(), opts)
+		So(err, ShouldBeNil)
+		So(added.Torrent.ID, ShouldEqual, 23)
+	})
+}
+
+func TestAddTorrentFetchURL(t *testing.T) {
+	Convey("Test the library fetches the URL with the jar", t, func() {
+		torrent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie("pass")
+			if err != nil || cookie.Value != "key" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("d4:infode"))
+		}))
+		defer torrent.Close()
+
+		jar, _ := cookiejar.New(nil)
+		u, _ := url.Parse(torrent.URL)
+		jar.SetCookies(u, []*http.Cookie{{Name: "pass", Value: "key"}})
+
+		client := New(torrent.URL, "", "")
+		cmd, err := client.newAddCmd(context.Background(), torrent.URL+"/1.torrent", AddTorrentOptions{
+			CookieJar:   jar,
+			FetchURL:    true,
+			DownloadDir: "/data",
+			Paused:      true,
+		})
+		So(err, ShouldBeNil)
+		So(cmd.Arguments.Filename, ShouldEqual, "")
+		So(cmd.Arguments.MetaInfo, ShouldEqual, base64.StdEncoding.EncodeToString([]byte("d4:infode")))
+		So(cmd.Arguments.DownloadDir, ShouldEqual, "/data")
+		So(cmd.Arguments.Paused, ShouldBeTrue)
+
+		_, err = client.newAddCmd(context.Background(), torrent.URL+"/1.torrent", AddTorrentOptions{FetchURL: true})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test the download goes through the Doer with the context", t, func() {
+		var seen context.Context
+		doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+			seen = req.Context()
+			body := strings.NewReader("d4:infode")
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body)}, nil
+		})
+		client := New("http://daemon.invalid", "", "", WithDoer(doer))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cmd, err := client.newAddCmd(ctx, "http://tracker.invalid/1.torrent", AddTorrentOptions{FetchURL: true})
+		So(err, ShouldBeNil)
+		So(cmd.Arguments.MetaInfo, ShouldEqual, base64.StdEncoding.EncodeToString([]byte("d4:infode")))
+		cancel()
+		So(seen.Err(), ShouldEqual, context.Canceled)
+	})
+
+	Convey("Test an oversized download is rejected", t, func() {
+		torrent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(make([]byte, maxFetchedTorrent+1))
+		}))
+		defer torrent.Close()
+
+		client := New(torrent.URL, "", "")
+		_, err := client.newAddCmd(context.Background(), torrent.URL+"/1.torrent", AddTorrentOptions{FetchURL: true})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestAddByInfoHash(t *testing.T) {
+	tSetup(`{"arguments":{"torrent-added":
+  {"hashString":"875a2d90068c32b4ce7992eaf56cd03f5be0d193",
+  "id":23,"name":"875a2d90068c32b4ce7992eaf56cd03f5be0d193"}},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test a torrent is added by its info hash", t, func() {
+		added, err := transmissionClient.AddByInfoHash("875A2D90068C32B4CE7992EAF56CD03F5BE0D193", AddTorrentOptions{})
+		So(err, ShouldBeNil)
+		So(added.Torrent.ID, ShouldEqual, 23)
+	})
+
+	Convey("Test the magnet links made from info hashes", t, func() {
+		magnet, err := infoHashMagnet("875A2D90068C32B4CE7992EAF56CD03F5BE0D193")
+		So(err, ShouldBeNil)
+		So(magnet, ShouldEqual, "magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193")
+
+		magnet, err = infoHashMagnet("QWRNFEAGRQZLJTTZSLVPW3IDH5PQ3UMT")
+		So(err, ShouldBeNil)
+		So(magnet, ShouldEqual, "magnet:?xt=urn:btih:85a2d290068c32b4ce7992eafb6d033f5f0dd193")
+
+		magnet, err = infoHashMagnet("caf1e1c30e81cb361b9ee167c4aa64228a7fa4fa9f6105232b28ad099f3a302e")
+		So(err, ShouldBeNil)
+		So(magnet, ShouldEqual, "magnet:?xt=urn:btmh:1220caf1e1c30e81cb361b9ee167c4aa64228a7fa4fa9f6105232b28ad099f3a302e")
+	})
+
+	Convey("Test malformed info hashes are rejected", t, func() {
+		for _, hash := range []string{"", "875a2d90", "magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193"} {
+			_, err := transmissionClient.AddByInfoHash(hash, AddTorrentOptions{})
+			So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package transmission
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// AddRetryPolicy configures AddTorrentWithRetry.
+type AddRetryPolicy struct {
+	// MaxRetries is the number of times a failed add is retried. Defaults
+	// to 3.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry. Defaults to 1s.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Defaults to 30s.
+	MaxBackoff time.Duration
+}
+
+// AmbiguousAddError is returned by AddTorrentWithRetry when a torrent-add
+// failed in a way that leaves open whether the daemon added the torrent,
+// and its info hash isn't known to check.
+type AmbiguousAddError struct {
+	Err error
+}
+
+func (e *AmbiguousAddError) Error() string {
+	return fmt.Sprintf("torrent-add may have succeeded: %v", e.Err)
+}
+
+func (e *AmbiguousAddError) Unwrap() error {
+	return e.Err
+}
+
+// addFailure classifies why a torrent-add failed.
+type addFailure int
+
+const (
+	// addRejected failures are final, such as a torrent the daemon
+	// couldn't parse.
+	addRejected addFailure = iota
+	// addUnsent failures happened before the request reached the daemon.
+	addUnsent
+	// addAmbiguous failures happened after the request may have reached
+	// the daemon, such as a timeout or a 502 from a proxy.
+	addAmbiguous
+)
+
+// AddTorrentWithRetry is AddTorrent retrying network failures and 5xx
+// responses with exponential backoff. When a failure leaves open whether
+// the torrent was added, it is looked up by its info hash before adding it
+// again, and returned if the daemon has it. The info hash is known for
+// magnet links and .torrent files; after such a failure adding any other
+// source fails with an *AmbiguousAddError rather than risk adding twice.
+func (ac *TransmissionClient) AddTorrentWithRetry(ctx context.Context, source string, opts AddTorrentOptions, policy AddRetryPolicy) (AddResult, error) {
+	cmd, err := ac.newAddCmd(ctx, source, opts)
+	if err != nil {
+		return AddResult{}, err
+	}
+	if policy.MaxRetries <= 0 {
+		policy.MaxRetries = 3
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = time.Second
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 30 * time.Second
+	}
+
+	hash := addCmdHash(cmd)
+	backoff := policy.InitialBackoff
+	unverified := false
+	for attempt := 0; ; attempt++ {
+		var (
+			result  AddResult
+			failure addFailure
+			found   bool
+		)
+		if unverified {
+			result, found, err = ac.findAdded(ctx, hash)
+			failure = addUnsent
+			if err == nil && !found {
+				unverified = false
+			}
+		}
+		if found {
+			return result, nil
+		}
+		if !unverified {
+			result, failure, err = ac.tryAdd(ctx, cmd)
+		}
+		if err == nil {
+			return result, nil
+		}
+		if failure == addAmbiguous {
+			if hash == "" {
+				return AddResult{}, &AmbiguousAddError{err}
+			}
+			unverified = true
+		}
+		if failure == addRejected || ctx.Err() != nil || attempt >= policy.MaxRetries {
+			return AddResult{}, err
+		}
+
+		ac.apiclient.stats.retry()
+		ac.apiclient.log(ctx, slog.LevelWarn, "retrying torrent-add",
+			slog.Int("attempt", attempt+1), slog.Duration("backoff", backoff), slog.Any("error", err))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return AddResult{}, err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// tryAdd sends cmd once and classifies a failure.
+func (ac *TransmissionClient) tryAdd(ctx context.Context, cmd *Command) (AddResult, addFailure, error) {
+	response, err := roundTrip[torrentAddResult](ctx, ac, cmd.Method, cmd.Arguments)
+	switch {
+	case err == nil:
+		err = resultError(response.Result)
+	case requestUnsent(err):
+		return AddResult{}, addUnsent, err
+	case response.status == 0 || response.status >= 500:
+		return AddResult{}, addAmbiguous, err
+	}
+	if err != nil {
+		return AddResult{}, addRejected, err
+	}
+	result, err := response.Arguments.addResult()
+	return result, addRejected, err
+}
+
+// findAdded looks up the torrent with hash after an ambiguous failure.
+func (ac *TransmissionClient) findAdded(ctx context.Context, hash string) (AddResult, bool, error) {
+	torrents, err := ac.fetchTorrents(ctx, []string{"id", "name", "hashString"}, Hashes(hash))
+	if err != nil || len(torrents) == 0 {
+		return AddResult{}, false, err
+	}
+	torrent := torrents[0]
+	return AddResult{Torrent: TorrentAdded{HashString: torrent.HashString, ID: torrent.ID, Name: torrent.Name}}, true, nil
+}
+
+// addCmdHash returns the v1 info hash of the torrent cmd adds, or "" when
+// it is a URL or a file on the daemon's host.
+func addCmdHash(cmd *Command) string {
+	if cmd.Arguments.MetaInfo != "" {
+		data, err := base64.StdEncoding.DecodeString(cmd.Arguments.MetaInfo)
+		if err != nil {
+			return ""
+		}
+		return metainfoHash(data)
+	}
+	if strings.HasPrefix(cmd.Arguments.Filename, "magnet:") {
+		magnet, err := ParseMagnet(cmd.Arguments.Filename)
+		if err != nil {
+			return ""
+		}
+		return magnet.InfoHash
+	}
+	return ""
+}
+
+// metainfoHash returns the v1 info hash of a .torrent, or "" if it can't
+// be decoded. The info dictionary is hashed as it appears in data, so
+// torrents whose keys aren't sorted as required still get the hash the
+// daemon computes.
+func metainfoHash(data []byte) string {
+	info, err := rawBencodeValue(data, "info")
+	if err != nil || info[0] != 'd' {
+		return ""
+	}
+	sum := sha1.Sum(info)
+	return hex.EncodeToString(sum[:])
+}
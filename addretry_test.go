@@ -0,0 +1,141 @@
+package transmission
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAddTorrentWithRetry(t *testing.T) {
+	const magnet = "magnet:?xt=urn:btih:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	var (
+		adds    int
+		lookups int
+		added   bool
+		// fail answers the add with the given attempt with a status, or
+		// drops the connection for -1.
+		fail map[int]int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var request struct {
+			Method string `json:"method"`
+			Tag    int    `json:"tag"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Method == "torrent-get" {
+			lookups++
+			torrents := []map[string]interface{}{}
+			if added {
+				torrents = append(torrents, map[string]interface{}{"id": 7, "hashString": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "name": "a"})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": "success", "tag": request.Tag,
+				"arguments": map[string]interface{}{"torrents": torrents}})
+			return
+		}
+
+		adds++
+		switch status := fail[adds]; {
+		case status == -1:
+			// The daemon added the torrent but the response got lost.
+			added = true
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		case status == 200:
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": "invalid or corrupt torrent file", "tag": request.Tag})
+			return
+		case status > 0:
+			w.WriteHeader(status)
+			w.Write([]byte("<html>Bad Gateway</html>"))
+			return
+		}
+		added = true
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": "success", "tag": request.Tag,
+			"arguments": map[string]interface{}{"torrent-added": map[string]interface{}{"id": 7, "hashString": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "name": "a"}}})
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+	policy := AddRetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	reset := func(failures map[int]int) {
+		adds, lookups, added, fail = 0, 0, false, failures
+	}
+
+	Convey("Test 5xx responses are retried after checking the torrent wasn't added", t, func() {
+		reset(map[int]int{1: http.StatusBadGateway, 2: http.StatusServiceUnavailable})
+		result, err := client.AddTorrentWithRetry(context.Background(), magnet, AddTorrentOptions{}, policy)
+		So(err, ShouldBeNil)
+		So(result.Torrent.ID, ShouldEqual, 7)
+		So(adds, ShouldEqual, 3)
+		So(lookups, ShouldEqual, 2)
+	})
+
+	Convey("Test a lost response isn't retried when the torrent was added", t, func() {
+		reset(map[int]int{1: -1})
+		result, err := client.AddTorrentWithRetry(context.Background(), magnet, AddTorrentOptions{}, policy)
+		So(err, ShouldBeNil)
+		So(result.Torrent.HashString, ShouldEqual, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+		So(adds, ShouldEqual, 1)
+		So(lookups, ShouldEqual, 1)
+	})
+
+	Convey("Test an ambiguous failure without an info hash isn't retried", t, func() {
+		reset(map[int]int{1: http.StatusBadGateway})
+		_, err := client.AddTorrentWithRetry(context.Background(), "https://example.com/a.torrent", AddTorrentOptions{}, policy)
+		So(err, ShouldHaveSameTypeAs, &AmbiguousAddError{})
+		So(adds, ShouldEqual, 1)
+	})
+
+	Convey("Test the daemon rejecting a torrent isn't retried", t, func() {
+		reset(map[int]int{1: 200})
+		_, err := client.AddTorrentWithRetry(context.Background(), magnet, AddTorrentOptions{}, policy)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "invalid or corrupt torrent file")
+		So(adds, ShouldEqual, 1)
+		So(lookups, ShouldEqual, 0)
+	})
+
+	Convey("Test retries stop after MaxRetries", t, func() {
+		reset(map[int]int{1: 500, 2: 500, 3: 500})
+		_, err := client.AddTorrentWithRetry(context.Background(), magnet, AddTorrentOptions{},
+			AddRetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond})
+		So(err, ShouldNotBeNil)
+		So(adds, ShouldEqual, 3)
+	})
+
+	Convey("Test an unreachable daemon is retried", t, func() {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		unreachable := New(closed.URL, "", "")
+		_, err := unreachable.AddTorrentWithRetry(context.Background(), magnet, AddTorrentOptions{}, policy)
+		So(err, ShouldNotBeNil)
+		So(errors.As(err, new(*AmbiguousAddError)), ShouldBeFalse)
+		So(unreachable.Stats().Retries, ShouldEqual, 3)
+	})
+}
+
+func TestMetainfoHash(t *testing.T) {
+	Convey("Test the info hash of a .torrent", t, func() {
+		sum := sha1.Sum([]byte("d6:lengthi3e4:name1:ae"))
+		So(metainfoHash([]byte("d8:announce0:4:infod6:lengthi3e4:name1:aee")), ShouldEqual, hex.EncodeToString(sum[:]))
+		So(metainfoHash([]byte("d8:announce0:e")), ShouldBeEmpty)
+		So(metainfoHash([]byte("not bencode")), ShouldBeEmpty)
+		So(metainfoHash([]byte("d4:info3:abce")), ShouldBeEmpty)
+
+		// Keys out of order are hashed as they are, not sorted.
+		unsorted := sha1.Sum([]byte("d4:name1:a6:lengthi3ee"))
+		So(metainfoHash([]byte("d4:infod4:name1:a6:lengthi3ee8:announce0:e")), ShouldEqual, hex.EncodeToString(unsorted[:]))
+	})
+}
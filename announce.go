@@ -0,0 +1,17 @@
+package transmission
+
+import "time"
+
+// CanReannounce reports whether the daemon allows a manual announce for the
+// torrent at now, based on its manualAnnounceTime.
+func (t Torrent) CanReannounce(now time.Time) bool {
+	if t.ManualAnnounceTime < 0 {
+		return false
+	}
+	return !now.Before(time.Unix(t.ManualAnnounceTime, 0))
+}
+
+// ReannounceTorrent ask the torrent's trackers for more peers
+func (ac *TransmissionClient) ReannounceTorrent(id int) (string, error) {
+	return ac.sendSimpleCommand("torrent-reannounce", IDs(id))
+}
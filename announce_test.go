@@ -0,0 +1,35 @@
+package transmission
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCanReannounce(t *testing.T) {
+	Convey("Test whether a manual announce is allowed", t, func() {
+		now := time.Unix(1000, 0)
+		So(Torrent{ManualAnnounceTime: 900}.CanReannounce(now), ShouldBeTrue)
+		So(Torrent{ManualAnnounceTime: 1000}.CanReannounce(now), ShouldBeTrue)
+		So(Torrent{ManualAnnounceTime: 1100}.CanReannounce(now), ShouldBeFalse)
+		So(Torrent{ManualAnnounceTime: -1}.CanReannounce(now), ShouldBeFalse)
+	})
+}
+
+func TestReannounceTorrent(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"etaIdle":600,
+  "manualAnnounceTime":1500000000}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the announce fields and reannouncing", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.EtaIdle, ShouldEqual, 600)
+		So(torrent.ManualAnnounceTime, ShouldEqual, 1500000000)
+
+		result, err := transmissionClient.ReannounceTorrent(1)
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, "success")
+	})
+}
@@ -0,0 +1,143 @@
+package transmission
+
+import (
+	"path"
+	"strings"
+)
+
+// ArrCategories maps the "categories" of Sonarr, Radarr and similar apps
+// onto Transmission the way those apps do it themselves: a category is a
+// subdirectory of the base download directory, and on daemons with labels
+// it is also set as a label.
+type ArrCategories struct {
+	// BaseDir holds the category directories. Empty uses the daemon's
+	// download-dir.
+	BaseDir string
+
+	client *TransmissionClient
+}
+
+// NewArrCategories create categories under baseDir for client
+func NewArrCategories(client *TransmissionClient, baseDir string) *ArrCategories {
+	return &ArrCategories{BaseDir: baseDir, client: client}
+}
+
+// Dir returns the download directory of category. An empty category is
+// the base directory itself.
+func (c *ArrCategories) Dir(category string) (string, error) {
+	base := c.BaseDir
+	if base == "" {
+		session, err := c.client.GetSession()
+		if err != nil {
+			return "", err
+		}
+		base = session.DownloadDir
+	}
+	if category == "" {
+		return cleanDir(base), nil
+	}
+	return path.Join(base, sanitizePathElement(category)), nil
+}
+
+// Add adds a torrent in category. The category is added to opts.Labels
+// when the daemon supports labels on add.
+func (c *ArrCategories) Add(source, category string, opts AddTorrentOptions) (AddResult, error) {
+	dir, err := c.Dir(category)
+	if err != nil {
+		return AddResult{}, err
+	}
+	opts.DownloadDir = dir
+	if category != "" {
+		version, err := c.client.getRPCVersion()
+		if err == nil && version >= 17 {
+			opts.Labels = append(append([]string(nil), opts.Labels...), category)
+		}
+	}
+	return c.client.AddTorrent(source, opts)
+}
+
+// Torrents returns the torrents in category: the ones with the category
+// as a label, or stored in its directory or below it.
+func (c *ArrCategories) Torrents(category string) (Torrents, error) {
+	dir, err := c.Dir(category)
+	if err != nil {
+		return nil, err
+	}
+	torrents, err := c.client.GetTorrents()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched Torrents
+	for _, torrent := range torrents {
+		torrentDir := cleanDir(torrent.DownloadDir)
+		if (category != "" && torrent.HasLabel(category)) || torrentDir == dir ||
+			strings.HasPrefix(torrentDir, dir+"/") {
+			matched = append(matched, torrent)
+		}
+	}
+	return matched, nil
+}
+
+// ArrState is the state of a torrent as an arr app sees it.
+type ArrState int
+
+const (
+	ArrQueued ArrState = iota
+	ArrDownloading
+	ArrPaused
+	// ArrCompleted means the data is complete and can be imported.
+	ArrCompleted
+	// ArrWarning means a tracker reported a problem.
+	ArrWarning
+	// ArrFailed means a local error, like a full disk, stopped the torrent.
+	ArrFailed
+)
+
+func (s ArrState) String() string {
+	switch s {
+	case ArrQueued:
+		return "queued"
+	case ArrDownloading:
+		return "downloading"
+	case ArrPaused:
+		return "paused"
+	case ArrCompleted:
+		return "completed"
+	case ArrWarning:
+		return "warning"
+	case ArrFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// torrentErrorLocal is the error value of a local error such as a missing
+// directory or a full disk.
+const torrentErrorLocal = 3
+
+// ArrStatus returns the state of a torrent by the rules arr apps use: a
+// torrent with nothing left to download is completed whether it is still
+// seeding or stopped.
+func ArrStatus(t Torrent) ArrState {
+	switch {
+	case t.Error == torrentErrorLocal:
+		return ArrFailed
+	case t.Error != 0:
+		return ArrWarning
+	case t.LeftUntilDone == 0 && t.PercentDone >= 1 && t.Status != StatusCheck && t.Status != StatusWait:
+		return ArrCompleted
+	case t.Status == StatusPaused:
+		return ArrPaused
+	case t.Status == StatusWait || t.Status == StatusCheck || t.Status == StatusDownloadWait:
+		return ArrQueued
+	}
+	return ArrDownloading
+}
+
+// ArrCanBeRemoved reports whether an arr app would consider the torrent
+// done with: complete, and stopped by the daemon after reaching its seed
+// limits.
+func ArrCanBeRemoved(t Torrent) bool {
+	return ArrStatus(t) == ArrCompleted && t.IsFinished && t.Status == StatusPaused
+}
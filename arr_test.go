@@ -0,0 +1,56 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestArrCategories(t *testing.T) {
+	tSetup(`{"arguments":{"download-dir":"/downloads/","rpc-version":17,
+  "torrent-added":{"id":9},"torrents":[
+  {"id":1,"downloadDir":"/downloads/tv-sonarr"},
+  {"id":2,"downloadDir":"/downloads/tv-sonarr/Show S01"},
+  {"id":3,"downloadDir":"/elsewhere","labels":["tv-sonarr"]},
+  {"id":4,"downloadDir":"/downloads/tv-sonarr-old"},
+  {"id":5,"downloadDir":"/downloads"}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test category directories", t, func() {
+		categories := NewArrCategories(&transmissionClient, "")
+		dir, err := categories.Dir("tv-sonarr")
+		So(err, ShouldBeNil)
+		So(dir, ShouldEqual, "/downloads/tv-sonarr")
+
+		dir, err = NewArrCategories(&transmissionClient, "/data").Dir("../x")
+		So(err, ShouldBeNil)
+		So(dir, ShouldEqual, "/data/.._x")
+	})
+
+	Convey("Test torrents in a category", t, func() {
+		categories := NewArrCategories(&transmissionClient, "")
+		torrents, err := categories.Torrents("tv-sonarr")
+		So(err, ShouldBeNil)
+		So(tIDs(torrents), ShouldResemble, []int{1, 2, 3})
+
+		added, err := categories.Add("magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193", "tv-sonarr", AddTorrentOptions{})
+		So(err, ShouldBeNil)
+		So(added.Torrent.ID, ShouldEqual, 9)
+	})
+}
+
+func TestArrStatus(t *testing.T) {
+	Convey("Test torrents map onto arr states", t, func() {
+		So(ArrStatus(Torrent{Status: StatusDownload, LeftUntilDone: 10}), ShouldEqual, ArrDownloading)
+		So(ArrStatus(Torrent{Status: StatusDownloadWait, LeftUntilDone: 10}), ShouldEqual, ArrQueued)
+		So(ArrStatus(Torrent{Status: StatusPaused, LeftUntilDone: 10}), ShouldEqual, ArrPaused)
+		So(ArrStatus(Torrent{Status: StatusSeed, PercentDone: 1}), ShouldEqual, ArrCompleted)
+		So(ArrStatus(Torrent{Status: StatusPaused, PercentDone: 1}), ShouldEqual, ArrCompleted)
+		So(ArrStatus(Torrent{Status: StatusCheck, PercentDone: 1}), ShouldEqual, ArrQueued)
+		So(ArrStatus(Torrent{Status: StatusSeed, PercentDone: 1, Error: 2}), ShouldEqual, ArrWarning)
+		So(ArrStatus(Torrent{Status: StatusPaused, Error: 3}).String(), ShouldEqual, "failed")
+
+		So(ArrCanBeRemoved(Torrent{Status: StatusPaused, PercentDone: 1, IsFinished: true}), ShouldBeTrue)
+		So(ArrCanBeRemoved(Torrent{Status: StatusSeed, PercentDone: 1}), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,229 @@
+package transmission
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// authScheme adds credentials to the requests of a client.
+type authScheme interface {
+	// authorize adds credentials to req.
+	authorize(req *http.Request)
+	// challenged is called with a 401 response and reports whether the
+	// request should be sent again with new credentials.
+	challenged(res *http.Response) bool
+}
+
+// authScheme returns the scheme of the client, basic auth by default.
+func (ac *ApiClient) authScheme() authScheme {
+	if ac.auth == nil {
+		return basicAuth{ac.username, ac.password}
+	}
+	return ac.auth
+}
+
+// WithBearerToken authenticates with an "Authorization: Bearer" header
+// instead of the username and password, for daemons behind a proxy that
+// checks tokens.
+func WithBearerToken(token string) Option {
+	return WithAuthHeader("Authorization", "Bearer "+token)
+}
+
+// WithAuthHeader authenticates by sending a static header, e.g. an API key
+// checked by a proxy, instead of the username and password.
+func WithAuthHeader(name, value string) Option {
+	return func(ac *ApiClient) {
+		ac.auth = headerAuth{name, value}
+	}
+}
+
+// WithDigestAuth authenticates with HTTP digest authentication using the
+// username and password of the client, for daemons behind a proxy that
+// requires it. MD5 and SHA-256 are supported.
+func WithDigestAuth() Option {
+	return func(ac *ApiClient) {
+		ac.auth = &digestAuth{username: ac.username, password: ac.password}
+	}
+}
+
+type basicAuth struct {
+	username, password string
+}
+
+func (a basicAuth) authorize(req *http.Request) {
+	req.SetBasicAuth(a.username, a.password)
+}
+
+func (basicAuth) challenged(*http.Response) bool {
+	return false
+}
+
+type headerAuth struct {
+	name, value string
+}
+
+func (a headerAuth) authorize(req *http.Request) {
+	req.Header.Set(a.name, a.value)
+}
+
+func (headerAuth) challenged(*http.Response) bool {
+	return false
+}
+
+// digestAuth answers the last digest challenge of the server. It is
+// shared between the requests of a client, which count the nonce uses.
+type digestAuth struct {
+	username, password string
+
+	mu        sync.Mutex
+	challenge map[string]string
+	count     int
+}
+
+func (a *digestAuth) authorize(req *http.Request) {
+	a.mu.Lock()
+	if a.challenge == nil {
+		a.mu.Unlock()
+		return
+	}
+	a.count++
+	challenge, count := a.challenge, a.count
+	a.mu.Unlock()
+
+	header, err := digestAuthorization(challenge, a.username, a.password,
+		req.Method, req.URL.RequestURI(), count, newCnonce())
+	if err == nil {
+		req.Header.Set("Authorization", header)
+	}
+}
+
+func (a *digestAuth) challenged(res *http.Response) bool {
+	for _, header := range res.Header.Values("WWW-Authenticate") {
+		scheme, params, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+		challenge := parseAuthParams(params)
+		if challenge["nonce"] == "" {
+			continue
+		}
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		// A new challenge after answering the same nonce means the
+		// credentials are wrong, unless the server says it went stale.
+		if a.challenge != nil && a.challenge["nonce"] == challenge["nonce"] &&
+			!strings.EqualFold(challenge["stale"], "true") {
+			return false
+		}
+		a.challenge = challenge
+		a.count = 0
+		return true
+	}
+	return false
+}
+
+// digestAuthorization returns the Authorization header answering
+// challenge, as described in RFC 7616.
+func digestAuthorization(challenge map[string]string, username, password, method, uri string, count int, cnonce string) (string, error) {
+	algorithm := challenge["algorithm"]
+	base, session := strings.CutSuffix(strings.ToUpper(algorithm), "-SESS")
+	var newHash func() hash.Hash
+	switch base {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+	h := func(s string) string {
+		sum := newHash()
+		sum.Write([]byte(s))
+		return hex.EncodeToString(sum.Sum(nil))
+	}
+
+	realm, nonce := challenge["realm"], challenge["nonce"]
+	nc := fmt.Sprintf("%08x", count)
+	ha1 := h(username + ":" + realm + ":" + password)
+	if session {
+		ha1 = h(ha1 + ":" + nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+
+	qop := ""
+	for _, option := range strings.Split(challenge["qop"], ",") {
+		if strings.TrimSpace(option) == "auth" {
+			qop = "auth"
+		}
+	}
+	var response string
+	if qop == "" {
+		response = h(ha1 + ":" + nonce + ":" + ha2)
+	} else {
+		response = h(ha1 + ":" + nonce + ":" + nc + ":" + cnonce + ":" + qop + ":" + ha2)
+	}
+
+	header := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		username, realm, nonce, uri, response)
+	if algorithm != "" {
+		header += ", algorithm=" + algorithm
+	}
+	if qop != "" {
+		header += fmt.Sprintf(`, qop=%s, nc=%s, cnonce="%s"`, qop, nc, cnonce)
+	}
+	if opaque, ok := challenge["opaque"]; ok {
+		header += fmt.Sprintf(`, opaque="%s"`, opaque)
+	}
+	return header, nil
+}
+
+func newCnonce() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// parseAuthParams parses the comma separated key=value pairs of a
+// WWW-Authenticate header. Values may be quoted.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if s == "" {
+			return params
+		}
+		key, rest, found := strings.Cut(s, "=")
+		if !found {
+			return params
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		rest = strings.TrimLeft(rest, " ")
+
+		var value strings.Builder
+		if strings.HasPrefix(rest, `"`) {
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				value.WriteByte(rest[i])
+			}
+			s = rest[min(i+1, len(rest)):]
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			value.WriteString(strings.TrimSpace(rest[:end]))
+			s = rest[end:]
+		}
+		params[key] = value.String()
+	}
+}
@@ -0,0 +1,94 @@
+package transmission
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDigestAuthorization(t *testing.T) {
+	Convey("Test the example of RFC 2617", t, func() {
+		challenge := parseAuthParams(`realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`)
+		So(challenge["realm"], ShouldEqual, "testrealm@host.com")
+		So(challenge["qop"], ShouldEqual, "auth,auth-int")
+
+		header, err := digestAuthorization(challenge, "Mufasa", "Circle Of Life", "GET", "/dir/index.html", 1, "0a4f113b")
+		So(err, ShouldBeNil)
+		So(header, ShouldContainSubstring, `response="6629fae49393a05397450978507c4ef1"`)
+		So(header, ShouldContainSubstring, `qop=auth, nc=00000001, cnonce="0a4f113b"`)
+		So(header, ShouldContainSubstring, `opaque="5ccc069c403ebaf9f0171e9517f40e41"`)
+	})
+
+	Convey("Test unsupported algorithms", t, func() {
+		_, err := digestAuthorization(map[string]string{"algorithm": "SHA-512-256"}, "u", "p", "POST", "/", 1, "c")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test parsing unquoted and escaped values", t, func() {
+		params := parseAuthParams(`realm="a \"b\"", algorithm=SHA-256, stale=TRUE`)
+		So(params, ShouldResemble, map[string]string{"realm": `a "b"`, "algorithm": "SHA-256", "stale": "TRUE"})
+	})
+}
+
+func TestAuthSchemes(t *testing.T) {
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		authorization = append(authorization, header)
+		if header == "" && r.Header.Get("X-Api-Key") == "" || strings.HasPrefix(header, "Basic") {
+			w.Header().Set("WWW-Authenticate", `Digest realm="rpc", nonce="abc", qop="auth", algorithm=SHA-256`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.HasPrefix(header, "Digest") {
+			challenge := parseAuthParams(strings.TrimPrefix(header, "Digest "))
+			expected, _ := digestAuthorization(map[string]string{"realm": "rpc", "nonce": "abc", "qop": "auth", "algorithm": "SHA-256"},
+				"user", "secret", r.Method, r.URL.RequestURI(), 1, challenge["cnonce"])
+			if challenge["nc"] == "00000001" && header != expected {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		w.Header().Set("X-Transmission-Session-Id", "token")
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Write([]byte(`{"arguments":{},"result":"success"}`))
+	}))
+	defer server.Close()
+
+	Convey("Test bearer tokens", t, func() {
+		authorization = nil
+		client := New(server.URL, "", "", WithBearerToken("t0ken"))
+		So(client.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(authorization, ShouldContain, "Bearer t0ken")
+	})
+
+	Convey("Test static headers", t, func() {
+		client := New(server.URL, "", "", WithAuthHeader("X-Api-Key", "k"))
+		So(client.apiclient.authScheme(), ShouldResemble, headerAuth{"X-Api-Key", "k"})
+		So(client.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+	})
+
+	Convey("Test digest authentication answers the challenge", t, func() {
+		authorization = nil
+		client := New(server.URL, "user", "secret", WithDigestAuth())
+		So(client.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(authorization[0], ShouldEqual, "")
+		So(authorization[1], ShouldStartWith, `Digest username="user", realm="rpc", nonce="abc"`)
+		So(authorization[1], ShouldContainSubstring, "nc=00000001")
+
+		So(client.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(authorization[len(authorization)-1], ShouldContainSubstring, "nc=00000003")
+	})
+
+	Convey("Test basic auth stays the default", t, func() {
+		client := NewClient(server.URL, "u", "p")
+		So(client.authScheme(), ShouldResemble, basicAuth{"u", "p"})
+	})
+}
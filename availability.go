@@ -0,0 +1,33 @@
+package transmission
+
+// DistributedCopies computes the swarm availability of the torrent from its
+// per-piece availability: the number of complete copies available among the
+// connected peers, plus the fraction of pieces available more often than
+// that. Pieces we already have count as one copy. The availability field
+// (RPC version 17) must have been requested with AddFields.
+func (t Torrent) DistributedCopies() float64 {
+	if len(t.Availability) == 0 {
+		return 0
+	}
+
+	counts := make([]int, len(t.Availability))
+	min := -1
+	for i, count := range t.Availability {
+		if count < 0 {
+			count = 1
+		}
+		counts[i] = count
+		if min == -1 || count < min {
+			min = count
+		}
+	}
+
+	above := 0
+	for _, count := range counts {
+		if count > min {
+			above++
+		}
+	}
+
+	return float64(min) + float64(above)/float64(len(counts))
+}
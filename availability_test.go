@@ -0,0 +1,34 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDistributedCopies(t *testing.T) {
+	Convey("Test computing distributed copies", t, func() {
+		So(Torrent{}.DistributedCopies(), ShouldEqual, 0)
+		So(Torrent{Availability: []int{0, 0, 0}}.DistributedCopies(), ShouldEqual, 0)
+		So(Torrent{Availability: []int{2, 2, 2, 2}}.DistributedCopies(), ShouldEqual, 2)
+		So(Torrent{Availability: []int{1, 2, 3, 1}}.DistributedCopies(), ShouldEqual, 1.5)
+		So(Torrent{Availability: []int{-1, 0, 0, 0}}.DistributedCopies(), ShouldEqual, 0.25)
+	})
+}
+
+func TestGetAvailability(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"availability":[-1,3,2,0]}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test requesting the availability field", t, func() {
+		cmd, err := NewGetTorrentsCmd()
+		So(err, ShouldBeNil)
+		cmd.AddFields("availability")
+		So(cmd.Arguments.Fields, ShouldContain, "availability")
+
+		out, err := transmissionClient.ExecuteCommand(cmd)
+		So(err, ShouldBeNil)
+		So(out.Arguments.Torrents[0].Availability, ShouldResemble, []int{-1, 3, 2, 0})
+	})
+}
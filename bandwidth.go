@@ -0,0 +1,194 @@
+package transmission
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BandwidthStats are the smoothed transfer rates of a torrent or of the
+// session. The averages cover the time sampled so far until it reaches
+// their window.
+type BandwidthStats struct {
+	Download1m  Rate
+	Download5m  Rate
+	Download15m Rate
+	Upload1m    Rate
+	Upload5m    Rate
+	Upload15m   Rate
+	// Downloaded and Uploaded are the bytes transferred between the last
+	// two samples.
+	Downloaded ByteSize
+	Uploaded   ByteSize
+}
+
+// BandwidthSampler samples the transfer counters of the session and of
+// every torrent on an interval. Averaging the counters over minutes is
+// far steadier than rateDownload and rateUpload, which the daemon
+// measures over a few seconds, and so better suited for alerting.
+type BandwidthSampler struct {
+	// Interval between samples in Run. Defaults to ten seconds.
+	Interval time.Duration
+	// OnError is called when a sample fails in Run.
+	OnError func(error)
+
+	client   *TransmissionClient
+	now      func() time.Time
+	mu       sync.Mutex
+	session  bandwidthSeries
+	torrents map[string]*bandwidthSeries
+}
+
+// NewBandwidthSampler create a sampler of the transfers of client
+func NewBandwidthSampler(client *TransmissionClient) *BandwidthSampler {
+	return &BandwidthSampler{
+		Interval: 10 * time.Second,
+		client:   client,
+		now:      time.Now,
+		torrents: make(map[string]*bandwidthSeries),
+	}
+}
+
+// bandwidthFields are the fields a sample fetches.
+var bandwidthFields = []string{"id", "hashString", "downloadedEver", "uploadedEver"}
+
+// Sample records the current counters. Torrents that were removed are
+// forgotten. The requests are aborted when ctx is done.
+func (s *BandwidthSampler) Sample(ctx context.Context) error {
+	var stats SessionStats
+	err := s.client.rpcContext(ctx, "session-stats", nil, &stats)
+	if err != nil {
+		return err
+	}
+	torrents, err := s.client.fetchTorrents(ctx, bandwidthFields, nil)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session.add(now, stats.CurrentStats.DownloadedBytes, stats.CurrentStats.UploadedBytes)
+	seen := make(map[string]bool, len(torrents))
+	for _, torrent := range torrents {
+		seen[torrent.HashString] = true
+		series, ok := s.torrents[torrent.HashString]
+		if !ok {
+			series = &bandwidthSeries{}
+			s.torrents[torrent.HashString] = series
+		}
+		series.add(now, torrent.DownloadedEver, torrent.UploadedEver)
+	}
+	for hash := range s.torrents {
+		if !seen[hash] {
+			delete(s.torrents, hash)
+		}
+	}
+	return nil
+}
+
+// Session returns the stats of the whole session.
+func (s *BandwidthSampler) Session() BandwidthStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.session.stats()
+}
+
+// Torrent returns the stats of the torrent with hash, and whether it was
+// in the last sample.
+func (s *BandwidthSampler) Torrent(hash string) (BandwidthStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	series, ok := s.torrents[hash]
+	if !ok {
+		return BandwidthStats{}, false
+	}
+	return series.stats(), true
+}
+
+// Run samples every interval until ctx is done.
+func (s *BandwidthSampler) Run(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.Sample(ctx)
+		if err != nil && s.OnError != nil {
+			s.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// bandwidthWindow is the longest average kept.
+const bandwidthWindow = 15 * time.Minute
+
+type bandwidthSample struct {
+	at         time.Time
+	downloaded ByteSize
+	uploaded   ByteSize
+}
+
+// bandwidthSeries holds the samples of the last bandwidthWindow, plus the
+// one just before it to average the whole window from.
+type bandwidthSeries struct {
+	samples []bandwidthSample
+}
+
+func (b *bandwidthSeries) add(at time.Time, downloaded, uploaded ByteSize) {
+	if n := len(b.samples); n > 0 {
+		last := b.samples[n-1]
+		if downloaded < last.downloaded || uploaded < last.uploaded {
+			// The counters were reset by a daemon restart.
+			b.samples = nil
+		}
+	}
+	b.samples = append(b.samples, bandwidthSample{at, downloaded, uploaded})
+
+	drop := 0
+	for drop+1 < len(b.samples) && at.Sub(b.samples[drop+1].at) >= bandwidthWindow {
+		drop++
+	}
+	b.samples = b.samples[drop:]
+}
+
+func (b *bandwidthSeries) stats() BandwidthStats {
+	var stats BandwidthStats
+	n := len(b.samples)
+	if n < 2 {
+		return stats
+	}
+	last := b.samples[n-1]
+	stats.Downloaded = last.downloaded - b.samples[n-2].downloaded
+	stats.Uploaded = last.uploaded - b.samples[n-2].uploaded
+	stats.Download1m, stats.Upload1m = b.average(time.Minute)
+	stats.Download5m, stats.Upload5m = b.average(5 * time.Minute)
+	stats.Download15m, stats.Upload15m = b.average(15 * time.Minute)
+	return stats
+}
+
+// average returns the rates since the oldest sample at most window before
+// the last one.
+func (b *bandwidthSeries) average(window time.Duration) (Rate, Rate) {
+	last := b.samples[len(b.samples)-1]
+	first := len(b.samples) - 2
+	for first > 0 && last.at.Sub(b.samples[first-1].at) <= window {
+		first--
+	}
+	start := b.samples[first]
+	elapsed := last.at.Sub(start.at).Seconds()
+	if elapsed <= 0 {
+		return 0, 0
+	}
+	return Rate(float64(last.downloaded-start.downloaded) / elapsed),
+		Rate(float64(last.uploaded-start.uploaded) / elapsed)
+}
@@ -0,0 +1,130 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBandwidthSampler(t *testing.T) {
+	var downloaded, uploaded int64
+	removed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var request struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Method == "session-stats" {
+			fmt.Fprintf(w, `{"arguments":{"current-stats":{"downloadedBytes":%d,"uploadedBytes":%d}},"result":"success"}`,
+				2*downloaded, 2*uploaded)
+			return
+		}
+		if removed {
+			w.Write([]byte(`{"arguments":{"torrents":[]},"result":"success"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"arguments":{"torrents":[{"id":1,"hashString":"aaaa","downloadedEver":%d,"uploadedEver":%d}]},"result":"success"}`,
+			downloaded, uploaded)
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	newSampler := func() *BandwidthSampler {
+		sampler := NewBandwidthSampler(&client)
+		sampler.now = func() time.Time { return now }
+		return sampler
+	}
+	step := func(sampler *BandwidthSampler, elapsed time.Duration, down, up int64) {
+		now = now.Add(elapsed)
+		downloaded += down
+		uploaded += up
+		So(sampler.Sample(context.Background()), ShouldBeNil)
+	}
+
+	Convey("Test rates are averaged over each window", t, func() {
+		now, downloaded, uploaded, removed = start, 0, 0, false
+		sampler := newSampler()
+		So(sampler.Sample(context.Background()), ShouldBeNil)
+		stats, ok := sampler.Torrent("aaaa")
+		So(ok, ShouldBeTrue)
+		So(stats, ShouldResemble, BandwidthStats{})
+
+		// 10 minutes at 1000 B/s, then 1 minute at 4000 B/s.
+		for i := 0; i < 10; i++ {
+			step(sampler, time.Minute, 60000, 6000)
+		}
+		step(sampler, time.Minute, 240000, 0)
+
+		stats, _ = sampler.Torrent("aaaa")
+		So(stats.Download1m, ShouldEqual, Rate(4000))
+		So(stats.Download5m, ShouldEqual, Rate(1600))
+		So(stats.Download15m, ShouldEqual, Rate(1272))
+		So(stats.Upload1m, ShouldEqual, Rate(0))
+		So(stats.Upload15m, ShouldEqual, Rate(90))
+		So(stats.Downloaded, ShouldEqual, ByteSize(240000))
+		So(stats.Uploaded, ShouldEqual, ByteSize(0))
+
+		session := sampler.Session()
+		So(session.Download1m, ShouldEqual, Rate(8000))
+		So(session.Downloaded, ShouldEqual, ByteSize(480000))
+	})
+
+	Convey("Test samples older than the longest window are dropped", t, func() {
+		now, downloaded, uploaded, removed = start, 0, 0, false
+		sampler := newSampler()
+		So(sampler.Sample(context.Background()), ShouldBeNil)
+		for i := 0; i < 20; i++ {
+			step(sampler, time.Minute, 0, 0)
+		}
+		step(sampler, time.Minute, 60000, 0)
+
+		stats, _ := sampler.Torrent("aaaa")
+		So(stats.Download15m, ShouldEqual, Rate(66))
+		So(sampler.session.samples, ShouldHaveLength, 16)
+	})
+
+	Convey("Test a counter reset starts the averages over", t, func() {
+		now, downloaded, uploaded, removed = start, 0, 0, false
+		sampler := newSampler()
+		So(sampler.Sample(context.Background()), ShouldBeNil)
+		step(sampler, time.Minute, 60000, 60000)
+		downloaded, uploaded = 0, 0
+		step(sampler, time.Minute, 0, 0)
+		stats, _ := sampler.Torrent("aaaa")
+		So(stats, ShouldResemble, BandwidthStats{})
+		step(sampler, time.Minute, 30000, 0)
+		stats, _ = sampler.Torrent("aaaa")
+		So(stats.Download1m, ShouldEqual, Rate(500))
+	})
+
+	Convey("Test removed torrents are forgotten", t, func() {
+		now, downloaded, uploaded, removed = start, 0, 0, false
+		sampler := newSampler()
+		So(sampler.Sample(context.Background()), ShouldBeNil)
+		removed = true
+		step(sampler, time.Minute, 0, 0)
+		_, ok := sampler.Torrent("aaaa")
+		So(ok, ShouldBeFalse)
+	})
+
+	Convey("Test Run returns while the daemon hangs", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+		So(tRunFor(NewBandwidthSampler(&client).Run, 50*time.Millisecond), ShouldEqual, context.DeadlineExceeded)
+	})
+}
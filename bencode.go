@@ -0,0 +1,196 @@
+package transmission
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// encodeBencode encodes v, which may be a string, []byte, an integer, a
+// bool, a []interface{}, a []string or a map[string]interface{}.
+// Dictionary keys are written in sorted order as the format requires.
+func encodeBencode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := writeBencode(&buf, v)
+	return buf.Bytes(), err
+}
+
+func writeBencode(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		buf.WriteString(strconv.Itoa(len(v)))
+		buf.WriteByte(':')
+		buf.WriteString(v)
+	case []byte:
+		buf.WriteString(strconv.Itoa(len(v)))
+		buf.WriteByte(':')
+		buf.Write(v)
+	case int:
+		fmt.Fprintf(buf, "i%de", v)
+	case int64:
+		fmt.Fprintf(buf, "i%de", v)
+	case ByteSize:
+		fmt.Fprintf(buf, "i%de", int64(v))
+	case bool:
+		if v {
+			buf.WriteString("i1e")
+		} else {
+			buf.WriteString("i0e")
+		}
+	case []string:
+		buf.WriteByte('l')
+		for _, item := range v {
+			writeBencode(buf, item)
+		}
+		buf.WriteByte('e')
+	case []interface{}:
+		buf.WriteByte('l')
+		for _, item := range v {
+			err := writeBencode(buf, item)
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('e')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('d')
+		for _, key := range keys {
+			writeBencode(buf, key)
+			err := writeBencode(buf, v[key])
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('e')
+	default:
+		return fmt.Errorf("bencode: unsupported type %T", v)
+	}
+	return nil
+}
+
+// maxBencodeDepth limits nesting so hostile input can't exhaust the stack.
+const maxBencodeDepth = 64
+
+var errBencodeSyntax = errors.New("bencode: invalid syntax")
+
+// decodeBencode decodes data into strings, int64s, []interface{} and
+// map[string]interface{}.
+func decodeBencode(data []byte) (interface{}, error) {
+	v, rest, err := readBencode(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errBencodeSyntax
+	}
+	return v, nil
+}
+
+// rawBencodeValue returns the encoded value of key in the dictionary data,
+// exactly as it appears in data.
+func rawBencodeValue(data []byte, key string) ([]byte, error) {
+	if len(data) == 0 || data[0] != 'd' {
+		return nil, errBencodeSyntax
+	}
+	var raw []byte
+	rest := data[1:]
+	for len(rest) > 0 && rest[0] != 'e' {
+		v, next, err := readBencode(rest, 1)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := v.(string)
+		if !ok {
+			return nil, errBencodeSyntax
+		}
+		_, after, err := readBencode(next, 1)
+		if err != nil {
+			return nil, err
+		}
+		if name == key {
+			raw = next[:len(next)-len(after)]
+		}
+		rest = after
+	}
+	if len(rest) != 1 {
+		return nil, errBencodeSyntax
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("bencode: no %q key", key)
+	}
+	return raw, nil
+}
+
+func readBencode(data []byte, depth int) (interface{}, []byte, error) {
+	if len(data) == 0 || depth > maxBencodeDepth {
+		return nil, nil, errBencodeSyntax
+	}
+	switch c := data[0]; {
+	case c == 'i':
+		end := bytes.IndexByte(data, 'e')
+		if end < 0 {
+			return nil, nil, errBencodeSyntax
+		}
+		n, err := strconv.ParseInt(string(data[1:end]), 10, 64)
+		if err != nil {
+			return nil, nil, errBencodeSyntax
+		}
+		return n, data[end+1:], nil
+	case c == 'l':
+		list := []interface{}{}
+		data = data[1:]
+		for len(data) > 0 && data[0] != 'e' {
+			item, rest, err := readBencode(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			list = append(list, item)
+			data = rest
+		}
+		if len(data) == 0 {
+			return nil, nil, errBencodeSyntax
+		}
+		return list, data[1:], nil
+	case c == 'd':
+		dict := map[string]interface{}{}
+		data = data[1:]
+		for len(data) > 0 && data[0] != 'e' {
+			key, rest, err := readBencode(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, nil, errBencodeSyntax
+			}
+			value, rest, err := readBencode(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			dict[name] = value
+			data = rest
+		}
+		if len(data) == 0 {
+			return nil, nil, errBencodeSyntax
+		}
+		return dict, data[1:], nil
+	case c >= '0' && c <= '9':
+		colon := bytes.IndexByte(data, ':')
+		if colon < 0 {
+			return nil, nil, errBencodeSyntax
+		}
+		n, err := strconv.Atoi(string(data[:colon]))
+		if err != nil || n < 0 || n > len(data)-colon-1 {
+			return nil, nil, errBencodeSyntax
+		}
+		return string(data[colon+1 : colon+1+n]), data[colon+1+n:], nil
+	}
+	return nil, nil, errBencodeSyntax
+}
@@ -0,0 +1,50 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBencode(t *testing.T) {
+	Convey("Test encoding sorts dictionary keys", t, func() {
+		data, err := encodeBencode(map[string]interface{}{
+			"name": "debian.iso",
+			"list": []interface{}{int64(1), "a"},
+			"flag": true,
+			"tags": []string{"x"},
+		})
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, "d4:flagi1e4:listli1e1:ae4:name10:debian.iso4:tagsl1:xee")
+
+		_, err = encodeBencode(map[string]interface{}{"bad": 1.5})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test decoding", t, func() {
+		v, err := decodeBencode([]byte("d4:listli-3e0:e4:spam4:eggse"))
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{
+			"list": []interface{}{int64(-3), ""},
+			"spam": "eggs",
+		})
+
+		for _, bad := range []string{"", "i1", "l", "d1:ae", "5:abc", "i1ei2e", "x", "di1ei2ee"} {
+			_, err = decodeBencode([]byte(bad))
+			So(err, ShouldNotBeNil)
+		}
+	})
+
+	Convey("Test reading a raw value", t, func() {
+		raw, err := rawBencodeValue([]byte("d1:bi1e1:ad1:xl1:yeee"), "a")
+		So(err, ShouldBeNil)
+		So(string(raw), ShouldEqual, "d1:xl1:yee")
+
+		for _, bad := range []string{"", "le", "d1:ai1e", "d1:ai1eex", "di1ei2ee"} {
+			_, err = rawBencodeValue([]byte(bad), "a")
+			So(err, ShouldNotBeNil)
+		}
+		_, err = rawBencodeValue([]byte("d1:bi1ee"), "a")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,21 @@
+package transmission
+
+// SetBlocklistEnabled enable or disable the peer blocklist
+func (ac *TransmissionClient) SetBlocklistEnabled(enabled bool) error {
+	return ac.setSession(map[string]interface{}{"blocklist-enabled": enabled})
+}
+
+// SetBlocklistURL set the URL the blocklist is downloaded from
+func (ac *TransmissionClient) SetBlocklistURL(url string) error {
+	return ac.setSession(map[string]interface{}{"blocklist-url": url})
+}
+
+// UpdateBlocklist make the daemon download the blocklist from its
+// configured URL and return the number of rules loaded.
+func (ac *TransmissionClient) UpdateBlocklist() (int, error) {
+	var out struct {
+		BlocklistSize int `json:"blocklist-size"`
+	}
+	err := ac.rpc("blocklist-update", nil, &out)
+	return out.BlocklistSize, err
+}
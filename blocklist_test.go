@@ -0,0 +1,30 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBlocklistSettings(t *testing.T) {
+	tSetup(`{"arguments":{"blocklist-enabled":true,"blocklist-size":393,
+  "blocklist-url":"http://www.example.com/blocklist"},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading the blocklist settings", t, func() {
+		session, err := transmissionClient.GetSession()
+		So(err, ShouldBeNil)
+		So(session.BlocklistEnabled, ShouldBeTrue)
+		So(session.BlocklistSize, ShouldEqual, 393)
+		So(session.BlocklistURL, ShouldEqual, "http://www.example.com/blocklist")
+	})
+
+	Convey("Test updating the blocklist", t, func() {
+		So(transmissionClient.SetBlocklistURL("http://www.example.com/blocklist"), ShouldBeNil)
+		So(transmissionClient.SetBlocklistEnabled(true), ShouldBeNil)
+
+		size, err := transmissionClient.UpdateBlocklist()
+		So(err, ShouldBeNil)
+		So(size, ShouldEqual, 393)
+	})
+}
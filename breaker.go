@@ -0,0 +1,129 @@
+package transmission
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitBreaker configures failing fast against a daemon that keeps
+// failing. After Threshold consecutive failed requests the circuit opens
+// and requests fail with a CircuitOpenError until CoolDown has passed.
+// Then a single request is let through: if it succeeds the circuit closes,
+// otherwise it opens for another CoolDown.
+type CircuitBreaker struct {
+	// Threshold is the number of consecutive failures that open the
+	// circuit. Defaults to 5.
+	Threshold int
+	// CoolDown is how long the circuit stays open. Defaults to 30s.
+	CoolDown time.Duration
+}
+
+// CircuitOpenError is returned without contacting the daemon while the
+// circuit is open.
+type CircuitOpenError struct {
+	// Until is when the next request will be let through.
+	Until time.Time
+}
+
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open until %s", e.Until.Format(time.RFC3339))
+}
+
+// WithCircuitBreaker enables a circuit breaker with the given settings. A
+// request fails when the daemon can't be reached or answers with a 5xx
+// status.
+func WithCircuitBreaker(breaker CircuitBreaker) Option {
+	return func(ac *ApiClient) {
+		if breaker.Threshold <= 0 {
+			breaker.Threshold = 5
+		}
+		if breaker.CoolDown <= 0 {
+			breaker.CoolDown = 30 * time.Second
+		}
+		ac.breaker = &circuit{settings: breaker, now: time.Now}
+	}
+}
+
+// circuit is the state of a CircuitBreaker, shared between copies of an
+// ApiClient. A nil *circuit lets everything through.
+type circuit struct {
+	settings CircuitBreaker
+	now      func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+	probing  bool
+	// generation changes whenever the circuit opens, closes or lets a
+	// probe through, so outcomes of requests let through before can be
+	// told apart.
+	generation uint64
+}
+
+// allow returns a CircuitOpenError if the request must not be sent, and
+// otherwise the generation to record its outcome with.
+func (c *circuit) allow() (uint64, error) {
+	if c == nil {
+		return 0, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.open {
+		return c.generation, nil
+	}
+	until := c.openedAt.Add(c.settings.CoolDown)
+	if c.probing || c.now().Before(until) {
+		return 0, CircuitOpenError{Until: until}
+	}
+	c.probing = true
+	c.generation++
+	return c.generation, nil
+}
+
+// record updates the circuit with the outcome of a request let through in
+// generation. Outcomes of requests let through before the circuit last
+// changed are ignored, so a slow request can't decide a probe.
+func (c *circuit) record(generation uint64, ok bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	c.probing = false
+	if ok {
+		c.failures = 0
+		if c.open {
+			c.open = false
+			c.generation++
+		}
+		return
+	}
+	c.failures++
+	if c.open || c.failures >= c.settings.Threshold {
+		c.open = true
+		c.openedAt = c.now()
+		c.generation++
+	}
+}
+
+// cancel gives up a request let through in generation without an
+// outcome, such as one whose context was cancelled. A probe is let
+// through again on the next request.
+func (c *circuit) cancel(generation uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation == c.generation {
+		c.probing = false
+	}
+}
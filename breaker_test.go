@@ -0,0 +1,120 @@
+package transmission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCircuit(t *testing.T) {
+	Convey("Test the circuit opens, probes and closes", t, func() {
+		now := time.Unix(0, 0)
+		c := &circuit{settings: CircuitBreaker{Threshold: 2, CoolDown: time.Minute},
+			now: func() time.Time { return now }}
+
+		generation, err := c.allow()
+		So(err, ShouldBeNil)
+		c.record(generation, false)
+		generation, err = c.allow()
+		So(err, ShouldBeNil)
+		c.record(generation, false)
+
+		_, err = c.allow()
+		So(err, ShouldHaveSameTypeAs, CircuitOpenError{})
+		So(err.(CircuitOpenError).Until, ShouldResemble, now.Add(time.Minute))
+
+		// After the cool-down a single probe is let through.
+		now = now.Add(time.Minute)
+		probe, err := c.allow()
+		So(err, ShouldBeNil)
+		_, err = c.allow()
+		So(err, ShouldNotBeNil)
+
+		// A failed probe opens the circuit again straight away.
+		c.record(probe, false)
+		_, err = c.allow()
+		So(err, ShouldNotBeNil)
+
+		now = now.Add(time.Minute)
+		probe, err = c.allow()
+		So(err, ShouldBeNil)
+		c.record(probe, true)
+		_, err = c.allow()
+		So(err, ShouldBeNil)
+		_, err = c.allow()
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Test a late outcome doesn't decide the probe", t, func() {
+		now := time.Unix(0, 0)
+		c := &circuit{settings: CircuitBreaker{Threshold: 1, CoolDown: time.Minute},
+			now: func() time.Time { return now }}
+
+		slow, _ := c.allow()
+		failed, _ := c.allow()
+		c.record(failed, false)
+
+		now = now.Add(time.Minute)
+		probe, err := c.allow()
+		So(err, ShouldBeNil)
+		c.record(slow, true)
+		_, err = c.allow()
+		So(err, ShouldNotBeNil)
+
+		c.record(probe, false)
+		_, err = c.allow()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test a cancelled probe lets another one through", t, func() {
+		now := time.Unix(0, 0)
+		c := &circuit{settings: CircuitBreaker{Threshold: 1, CoolDown: time.Minute},
+			now: func() time.Time { return now }}
+
+		generation, _ := c.allow()
+		c.record(generation, false)
+		now = now.Add(time.Minute)
+		probe, _ := c.allow()
+		c.cancel(probe)
+		_, err := c.allow()
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	Convey("Test a failing daemon is no longer contacted", t, func() {
+		client := New(server.URL, "", "", WithCircuitBreaker(CircuitBreaker{Threshold: 2}))
+		client.StartTorrent(1)
+		client.StartTorrent(1)
+		sent := requests
+
+		_, err := client.StartTorrent(1)
+		So(err, ShouldHaveSameTypeAs, CircuitOpenError{})
+		So(requests, ShouldEqual, sent)
+		So(client.Stats().Errors["circuit"], ShouldEqual, 1)
+	})
+
+	Convey("Test cancelled requests aren't counted as failures", t, func() {
+		client := New(server.URL, "", "", WithCircuitBreaker(CircuitBreaker{Threshold: 1}))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := client.Call(ctx, "torrent-start", nil, nil)
+		So(err, ShouldNotBeNil)
+
+		_, err = client.StartTorrent(1)
+		So(err, ShouldNotBeNil)
+		So(errors.As(err, new(CircuitOpenError)), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,237 @@
+package transmission
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// TorrentGetBuilder builds a torrent-get request:
+//
+//	torrents, err := TorrentGet().Fields("id", "name").IDs(1, 2).Do(ctx, &client)
+//
+// Invalid arguments are reported by Command and Do as a *ValidationError.
+type TorrentGetBuilder struct {
+	args torrentGetArgs
+	err  error
+}
+
+// TorrentGet starts a torrent-get request for all torrents with the
+// fields GetTorrents fetches.
+func TorrentGet() *TorrentGetBuilder {
+	return &TorrentGetBuilder{args: torrentGetArgs{Fields: append([]string(nil), torrentGetFields...)}}
+}
+
+// Fields replaces the fields to fetch.
+func (b *TorrentGetBuilder) Fields(fields ...string) *TorrentGetBuilder {
+	b.args.Fields = append([]string(nil), fields...)
+	return b
+}
+
+// AddFields fetches fields in addition to the ones already set.
+func (b *TorrentGetBuilder) AddFields(fields ...string) *TorrentGetBuilder {
+	b.args.Fields = append(b.args.Fields, fields...)
+	return b
+}
+
+// IDs restricts the request to the torrents with ids.
+func (b *TorrentGetBuilder) IDs(ids ...int) *TorrentGetBuilder {
+	for _, id := range ids {
+		if id <= 0 {
+			b.fail(&ValidationError{"ids", id, "must be positive"})
+		}
+	}
+	b.args.Ids = append(b.args.Ids, IDs(ids...)...)
+	return b
+}
+
+// Hashes restricts the request to the torrents with the hash strings.
+func (b *TorrentGetBuilder) Hashes(hashes ...string) *TorrentGetBuilder {
+	for _, hash := range hashes {
+		if hash == "" {
+			b.fail(&ValidationError{"ids", hash, "must not be empty"})
+		}
+	}
+	b.args.Ids = append(b.args.Ids, Hashes(hashes...)...)
+	return b
+}
+
+func (b *TorrentGetBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *TorrentGetBuilder) validate() error {
+	if b.err != nil {
+		return b.err
+	}
+	if len(b.args.Fields) == 0 {
+		return &ValidationError{"fields", b.args.Fields, "must not be empty"}
+	}
+	return nil
+}
+
+// Command returns the request as a Command, for ExecuteCommand.
+func (b *TorrentGetBuilder) Command() (*Command, error) {
+	err := b.validate()
+	if err != nil {
+		return nil, err
+	}
+	cmd := &Command{Method: "torrent-get"}
+	cmd.Arguments.Fields = append([]string(nil), b.args.Fields...)
+	cmd.Arguments.Ids = append(TorrentIDs(nil), b.args.Ids...)
+	return cmd, nil
+}
+
+// Do sends the request with client.
+func (b *TorrentGetBuilder) Do(ctx context.Context, client *TransmissionClient) (Torrents, error) {
+	err := b.validate()
+	if err != nil {
+		return nil, err
+	}
+	result, err := invoke[torrentGetResult](ctx, client, "torrent-get", b.args)
+	return result.Torrents, err
+}
+
+// TorrentAddBuilder builds a torrent-add request:
+//
+//	added, err := TorrentAdd().Magnet(link).Paused(true).Do(ctx, &client)
+//
+// Exactly one of Magnet, URL, Filename and MetaInfo must be set. Invalid
+// arguments are reported by Command and Do as a *ValidationError.
+type TorrentAddBuilder struct {
+	args    torrentAddArgs
+	sources int
+	err     error
+}
+
+// TorrentAdd starts a torrent-add request.
+func TorrentAdd() *TorrentAddBuilder {
+	return &TorrentAddBuilder{}
+}
+
+// Magnet adds the torrent of a magnet link, which is validated with
+// ParseMagnet.
+func (b *TorrentAddBuilder) Magnet(link string) *TorrentAddBuilder {
+	_, err := ParseMagnet(link)
+	if err != nil {
+		b.fail(err)
+	}
+	return b.source(link, "")
+}
+
+// URL adds the torrent the daemon downloads from an http(s) URL.
+func (b *TorrentAddBuilder) URL(url string) *TorrentAddBuilder {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		b.fail(&ValidationError{"filename", url, "must be an http(s) URL"})
+	}
+	return b.source(url, "")
+}
+
+// Filename adds the .torrent file at filename on the daemon's host.
+func (b *TorrentAddBuilder) Filename(filename string) *TorrentAddBuilder {
+	if filename == "" {
+		b.fail(&ValidationError{"filename", filename, "must not be empty"})
+	}
+	return b.source(filename, "")
+}
+
+// MetaInfo adds the torrent of the content of a .torrent file.
+func (b *TorrentAddBuilder) MetaInfo(metainfo []byte) *TorrentAddBuilder {
+	if len(metainfo) == 0 {
+		b.fail(&ValidationError{"metainfo", "", "must not be empty"})
+	}
+	return b.source("", base64.StdEncoding.EncodeToString(metainfo))
+}
+
+func (b *TorrentAddBuilder) source(filename, metainfo string) *TorrentAddBuilder {
+	b.sources++
+	b.args.Filename = filename
+	b.args.MetaInfo = metainfo
+	return b
+}
+
+// DownloadDir sets where the torrent's data is stored.
+func (b *TorrentAddBuilder) DownloadDir(dir string) *TorrentAddBuilder {
+	b.args.DownloadDir = dir
+	return b
+}
+
+// Paused adds the torrent without starting it.
+func (b *TorrentAddBuilder) Paused(paused bool) *TorrentAddBuilder {
+	b.args.Paused = paused
+	return b
+}
+
+// Labels sets the labels of the torrent. Do requires RPC version 17
+// (Transmission 4.0) for them.
+func (b *TorrentAddBuilder) Labels(labels ...string) *TorrentAddBuilder {
+	for _, label := range labels {
+		if label == "" || strings.Contains(label, ",") {
+			b.fail(&ValidationError{"labels", label, "must be non-empty and not contain commas"})
+		}
+	}
+	b.args.Labels = append(b.args.Labels, labels...)
+	return b
+}
+
+// Cookies sets the cookies the daemon sends when it fetches a URL.
+func (b *TorrentAddBuilder) Cookies(cookies ...*http.Cookie) *TorrentAddBuilder {
+	cmd := Command{}
+	cmd.SetCookies(cookies)
+	b.args.Cookies = cmd.Arguments.Cookies
+	return b
+}
+
+func (b *TorrentAddBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *TorrentAddBuilder) validate() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.sources != 1 {
+		return &ValidationError{"filename", b.sources, "exactly one of a magnet link, URL, filename or metainfo must be set"}
+	}
+	return nil
+}
+
+// Command returns the request as a Command, for ExecuteAddCommand.
+func (b *TorrentAddBuilder) Command() (*Command, error) {
+	err := b.validate()
+	if err != nil {
+		return nil, err
+	}
+	cmd := &Command{Method: "torrent-add"}
+	cmd.Arguments.Filename = b.args.Filename
+	cmd.Arguments.MetaInfo = b.args.MetaInfo
+	cmd.Arguments.DownloadDir = b.args.DownloadDir
+	cmd.Arguments.Paused = b.args.Paused
+	cmd.Arguments.Labels = append([]string(nil), b.args.Labels...)
+	cmd.Arguments.Cookies = b.args.Cookies
+	return cmd, nil
+}
+
+// Do sends the request with client.
+func (b *TorrentAddBuilder) Do(ctx context.Context, client *TransmissionClient) (AddResult, error) {
+	err := b.validate()
+	if err != nil {
+		return AddResult{}, err
+	}
+	if len(b.args.Labels) > 0 {
+		err = client.requireRPCVersion("labels", 17)
+		if err != nil {
+			return AddResult{}, err
+		}
+	}
+	result, err := invoke[torrentAddResult](ctx, client, "torrent-add", b.args)
+	if err != nil {
+		return AddResult{}, err
+	}
+	return result.addResult()
+}
@@ -0,0 +1,79 @@
+package transmission
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentGetBuilder(t *testing.T) {
+	var request string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		request = string(body)
+		w.Write([]byte(`{"arguments":{"torrents":[{"id":1,"name":"a"}],
+  "torrent-added":{"id":2,"hashString":"bbbb","name":"b"}},"result":"success"}`))
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	Convey("Test a torrent-get request is built and sent", t, func() {
+		torrents, err := TorrentGet().Fields("id", "name").IDs(1).Hashes("aaaa").Do(context.Background(), &client)
+		So(err, ShouldBeNil)
+		So(torrents, ShouldHaveLength, 1)
+		So(request, ShouldContainSubstring, `"arguments":{"fields":["id","name"],"ids":[1,"aaaa"]}`)
+
+		cmd, err := TorrentGet().AddFields("comment").Command()
+		So(err, ShouldBeNil)
+		So(cmd.Method, ShouldEqual, "torrent-get")
+		So(cmd.Arguments.Fields, ShouldContain, "comment")
+		So(cmd.Arguments.Fields, ShouldContain, "hashString")
+	})
+
+	Convey("Test invalid torrent-get requests are rejected", t, func() {
+		request = ""
+		_, err := TorrentGet().IDs(0).Do(context.Background(), &client)
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		_, err = TorrentGet().Fields().Command()
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		_, err = TorrentGet().Hashes("").Command()
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		So(request, ShouldBeEmpty)
+	})
+
+	Convey("Test a torrent-add request is built and sent", t, func() {
+		added, err := TorrentAdd().Magnet("magnet:?xt=urn:btih:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb").Paused(true).DownloadDir("/data").
+			Cookies(&http.Cookie{Name: "uid", Value: "1"}).Do(context.Background(), &client)
+		So(err, ShouldBeNil)
+		So(added.Torrent.HashString, ShouldEqual, "bbbb")
+		So(request, ShouldContainSubstring,
+			`"arguments":{"filename":"magnet:?xt=urn:btih:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb","download-dir":"/data","paused":true,"cookies":"uid=1"}`)
+
+		cmd, err := TorrentAdd().MetaInfo([]byte("d4:infodee")).Command()
+		So(err, ShouldBeNil)
+		So(cmd.Arguments.MetaInfo, ShouldEqual, "ZDQ6aW5mb2RlZQ==")
+		So(cmd.Arguments.Filename, ShouldBeEmpty)
+	})
+
+	Convey("Test invalid torrent-add requests are rejected", t, func() {
+		_, err := TorrentAdd().Command()
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		_, err = TorrentAdd().Magnet("magnet:?xt=urn:btih:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb").URL("https://example.com/a.torrent").Command()
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		_, err = TorrentAdd().Magnet("https://example.com/a.torrent").Command()
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		_, err = TorrentAdd().URL("ftp://example.com/a.torrent").Command()
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		_, err = TorrentAdd().Filename("/tmp/a.torrent").Labels("a,b").Command()
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+	})
+}
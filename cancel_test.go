@@ -0,0 +1,124 @@
+package transmission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// tHungDaemon answers the session handshake and then hangs in hang until
+// release is closed or the request is cancelled.
+func tHungDaemon(hang func(w http.ResponseWriter, r *http.Request)) (*httptest.Server, chan struct{}) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		hang(w, r)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	return server, release
+}
+
+// tRunFor runs run, such as the Run of a background helper, with a context
+// ending after timeout. It returns the error of run, or an error of its own
+// if run is still going a second after the context ended.
+func tRunFor(run func(context.Context) error, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- run(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout + time.Second):
+		return errors.New("still running after the context ended")
+	}
+}
+
+func TestCancelInFlight(t *testing.T) {
+	Convey("Test cancelling aborts a request the daemon doesn't answer", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		err := client.Call(ctx, "session-get", nil, nil)
+		So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+		So(time.Since(started), ShouldBeLessThan, time.Second)
+	})
+
+	Convey("Test cancelling aborts reading a response the daemon stalls on", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"arguments":{"torrents":[`))
+			w.(http.Flusher).Flush()
+		})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		err := client.Call(ctx, "torrent-get", map[string]interface{}{"fields": []string{"id"}}, nil)
+		So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+		So(time.Since(started), ShouldBeLessThan, time.Second)
+	})
+
+	Convey("Test cancelling stops waiting for another request's session id fetch", t, func() {
+		release := make(chan struct{})
+		fetching := make(chan struct{}, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case fetching <- struct{}{}:
+			default:
+			}
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+
+		first, cancelFirst := context.WithCancel(context.Background())
+		defer cancelFirst()
+		go client.Call(first, "session-get", nil, nil)
+		<-fetching
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		err := client.Call(ctx, "session-get", nil, nil)
+		So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+		So(time.Since(started), ShouldBeLessThan, time.Second)
+	})
+
+	Convey("Test cancelling interrupts the backoff of a reconnecting client", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+		client := New(url, "", "", WithReconnect(ReconnectPolicy{InitialBackoff: time.Minute}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		err := client.Call(ctx, "session-get", nil, nil)
+		So(err, ShouldNotBeNil)
+		So(time.Since(started), ShouldBeLessThan, time.Second)
+	})
+}
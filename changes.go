@@ -0,0 +1,109 @@
+package transmission
+
+import (
+	"context"
+	"time"
+)
+
+// changesPollInterval is how often ChangesSince asks the daemon for
+// recently active torrents while it waits.
+var changesPollInterval = time.Second
+
+// recentlyActiveWindow is a little less than the minute the daemon
+// considers a torrent recently active. A cursor older than this can't
+// rely on recently-active and falls back to the full list.
+const recentlyActiveWindow = 50 * time.Second
+
+// Cursor marks a point in the history of the torrent list. The zero
+// Cursor is before the first change.
+type Cursor struct {
+	known  map[int]Torrent
+	polled time.Time
+}
+
+// Changes holds the torrents that changed since a cursor.
+type Changes struct {
+	// Changed holds the new state of every added or changed torrent.
+	Changed Torrents
+	// Removed holds the IDs of torrents that were removed.
+	Removed []int
+	// Cursor is passed to the next call of ChangesSince.
+	Cursor Cursor
+}
+
+// ChangesSince waits until a torrent changes after cursor and returns the
+// changes, or returns ctx.Err() once ctx is done. With the zero Cursor it
+// returns every torrent straight away.
+func (ac *TransmissionClient) ChangesSince(ctx context.Context, cursor Cursor) (Changes, error) {
+	cmd, _ := NewGetTorrentsCmd()
+	fields := cmd.Arguments.Fields
+
+	for {
+		args := map[string]interface{}{"fields": fields}
+		full := cursor.known == nil || time.Since(cursor.polled) > recentlyActiveWindow
+		if !full {
+			args["ids"] = "recently-active"
+		}
+		var out struct {
+			Torrents Torrents `json:"torrents"`
+			Removed  []int    `json:"removed"`
+		}
+		polled := time.Now()
+		err := ac.rpcContext(ctx, "torrent-get", args, &out)
+		if err != nil {
+			return Changes{}, err
+		}
+
+		changes := cursor.apply(out.Torrents, out.Removed, full)
+		changes.Cursor.polled = polled
+		if cursor.known == nil || len(changes.Changed) > 0 || len(changes.Removed) > 0 {
+			return changes, nil
+		}
+		cursor.polled = polled
+
+		select {
+		case <-ctx.Done():
+			return Changes{}, ctx.Err()
+		case <-time.After(changesPollInterval):
+		}
+	}
+}
+
+// apply compares torrents with the known state and returns the changes
+// along with a cursor holding the new state. When full is set, torrents
+// is the complete list and anything missing from it was removed.
+func (c Cursor) apply(torrents Torrents, removed []int, full bool) Changes {
+	var changes Changes
+	known := make(map[int]Torrent, len(c.known)+len(torrents))
+	for id, torrent := range c.known {
+		known[id] = torrent
+	}
+
+	seen := make(map[int]bool, len(torrents))
+	for _, torrent := range torrents {
+		seen[torrent.ID] = true
+		old, ok := known[torrent.ID]
+		if ok && len(changedFields(old, torrent)) == 0 {
+			continue
+		}
+		known[torrent.ID] = torrent
+		changes.Changed = append(changes.Changed, torrent)
+	}
+	if full {
+		removed = nil
+		for id := range c.known {
+			if !seen[id] {
+				removed = append(removed, id)
+			}
+		}
+	}
+	for _, id := range removed {
+		if _, ok := known[id]; ok {
+			delete(known, id)
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+
+	changes.Cursor = Cursor{known: known}
+	return changes
+}
@@ -0,0 +1,47 @@
+package transmission
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCursorApply(t *testing.T) {
+	Convey("Test applying recently active torrents to a cursor", t, func() {
+		start := Cursor{}.apply(Torrents{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}, nil, true)
+		So(start.Changed, ShouldHaveLength, 2)
+
+		changes := start.Cursor.apply(Torrents{{ID: 1, Name: "a"}, {ID: 3, Name: "c"}}, []int{2, 9}, false)
+		So(changes.Changed, ShouldResemble, Torrents{{ID: 3, Name: "c"}})
+		So(changes.Removed, ShouldResemble, []int{2})
+		So(changes.Cursor.known, ShouldHaveLength, 2)
+
+		// The earlier cursor is left untouched and can be used again.
+		So(start.Cursor.known, ShouldHaveLength, 2)
+		So(start.Cursor.known, ShouldContainKey, 2)
+
+		changes = start.Cursor.apply(Torrents{{ID: 2, Name: "b"}}, nil, true)
+		So(changes.Changed, ShouldBeEmpty)
+		So(changes.Removed, ShouldResemble, []int{1})
+	})
+}
+
+func TestChangesSince(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"name":"a"}],
+  "removed":[]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test waiting for changes", t, func() {
+		changes, err := transmissionClient.ChangesSince(context.Background(), Cursor{})
+		So(err, ShouldBeNil)
+		So(changes.Changed, ShouldHaveLength, 1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = transmissionClient.ChangesSince(ctx, changes.Cursor)
+		So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+	})
+}
@@ -1,87 +1,244 @@
 package transmission
 
 import (
-	"io/ioutil"
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// ApiClient sends RPC requests to a daemon. Requests go through an
+// *http.Client by default, or through the Doer given with WithDoer.
 type ApiClient struct {
-	url      string
-	username string
-	password string
-	token    string
-	client   http.Client
+	url           string
+	username      string
+	password      string
+	token         *sessionToken
+	conn          *connection
+	reconnect     *ReconnectPolicy
+	failover      *endpointList
+	stats         *clientStats
+	breaker       *circuit
+	logger        *slog.Logger
+	codec         JSONCodec
+	transportOpts *TransportOptions
+	auth          authScheme
+	sessionStore  SessionStore
+	version       *versionCache
+	doer          Doer
+	timeout       time.Duration
 }
 
+// Option configures an ApiClient.
+type Option func(*ApiClient)
+
 func NewClient(url string,
-	username string, password string) ApiClient {
-	ac := ApiClient{url: url + "/transmission/rpc", username: username, password: password}
+	username string, password string, opts ...Option) ApiClient {
+	ac := ApiClient{url: url + "/transmission/rpc", username: username, password: password,
+		token: &sessionToken{}, version: &versionCache{}, stats: newClientStats(), conn: &connection{}}
+
+	for _, opt := range opts {
+		opt(&ac)
+	}
+	ac.version.set(ac.loadSession())
 
 	return ac
 }
 
 func (ac *ApiClient) CreateClient(apiToken string) {
-	ac.client = http.Client{}
+	ac.conn.mu.Lock()
+	defer ac.conn.mu.Unlock()
+	ac.conn.client = http.Client{}
 }
 
+// Post sends body and returns the body of the response.
+//
+// Deprecated: Use PostBytes, which doesn't copy the request body.
 func (ac *ApiClient) Post(body string) ([]byte, error) {
-	authRequest, err := ac.authRequest("POST", body)
+	return ac.PostBytes(context.Background(), []byte(body))
+}
+
+// PostContext is like Post but aborts the request when ctx is done.
+//
+// Deprecated: Use PostBytes, which doesn't copy the request body.
+func (ac *ApiClient) PostContext(ctx context.Context, body string) ([]byte, error) {
+	return ac.PostBytes(ctx, []byte(body))
+}
+
+// PostBytes sends body and returns the body of the response. It aborts
+// the request when ctx is done. body isn't modified or retained, so the
+// caller may reuse it afterwards.
+func (ac *ApiClient) PostBytes(ctx context.Context, body []byte) ([]byte, error) {
+	_, resBody, err := ac.post(ctx, rpcMethod(body), body)
+	return resBody, err
+}
+
+// post sends body, a request for method, and returns the status code and
+// body of the response, refreshing the session token once if the daemon
+// answers 409.
+func (ac *ApiClient) post(ctx context.Context, method string, body []byte) (int, []byte, error) {
+	return ac.send(ctx, bytesBody(method, body))
+}
+
+// requestBody is a request body that can be sent more than once, for the
+// retry after a 409, reconnects and failover.
+type requestBody struct {
+	method string
+	size   int64
+	open   func() (io.Reader, error)
+}
+
+func bytesBody(method string, body []byte) requestBody {
+	return requestBody{
+		method: method,
+		size:   int64(len(body)),
+		open: func() (io.Reader, error) {
+			return bytes.NewReader(body), nil
+		},
+	}
+}
+
+// send is post for any requestBody.
+func (ac *ApiClient) send(ctx context.Context, body requestBody) (int, []byte, error) {
+	ac.stats.request(body.method, body.size)
+	started := time.Now()
+	caller := ctx
+	if ac.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ac.timeout)
+		defer cancel()
+	}
+	generation, err := ac.breaker.allow()
 	if err != nil {
-		return make([]byte, 0), err
+		ac.stats.transport(0, 0, err)
+		ac.logRequest(ctx, body, 0, 0, started, err)
+		return 0, make([]byte, 0), err
+	}
+	var (
+		status  int
+		resBody []byte
+	)
+	if ac.reconnect != nil {
+		status, resBody, err = ac.supervisedPost(ctx, body)
+	} else {
+		status, resBody, err = ac.postAny(ctx, body)
+	}
+	if err != nil && caller.Err() != nil {
+		// The caller gave up, which says nothing about the daemon.
+		ac.breaker.cancel(generation)
+	} else {
+		ac.breaker.record(generation, err == nil && status < 500)
+	}
+	ac.stats.transport(status, len(resBody), err)
+	ac.logRequest(ctx, body, status, len(resBody), started, err)
+	return status, resBody, err
+}
+
+// postAny sends body to the first endpoint that accepts a connection.
+func (ac *ApiClient) postAny(ctx context.Context, body requestBody) (int, []byte, error) {
+	if ac.failover == nil {
+		return ac.postOnce(ctx, ac.url, body)
 	}
-	res, err := ac.client.Do(authRequest)
+	return ac.failover.post(ctx, ac, body)
+}
+
+func (ac *ApiClient) postOnce(ctx context.Context, url string, body requestBody) (int, []byte, error) {
+	res, err := ac.doAuthRequest(ctx, url, body)
 	if err != nil {
-		return make([]byte, 0), err
+		return 0, make([]byte, 0), err
 	}
-	defer res.Body.Close()
-	if res.StatusCode == 409 {
-		ac.getToken()
-		authRequest, err := ac.authRequest("POST", body)
+	if res.StatusCode == http.StatusUnauthorized && ac.authScheme().challenged(res) {
+		res.Body.Close()
+		res, err = ac.doAuthRequest(ctx, url, body)
 		if err != nil {
-			return make([]byte, 0), err
+			return 0, make([]byte, 0), err
 		}
-		res, err = ac.client.Do(authRequest)
+	}
+	if res.StatusCode == http.StatusConflict {
+		res.Body.Close()
+		ac.stats.refresh()
+		ac.log(ctx, slog.LevelInfo, "session id refreshed", slog.String("url", url))
+		ac.token.replace(res.Request.Header.Get("X-Transmission-Session-Id"),
+			res.Header.Get("X-Transmission-Session-Id"))
+		// The daemon may have been upgraded, so the version goes too.
+		ac.version.set(nil)
+		ac.saveSession(nil)
+		res, err = ac.doAuthRequest(ctx, url, body)
 		if err != nil {
-			return make([]byte, 0), err
+			return 0, make([]byte, 0), err
 		}
 	}
-	resBody, err := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	resBody, err := readBody(res.Body)
 	if err != nil {
-		return make([]byte, 0), err
+		return res.StatusCode, make([]byte, 0), err
 	}
-	return resBody, nil
+	return res.StatusCode, resBody, nil
 }
 
-func (ac *ApiClient) getToken() error {
-	req, err := http.NewRequest("POST", ac.url, strings.NewReader(""))
+func (ac *ApiClient) doAuthRequest(ctx context.Context, url string, body requestBody) (*http.Response, error) {
+	authRequest, err := ac.authRequest(ctx, url, "POST", body)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return ac.do(authRequest)
+}
 
-	req.SetBasicAuth(ac.username, ac.password)
-	res, err := ac.client.Do(req)
+func (ac *ApiClient) getToken(ctx context.Context, url string) (string, error) {
+	res, err := ac.tokenRequest(ctx, url)
+	if err == nil && res.StatusCode == http.StatusUnauthorized && ac.authScheme().challenged(res) {
+		res.Body.Close()
+		res, err = ac.tokenRequest(ctx, url)
+	}
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer res.Body.Close()
-	ac.token = res.Header.Get("X-Transmission-Session-Id")
-	return nil
+	if res.StatusCode == http.StatusConflict {
+		ac.stats.refresh()
+		ac.log(ctx, slog.LevelDebug, "session id fetched", slog.String("url", url))
+	}
+	return res.Header.Get("X-Transmission-Session-Id"), nil
 }
 
-func (ac *ApiClient) authRequest(method string, body string) (*http.Request, error) {
-	if ac.token == "" {
-		err := ac.getToken()
-		if err != nil {
-			return &http.Request{}, err
-		}
+func (ac *ApiClient) tokenRequest(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(""))
+	if err != nil {
+		return nil, err
 	}
-	req, err := http.NewRequest(method, ac.url, strings.NewReader(body))
+	ac.authScheme().authorize(req)
+	return ac.do(req)
+}
+
+func (ac *ApiClient) authRequest(ctx context.Context, url string, method string, body requestBody) (*http.Request, error) {
+	fetched := false
+	token, err := ac.token.get(ctx, func() (string, error) {
+		fetched = true
+		return ac.getToken(ctx, url)
+	})
+	if err != nil {
+		return &http.Request{}, err
+	}
+	if fetched {
+		ac.version.set(nil)
+		ac.saveSession(nil)
+	}
+	reader, err := body.open()
 	if err != nil {
 		return &http.Request{}, err
 	}
-	req.Header.Add("X-Transmission-Session-Id", ac.token)
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return &http.Request{}, err
+	}
+	if body.size > 0 {
+		req.ContentLength = body.size
+	}
+	req.Header.Add("X-Transmission-Session-Id", token)
 
-	req.SetBasicAuth(ac.username, ac.password)
+	ac.authScheme().authorize(req)
 	return req, nil
 }
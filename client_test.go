@@ -1,6 +1,7 @@
 package transmission
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -63,4 +64,9 @@ func TestPost(t *testing.T) {
 		So(string(output), ShouldEqual, "Not Authorized\n")
 	})
 
+	Convey("Test PostBytes is working correctly", t, func() {
+		output, err := client.PostBytes(context.Background(), nil)
+		So(err, ShouldBeNil)
+		So(string(output), ShouldEqual, `{"arguments":{},"result":"no method name"}`)
+	})
 }
@@ -0,0 +1,184 @@
+package transmission
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PoolKey identifies a client in a Pool.
+type PoolKey struct {
+	URL      string
+	Username string
+	Password string
+}
+
+// Pool creates clients on first use and keeps them for reuse, for services
+// talking to many daemons. Clients idle for longer than MaxIdle are
+// dropped, and Check drops clients whose daemon doesn't answer so they
+// are created afresh on next use.
+type Pool struct {
+	// Options are applied to every client created by the pool.
+	Options []Option
+	// MaxIdle is how long an unused client is kept. Defaults to ten
+	// minutes.
+	MaxIdle time.Duration
+	// MaxClients caps the number of clients, dropping the least recently
+	// used one to make room. Zero means no limit.
+	MaxClients int
+	// Interval between checks in Run. Defaults to one minute.
+	Interval time.Duration
+	// OnUnhealthy is called for every client dropped by a failed check.
+	OnUnhealthy func(PoolKey, error)
+
+	mu      sync.Mutex
+	clients map[PoolKey]*pooledClient
+	now     func() time.Time
+}
+
+type pooledClient struct {
+	client   *TransmissionClient
+	lastUsed time.Time
+}
+
+// NewPool create a pool whose clients use opts
+func NewPool(opts ...Option) *Pool {
+	return &Pool{
+		Options:  opts,
+		MaxIdle:  10 * time.Minute,
+		Interval: time.Minute,
+		clients:  make(map[PoolKey]*pooledClient),
+		now:      time.Now,
+	}
+}
+
+// Get returns the client for key, creating it if needed.
+func (p *Pool) Get(key PoolKey) *TransmissionClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	if pooled, ok := p.clients[key]; ok {
+		pooled.lastUsed = now
+		return pooled.client
+	}
+
+	if p.MaxClients > 0 && len(p.clients) >= p.MaxClients {
+		p.evictOldest()
+	}
+	client := New(key.URL, key.Username, key.Password, p.Options...)
+	p.clients[key] = &pooledClient{client: &client, lastUsed: now}
+	return &client
+}
+
+// Remove drops the client for key.
+func (p *Pool) Remove(key PoolKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remove(key)
+}
+
+// Len returns the number of clients in the pool.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.clients)
+}
+
+func (p *Pool) remove(key PoolKey) {
+	if pooled, ok := p.clients[key]; ok {
+		pooled.client.apiclient.conn.closeIdle()
+		delete(p.clients, key)
+	}
+}
+
+func (p *Pool) evictOldest() {
+	var (
+		oldest PoolKey
+		found  bool
+		last   time.Time
+	)
+	for key, pooled := range p.clients {
+		if !found || pooled.lastUsed.Before(last) {
+			oldest, last, found = key, pooled.lastUsed, true
+		}
+	}
+	if found {
+		p.remove(oldest)
+	}
+}
+
+// Check drops the idle clients, then pings the others and drops those
+// that fail. It returns the errors of the failed clients.
+func (p *Pool) Check(ctx context.Context) map[PoolKey]error {
+	maxIdle := p.MaxIdle
+	if maxIdle <= 0 {
+		maxIdle = 10 * time.Minute
+	}
+
+	p.mu.Lock()
+	now := p.now()
+	clients := make(map[PoolKey]*TransmissionClient, len(p.clients))
+	for key, pooled := range p.clients {
+		if now.Sub(pooled.lastUsed) > maxIdle {
+			p.remove(key)
+			continue
+		}
+		clients[key] = pooled.client
+	}
+	p.mu.Unlock()
+
+	// Ping without the lock so a slow daemon doesn't hold up Get.
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures = make(map[PoolKey]error)
+	)
+	for key, client := range clients {
+		wg.Add(1)
+		go func(key PoolKey, client *TransmissionClient) {
+			defer wg.Done()
+			err := client.Ping(ctx)
+			if err != nil {
+				mu.Lock()
+				failures[key] = err
+				mu.Unlock()
+			}
+		}(key, client)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	for key := range failures {
+		// Leave a client created again since the check alone.
+		if pooled, ok := p.clients[key]; ok && pooled.client == clients[key] {
+			p.remove(key)
+		}
+	}
+	p.mu.Unlock()
+	if p.OnUnhealthy != nil {
+		for key, err := range failures {
+			p.OnUnhealthy(key, err)
+		}
+	}
+	return failures
+}
+
+// Run checks every interval until ctx is done.
+func (p *Pool) Run(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package transmission
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPool(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Transmission-Session-Id", "token")
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Write([]byte(`{"arguments":{"version":"4.0.5"},"result":"success"}`))
+	}))
+	defer healthy.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	Convey("Test clients are created once per key", t, func() {
+		pool := NewPool()
+		alice := pool.Get(PoolKey{URL: healthy.URL, Username: "alice"})
+		So(pool.Get(PoolKey{URL: healthy.URL, Username: "alice"}), ShouldEqual, alice)
+		So(pool.Get(PoolKey{URL: healthy.URL, Username: "bob"}), ShouldNotEqual, alice)
+		So(pool.Len(), ShouldEqual, 2)
+
+		pool.Remove(PoolKey{URL: healthy.URL, Username: "bob"})
+		So(pool.Len(), ShouldEqual, 1)
+	})
+
+	Convey("Test the least recently used client makes room", t, func() {
+		pool := NewPool()
+		now := time.Now()
+		pool.now = func() time.Time { return now }
+		pool.MaxClients = 2
+
+		first := pool.Get(PoolKey{URL: "http://a"})
+		now = now.Add(time.Second)
+		pool.Get(PoolKey{URL: "http://b"})
+		now = now.Add(time.Second)
+		So(pool.Get(PoolKey{URL: "http://a"}), ShouldEqual, first)
+		now = now.Add(time.Second)
+		pool.Get(PoolKey{URL: "http://c"})
+
+		So(pool.Len(), ShouldEqual, 2)
+		So(pool.Get(PoolKey{URL: "http://a"}), ShouldEqual, first)
+	})
+
+	Convey("Test checks drop idle and unhealthy clients", t, func() {
+		pool := NewPool()
+		now := time.Now()
+		pool.now = func() time.Time { return now }
+		var unhealthy []PoolKey
+		pool.OnUnhealthy = func(key PoolKey, err error) { unhealthy = append(unhealthy, key) }
+
+		pool.Get(PoolKey{URL: "http://idle"})
+		now = now.Add(time.Hour)
+		up := pool.Get(PoolKey{URL: healthy.URL})
+		pool.Get(PoolKey{URL: down.URL})
+
+		failures := pool.Check(context.Background())
+		So(failures, ShouldHaveLength, 1)
+		So(failures[PoolKey{URL: down.URL}], ShouldNotBeNil)
+		So(unhealthy, ShouldResemble, []PoolKey{{URL: down.URL}})
+		So(pool.Len(), ShouldEqual, 1)
+		So(pool.Get(PoolKey{URL: healthy.URL}), ShouldEqual, up)
+	})
+
+	Convey("Test removing a client leaves the shared default transport alone", t, func() {
+		var conns int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&conns, 1)
+			}
+		}
+		server.Start()
+		defer server.Close()
+		defer http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+		get := func() {
+			resp, err := http.Get(server.URL)
+			So(err, ShouldBeNil)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		pool := NewPool()
+		pool.Get(PoolKey{URL: healthy.URL})
+		get()
+		pool.Remove(PoolKey{URL: healthy.URL})
+		get()
+		So(atomic.LoadInt32(&conns), ShouldEqual, 1)
+	})
+}
@@ -0,0 +1,45 @@
+package transmission
+
+import "time"
+
+// WithCredentials replaces the username and password of the client. It is
+// meant for Clone; New and NewClient take them as arguments.
+func WithCredentials(username, password string) Option {
+	return func(ac *ApiClient) {
+		ac.username = username
+		ac.password = password
+		if _, ok := ac.auth.(*digestAuth); ok {
+			ac.auth = &digestAuth{username: username, password: password}
+		}
+	}
+}
+
+// WithTimeout limits the time a request may take, including the retry
+// after a 409, reconnects and failover, also when sent with WithDoer. Zero
+// means no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(ac *ApiClient) {
+		ac.timeout = timeout
+	}
+}
+
+// Clone returns a client for the same daemon with opts applied on top of
+// the options of ac, e.g. to act as another RPC user with WithCredentials.
+// The clone shares the connections, circuit breaker and failover state of
+// ac, and starts with its session id and cached version. It has its own
+// Stats.
+func (ac *TransmissionClient) Clone(opts ...Option) TransmissionClient {
+	api := ac.apiclient
+	api.token = &sessionToken{id: ac.apiclient.token.peek()}
+	api.version = &versionCache{info: ac.apiclient.version.get()}
+	api.stats = newClientStats()
+	api.conn = ac.apiclient.conn.clone()
+	if digest, ok := api.auth.(*digestAuth); ok {
+		// Nonce counts can't be shared.
+		api.auth = &digestAuth{username: digest.username, password: digest.password}
+	}
+	for _, opt := range opts {
+		opt(&api)
+	}
+	return TransmissionClient{apiclient: api}
+}
@@ -0,0 +1,61 @@
+package transmission
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClone(t *testing.T) {
+	var users []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ := r.BasicAuth()
+		users = append(users, user)
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Write([]byte(`{"arguments":{},"result":"success"}`))
+	}))
+	defer server.Close()
+
+	Convey("Test a clone acts as another user", t, func() {
+		transport := &http.Transport{}
+		client := New(server.URL, "admin", "secret", WithTransportOptions(TransportOptions{}))
+		client.apiclient.conn.client.Transport = transport
+		So(client.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+
+		users = nil
+		clone := client.Clone(WithCredentials("alice", "pw"), WithTimeout(time.Second))
+		So(clone.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(users, ShouldResemble, []string{"alice"})
+
+		So(clone.apiclient.conn.client.Transport, ShouldEqual, transport)
+		So(clone.apiclient.timeout, ShouldEqual, time.Second)
+		So(client.apiclient.timeout, ShouldEqual, 0)
+		So(client.apiclient.username, ShouldEqual, "admin")
+		So(clone.Stats().Requests["session-stats"], ShouldEqual, 1)
+		So(client.Stats().Requests["session-stats"], ShouldEqual, 1)
+	})
+
+	Convey("Test a clone doesn't share session id updates", t, func() {
+		client := New(server.URL, "admin", "secret")
+		clone := client.Clone()
+		So(clone.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(clone.apiclient.token.peek(), ShouldEqual, "token")
+		So(client.apiclient.token.peek(), ShouldEqual, "")
+	})
+
+	Convey("Test digest auth follows new credentials", t, func() {
+		client := New(server.URL, "admin", "secret", WithDigestAuth())
+		clone := client.Clone(WithCredentials("alice", "pw"))
+		digest := clone.apiclient.auth.(*digestAuth)
+		So(digest.username, ShouldEqual, "alice")
+		So(digest, ShouldNotEqual, client.apiclient.auth)
+	})
+}
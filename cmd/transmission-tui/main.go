@@ -0,0 +1,110 @@
+// Command transmission-tui is a live terminal dashboard for a Transmission
+// daemon.
+//
+//	transmission-tui -url http://localhost:9091
+//
+// Credentials are read from -user and -password, or from the
+// TRANSMISSION_USER and TRANSMISSION_PASSWORD environment variables.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tubbebubbe/transmission"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:9091", "base URL of the daemon, without /transmission/rpc")
+	user := flag.String("user", os.Getenv("TRANSMISSION_USER"), "RPC username")
+	password := flag.String("password", os.Getenv("TRANSMISSION_PASSWORD"), "RPC password")
+	interval := flag.Duration("interval", 2*time.Second, "refresh interval")
+	flag.Parse()
+
+	client := transmission.New(*url, *user, *password)
+	err := run(&client, *interval)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "transmission-tui:", err)
+		os.Exit(1)
+	}
+}
+
+func run(client *transmission.TransmissionClient, interval time.Duration) error {
+	m := newModel()
+	watcher := transmission.NewWatcher(client)
+	torrents, err := client.GetTorrents()
+	if err != nil {
+		return err
+	}
+	m.reset(torrents)
+	// The first poll only primes the watcher.
+	watcher.Poll()
+
+	term, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer term.close()
+
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	refresh := func() {
+		events, err := watcher.Poll()
+		if err != nil {
+			m.status = "error: " + err.Error()
+			return
+		}
+		if !m.apply(events) {
+			torrents, err := client.GetTorrents()
+			if err != nil {
+				m.status = "error: " + err.Error()
+				return
+			}
+			m.reset(torrents)
+		}
+	}
+
+	for {
+		width, height := term.size()
+		term.draw(m.render(width, height))
+
+		select {
+		case <-ticker.C:
+			refresh()
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			a := m.key(key)
+			if a == nil {
+				continue
+			}
+			if a.Method == "" {
+				return nil
+			}
+			m.status = perform(client, a)
+			refresh()
+		}
+	}
+}
+
+// perform carries out an action on all its torrents with a single request.
+func perform(client *transmission.TransmissionClient, a *action) string {
+	args := map[string]interface{}{"ids": a.Hashes}
+	if a.Method == "torrent-remove" {
+		args["delete-local-data"] = a.DeleteData
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := client.Call(ctx, a.Method, args, nil)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return fmt.Sprintf("%s: %d torrent(s)", a.Method, len(a.Hashes))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tubbebubbe/transmission"
+)
+
+func TestPerform(t *testing.T) {
+	var request struct {
+		Method    string                 `json:"method"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &request)
+		w.Write([]byte(`{"arguments":{},"result":"success"}`))
+	}))
+	defer daemon.Close()
+	client := transmission.New(daemon.URL, "", "")
+
+	Convey("Test actions are sent as one request", t, func() {
+		status := perform(&client, &action{Method: "torrent-remove", Hashes: []string{"aaaa", "bbbb"}, DeleteData: true})
+		So(status, ShouldEqual, "torrent-remove: 2 torrent(s)")
+		So(request.Method, ShouldEqual, "torrent-remove")
+		So(request.Arguments["ids"], ShouldResemble, []interface{}{"aaaa", "bbbb"})
+		So(request.Arguments["delete-local-data"], ShouldEqual, true)
+	})
+}
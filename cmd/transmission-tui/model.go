@@ -0,0 +1,240 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tubbebubbe/transmission"
+)
+
+// sortKey is a column the list can be sorted by.
+type sortKey int
+
+const (
+	sortName sortKey = iota
+	sortProgress
+	sortDownload
+	sortUpload
+	sortAdded
+	sortRatio
+)
+
+var sortNames = []string{"name", "progress", "down", "up", "added", "ratio"}
+
+func (k sortKey) String() string {
+	return sortNames[k]
+}
+
+// action is a request made with a key that the main loop carries out
+// against the daemon.
+type action struct {
+	// Method is the RPC method, or "" to quit.
+	Method     string
+	Hashes     []string
+	DeleteData bool
+}
+
+// model is the state of the dashboard. It knows nothing of the terminal or
+// the daemon, so every key binding can be tested on its own.
+type model struct {
+	torrents map[int]transmission.Torrent
+	sortBy   sortKey
+	reverse  bool
+	filter   string
+	editing  bool
+	cursor   int
+	marked   map[string]bool
+	detail   bool
+	// confirm holds a removal waiting for y.
+	confirm *action
+	status  string
+}
+
+func newModel() *model {
+	return &model{
+		torrents: make(map[int]transmission.Torrent),
+		marked:   make(map[string]bool),
+	}
+}
+
+// reset replaces the torrents, e.g. after a daemon restart.
+func (m *model) reset(torrents transmission.Torrents) {
+	m.torrents = make(map[int]transmission.Torrent, len(torrents))
+	for _, torrent := range torrents {
+		m.torrents[torrent.ID] = torrent
+	}
+	m.clamp()
+}
+
+// apply updates the torrents with events from the watcher. It returns
+// false when the list must be fetched again.
+func (m *model) apply(events []transmission.Event) bool {
+	for _, event := range events {
+		switch event.Type {
+		case transmission.EventAdded, transmission.EventChanged:
+			m.torrents[event.Torrent.ID] = event.Torrent
+		case transmission.EventRemoved:
+			delete(m.torrents, event.Torrent.ID)
+			delete(m.marked, event.Torrent.HashString)
+		case transmission.EventCompleted:
+			m.status = "completed: " + event.Torrent.Name
+		case transmission.EventDaemonRestarted:
+			return false
+		}
+	}
+	m.clamp()
+	return true
+}
+
+// visible returns the torrents passing the filter, sorted.
+func (m *model) visible() transmission.Torrents {
+	filter := strings.ToLower(m.filter)
+	list := make(transmission.Torrents, 0, len(m.torrents))
+	for _, torrent := range m.torrents {
+		if filter == "" || strings.Contains(strings.ToLower(torrent.Name), filter) || torrent.HasLabel(m.filter) {
+			list = append(list, torrent)
+		}
+	}
+
+	less := func(a, b transmission.Torrent) bool {
+		switch m.sortBy {
+		case sortProgress:
+			return a.PercentDone < b.PercentDone
+		case sortDownload:
+			return a.RateDownload < b.RateDownload
+		case sortUpload:
+			return a.RateUpload < b.RateUpload
+		case sortAdded:
+			return a.AddedDate < b.AddedDate
+		case sortRatio:
+			return a.UploadRatio < b.UploadRatio
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if m.reverse {
+			a, b = b, a
+		}
+		if less(a, b) != less(b, a) {
+			return less(a, b)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// selected returns the torrent under the cursor.
+func (m *model) selected() (transmission.Torrent, bool) {
+	list := m.visible()
+	if m.cursor < 0 || m.cursor >= len(list) {
+		return transmission.Torrent{}, false
+	}
+	return list[m.cursor], true
+}
+
+// targets returns the hashes an action applies to: the marked torrents, or
+// the one under the cursor if none are marked.
+func (m *model) targets() []string {
+	if len(m.marked) > 0 {
+		hashes := make([]string, 0, len(m.marked))
+		for hash := range m.marked {
+			hashes = append(hashes, hash)
+		}
+		sort.Strings(hashes)
+		return hashes
+	}
+	if torrent, ok := m.selected(); ok {
+		return []string{torrent.HashString}
+	}
+	return nil
+}
+
+func (m *model) clamp() {
+	n := len(m.visible())
+	if m.cursor >= n {
+		m.cursor = n - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// key handles a key press and returns the action it asks for, if any.
+func (m *model) key(k string) *action {
+	if m.editing {
+		switch k {
+		case "enter", "esc":
+			m.editing = false
+		case "backspace":
+			if m.filter != "" {
+				m.filter = m.filter[:len(m.filter)-1]
+			}
+		default:
+			if len(k) == 1 {
+				m.filter += k
+			}
+		}
+		m.cursor = 0
+		return nil
+	}
+
+	if m.confirm != nil {
+		pending := m.confirm
+		m.confirm = nil
+		if k == "y" {
+			m.marked = make(map[string]bool)
+			return pending
+		}
+		m.status = "cancelled"
+		return nil
+	}
+
+	switch k {
+	case "q":
+		return &action{}
+	case "j", "down":
+		m.cursor++
+	case "k", "up":
+		m.cursor--
+	case "g":
+		m.cursor = 0
+	case "G":
+		m.cursor = len(m.visible()) - 1
+	case "s":
+		m.sortBy = (m.sortBy + 1) % sortKey(len(sortNames))
+	case "r":
+		m.reverse = !m.reverse
+	case "/":
+		m.editing = true
+		m.filter = ""
+	case "esc":
+		m.filter = ""
+		m.marked = make(map[string]bool)
+	case "enter":
+		m.detail = !m.detail
+	case " ":
+		if torrent, ok := m.selected(); ok {
+			if m.marked[torrent.HashString] {
+				delete(m.marked, torrent.HashString)
+			} else {
+				m.marked[torrent.HashString] = true
+			}
+			m.cursor++
+		}
+	case "S", "p":
+		method := "torrent-start"
+		if k == "p" {
+			method = "torrent-stop"
+		}
+		if hashes := m.targets(); len(hashes) > 0 {
+			return &action{Method: method, Hashes: hashes}
+		}
+	case "d", "D":
+		if hashes := m.targets(); len(hashes) > 0 {
+			m.confirm = &action{Method: "torrent-remove", Hashes: hashes, DeleteData: k == "D"}
+		}
+	}
+	m.clamp()
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tubbebubbe/transmission"
+)
+
+func tModel() *model {
+	m := newModel()
+	m.reset(transmission.Torrents{
+		{ID: 1, Name: "Bravo", HashString: "bbbb", PercentDone: 0.5, RateDownload: 300},
+		{ID: 2, Name: "alpha", HashString: "aaaa", PercentDone: 1, Labels: []string{"tv"}},
+		{ID: 3, Name: "Charlie", HashString: "cccc", PercentDone: 0.1, RateDownload: 900},
+	})
+	return m
+}
+
+func tNames(torrents transmission.Torrents) []string {
+	names := make([]string, len(torrents))
+	for i, torrent := range torrents {
+		names[i] = torrent.Name
+	}
+	return names
+}
+
+func TestModelSortAndFilter(t *testing.T) {
+	Convey("Test the list sorts by name by default", t, func() {
+		m := tModel()
+		So(tNames(m.visible()), ShouldResemble, []string{"alpha", "Bravo", "Charlie"})
+	})
+
+	Convey("Test cycling and reversing the sort", t, func() {
+		m := tModel()
+		m.key("s")
+		So(m.sortBy, ShouldEqual, sortProgress)
+		So(tNames(m.visible()), ShouldResemble, []string{"Charlie", "Bravo", "alpha"})
+		m.key("s")
+		m.key("r")
+		So(tNames(m.visible()), ShouldResemble, []string{"Charlie", "Bravo", "alpha"})
+	})
+
+	Convey("Test filtering by name or label", t, func() {
+		m := tModel()
+		for _, k := range []string{"/", "a", "r", "backspace", "enter"} {
+			m.key(k)
+		}
+		So(m.filter, ShouldEqual, "a")
+		So(m.editing, ShouldBeFalse)
+		So(tNames(m.visible()), ShouldResemble, []string{"alpha", "Bravo", "Charlie"})
+
+		m.filter = "tv"
+		So(tNames(m.visible()), ShouldResemble, []string{"alpha"})
+		m.key("esc")
+		So(m.visible(), ShouldHaveLength, 3)
+	})
+}
+
+func TestModelKeys(t *testing.T) {
+	Convey("Test the cursor stays on the list", t, func() {
+		m := tModel()
+		m.key("k")
+		So(m.cursor, ShouldEqual, 0)
+		m.key("G")
+		m.key("j")
+		So(m.cursor, ShouldEqual, 2)
+		selected, _ := m.selected()
+		So(selected.Name, ShouldEqual, "Charlie")
+	})
+
+	Convey("Test actions apply to the selected or marked torrents", t, func() {
+		m := tModel()
+		a := m.key("p")
+		So(a, ShouldResemble, &action{Method: "torrent-stop", Hashes: []string{"aaaa"}})
+
+		m.key(" ")
+		m.key(" ")
+		So(m.cursor, ShouldEqual, 2)
+		a = m.key("S")
+		So(a, ShouldResemble, &action{Method: "torrent-start", Hashes: []string{"aaaa", "bbbb"}})
+	})
+
+	Convey("Test removal waits for confirmation", t, func() {
+		m := tModel()
+		So(m.key("D"), ShouldBeNil)
+		So(m.footer(), ShouldContainSubstring, "remove with data 1 torrent(s)?")
+		So(m.key("n"), ShouldBeNil)
+		So(m.status, ShouldEqual, "cancelled")
+
+		m.key("d")
+		So(m.key("y"), ShouldResemble, &action{Method: "torrent-remove", Hashes: []string{"aaaa"}})
+	})
+
+	Convey("Test q quits", t, func() {
+		So(tModel().key("q"), ShouldResemble, &action{})
+	})
+}
+
+func TestModelApply(t *testing.T) {
+	Convey("Test events update the torrents", t, func() {
+		m := tModel()
+		m.cursor = 2
+		So(m.apply([]transmission.Event{
+			{Type: transmission.EventRemoved, Torrent: transmission.Torrent{ID: 3}},
+			{Type: transmission.EventChanged, Torrent: transmission.Torrent{ID: 1, Name: "Bravo", PercentDone: 1}},
+			{Type: transmission.EventCompleted, Torrent: transmission.Torrent{ID: 1, Name: "Bravo"}},
+		}), ShouldBeTrue)
+		So(tNames(m.visible()), ShouldResemble, []string{"alpha", "Bravo"})
+		So(m.cursor, ShouldEqual, 1)
+		So(m.status, ShouldEqual, "completed: Bravo")
+	})
+
+	Convey("Test a daemon restart asks for a new list", t, func() {
+		m := tModel()
+		So(m.apply([]transmission.Event{{Type: transmission.EventDaemonRestarted}}), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tubbebubbe/transmission"
+)
+
+var statusNames = map[int]string{
+	transmission.StatusPaused:       "Paused",
+	transmission.StatusWait:         "Check wait",
+	transmission.StatusCheck:        "Checking",
+	transmission.StatusDownloadWait: "Queued",
+	transmission.StatusDownload:     "Downloading",
+	transmission.StatisSeedWait:     "Seed wait",
+	transmission.StatusSeed:         "Seeding",
+}
+
+const (
+	reverseVideo = "\x1b[7m"
+	bold         = "\x1b[1m"
+	resetStyle   = "\x1b[0m"
+)
+
+// render draws the model into a screen of width by height cells.
+func (m *model) render(width, height int) string {
+	list := m.visible()
+	var lines []string
+
+	totals := list.Totals()
+	order := m.sortBy.String()
+	if m.reverse {
+		order += " desc"
+	}
+	lines = append(lines, bold+fit(fmt.Sprintf("%d torrents  ↓ %v  ↑ %v  sort: %s",
+		totals.Count, totals.RateDownload, totals.RateUpload, order), width)+resetStyle)
+	lines = append(lines, fit(row(" ", "Name", "Done", "Status", "Down", "Up", "ETA", "Ratio", width), width))
+
+	var details []string
+	if m.detail {
+		if torrent, ok := m.selected(); ok {
+			details = detailLines(torrent)
+		}
+	}
+
+	rows := height - len(lines) - len(details) - 1
+	if rows < 1 {
+		rows = 1
+	}
+	first := 0
+	if m.cursor >= rows {
+		first = m.cursor - rows + 1
+	}
+	for i := first; i < len(list) && i < first+rows; i++ {
+		torrent := list[i]
+		mark := " "
+		if m.marked[torrent.HashString] {
+			mark = "*"
+		}
+		line := fit(row(mark, torrent.Name,
+			fmt.Sprintf("%.0f%%", torrent.PercentDone*100),
+			statusName(torrent),
+			torrent.RateDownload.String(),
+			torrent.RateUpload.String(),
+			formatETA(torrent.Eta),
+			fmt.Sprintf("%.2f", torrent.UploadRatio), width), width)
+		if i == m.cursor {
+			line = reverseVideo + line + resetStyle
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < height-len(details)-1 {
+		lines = append(lines, "")
+	}
+
+	for _, line := range details {
+		lines = append(lines, fit(line, width))
+	}
+	lines = append(lines, fit(m.footer(), width))
+	return strings.Join(lines, "\r\n")
+}
+
+func (m *model) footer() string {
+	switch {
+	case m.editing:
+		return "filter: " + m.filter + "_"
+	case m.confirm != nil:
+		verb := "remove"
+		if m.confirm.DeleteData {
+			verb = "remove with data"
+		}
+		return fmt.Sprintf("%s %d torrent(s)? [y/N]", verb, len(m.confirm.Hashes))
+	case m.status != "":
+		return m.status
+	}
+	return "j/k move  space mark  enter info  / filter  s/r sort  S start  p pause  d/D remove  q quit"
+}
+
+func detailLines(t transmission.Torrent) []string {
+	lines := []string{
+		strings.Repeat("─", 20),
+		"Name:     " + t.Name,
+		"Hash:     " + t.HashString,
+		"Location: " + t.DownloadDir,
+		fmt.Sprintf("Size:     %v of %v, %v left", t.SizeWhenDone-t.Remaining(), t.SizeWhenDone, t.Remaining()),
+		fmt.Sprintf("Ratio:    %.2f (%v uploaded)", t.UploadRatio, t.UploadedEver),
+		"Labels:   " + strings.Join(t.Labels, ", "),
+	}
+	for _, stat := range t.TrackerStats {
+		lines = append(lines, "Tracker:  "+transmission.TrackerIdentity(stat.Announce))
+	}
+	if t.ErrorString != "" {
+		lines = append(lines, "Error:    "+t.ErrorString)
+	}
+	return lines
+}
+
+func statusName(t transmission.Torrent) string {
+	if t.Error != 0 {
+		return "Error"
+	}
+	return statusNames[t.Status]
+}
+
+// formatETA formats the eta field, which is -1 when not downloading and -2
+// when it can't be estimated.
+func formatETA(seconds int) string {
+	switch {
+	case seconds == -1:
+		return ""
+	case seconds < 0:
+		return "∞"
+	}
+	eta := time.Duration(seconds) * time.Second
+	switch {
+	case eta >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", eta/(24*time.Hour), eta%(24*time.Hour)/time.Hour)
+	case eta >= time.Hour:
+		return fmt.Sprintf("%dh%dm", eta/time.Hour, eta%time.Hour/time.Minute)
+	}
+	return fmt.Sprintf("%dm%ds", eta/time.Minute, eta%time.Minute/time.Second)
+}
+
+// row lays out a list line, giving the name whatever width is left.
+func row(mark, name, done, status, down, up, eta, ratio string, width int) string {
+	rest := fmt.Sprintf(" %5s %-11s %11s %11s %7s %6s", done, status, down, up, eta, ratio)
+	nameWidth := width - 2 - utf8.RuneCountInString(rest)
+	if nameWidth < 10 {
+		nameWidth = 10
+	}
+	return mark + " " + pad(name, nameWidth) + rest
+}
+
+// pad cuts or pads s to exactly n runes.
+func pad(s string, n int) string {
+	count := utf8.RuneCountInString(s)
+	if count > n {
+		runes := []rune(s)
+		return string(runes[:n-1]) + "…"
+	}
+	return s + strings.Repeat(" ", n-count)
+}
+
+// fit cuts s to the screen width.
+func fit(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRender(t *testing.T) {
+	Convey("Test the screen fills the terminal", t, func() {
+		m := tModel()
+		lines := strings.Split(m.render(100, 10), "\r\n")
+		So(lines, ShouldHaveLength, 10)
+		So(lines[0], ShouldContainSubstring, "3 torrents")
+		So(lines[0], ShouldContainSubstring, "↓ 1.2 kB/s")
+		So(lines[2], ShouldStartWith, reverseVideo)
+		So(lines[2], ShouldContainSubstring, "alpha")
+		So(lines[9], ShouldContainSubstring, "q quit")
+	})
+
+	Convey("Test the detail pane shows the selected torrent", t, func() {
+		m := tModel()
+		m.key("enter")
+		screen := m.render(100, 20)
+		So(screen, ShouldContainSubstring, "Hash:     aaaa")
+		So(screen, ShouldContainSubstring, "Labels:   tv")
+	})
+
+	Convey("Test long names are cut to the width", t, func() {
+		So(pad("abcdef", 4), ShouldEqual, "abc…")
+		So(pad("ab", 4), ShouldEqual, "ab  ")
+		line := row(" ", strings.Repeat("x", 200), "50%", "Seeding", "", "", "", "1.00", 80)
+		So(utf8.RuneCountInString(line), ShouldEqual, 80)
+	})
+
+	Convey("Test formatting the ETA", t, func() {
+		So(formatETA(-1), ShouldEqual, "")
+		So(formatETA(-2), ShouldEqual, "∞")
+		So(formatETA(90), ShouldEqual, "1m30s")
+		So(formatETA(3*3600+120), ShouldEqual, "3h2m")
+		So(formatETA(2*86400+3600), ShouldEqual, "2d1h")
+	})
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// terminal puts the controlling terminal in raw mode on the alternate
+// screen. It shells out to stty so the command needs nothing but the
+// standard library.
+type terminal struct {
+	in    *os.File
+	out   io.Writer
+	saved string
+}
+
+func openTerminal() (*terminal, error) {
+	t := &terminal{in: os.Stdin, out: os.Stdout}
+	saved, err := t.stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("not a terminal: %v", err)
+	}
+	t.saved = strings.TrimSpace(saved)
+	_, err = t.stty("raw", "-echo")
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprint(t.out, "\x1b[?1049h\x1b[?25l")
+	return t, nil
+}
+
+func (t *terminal) stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = t.in
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// size returns the width and height of the terminal.
+func (t *terminal) size() (int, int) {
+	out, err := t.stty("size")
+	var height, width int
+	if err == nil {
+		fmt.Sscan(out, &height, &width)
+	}
+	if width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+func (t *terminal) draw(screen string) {
+	fmt.Fprint(t.out, "\x1b[H\x1b[2J"+screen)
+}
+
+func (t *terminal) close() {
+	fmt.Fprint(t.out, "\x1b[?25h\x1b[?1049l")
+	t.stty(t.saved)
+}
+
+// readKeys sends the keys read from r until it fails.
+func readKeys(r io.Reader, keys chan<- string) {
+	buf := make([]byte, 32)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+	}
+}
+
+var escapes = map[string]string{
+	"\x1b[A": "up",
+	"\x1b[B": "down",
+	"\x1bOA": "up",
+	"\x1bOB": "down",
+}
+
+// parseKeys splits raw terminal input into key names.
+func parseKeys(input []byte) []string {
+	var keys []string
+	s := string(input)
+	for s != "" {
+		if s[0] == 0x1b {
+			if len(s) >= 3 {
+				if key, ok := escapes[s[:3]]; ok {
+					keys = append(keys, key)
+					s = s[3:]
+					continue
+				}
+			}
+			keys = append(keys, "esc")
+			s = s[1:]
+			continue
+		}
+		switch s[0] {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			// Ctrl-C doesn't interrupt in raw mode.
+			keys = append(keys, "q")
+		default:
+			keys = append(keys, s[:1])
+		}
+		s = s[1:]
+	}
+	return keys
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseKeys(t *testing.T) {
+	Convey("Test raw input is split into keys", t, func() {
+		So(parseKeys([]byte("jk")), ShouldResemble, []string{"j", "k"})
+		So(parseKeys([]byte("\x1b[A\x1b[Bq")), ShouldResemble, []string{"up", "down", "q"})
+		So(parseKeys([]byte("\x1b")), ShouldResemble, []string{"esc"})
+		So(parseKeys([]byte("\r\x7f\x03")), ShouldResemble, []string{"enter", "backspace", "q"})
+	})
+}
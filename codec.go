@@ -0,0 +1,45 @@
+package transmission
+
+import "encoding/json"
+
+// JSONCodec encodes the requests and decodes the responses of a client.
+// Implementations must honour json.Marshaler and json.Unmarshaler, which
+// several types of this package rely on; drop-in replacements of
+// encoding/json such as jsoniter and sonic do.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// stdCodec is the default JSONCodec, backed by encoding/json.
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithJSONCodec replaces encoding/json for the requests and responses of
+// the client.
+//
+// The codec only sees the request and the response as a whole. Torrent and
+// Session convert seconds and KB/s in their own MarshalJSON and
+// UnmarshalJSON, which use encoding/json, so the torrents of a torrent-get
+// response and the settings of a session are still decoded by encoding/json
+// even with a faster codec.
+func WithJSONCodec(codec JSONCodec) Option {
+	return func(ac *ApiClient) {
+		ac.codec = codec
+	}
+}
+
+// jsonCodec returns the codec of the client, falling back to encoding/json.
+func (ac *ApiClient) jsonCodec() JSONCodec {
+	if ac.codec == nil {
+		return stdCodec{}
+	}
+	return ac.codec
+}
@@ -0,0 +1,80 @@
+package transmission
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// tCodec counts the calls it passes on to encoding/json.
+type tCodec struct {
+	marshals, unmarshals int
+	err                  error
+}
+
+func (c *tCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return json.Marshal(v)
+}
+
+func (c *tCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestJSONCodec(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"name":"a"}],"download-dir":"/downloads"},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test requests and responses go through the codec", t, func() {
+		codec := &tCodec{}
+		client := New(tServer.URL, "test", "test", WithJSONCodec(codec))
+
+		torrents, err := client.GetTorrents()
+		So(err, ShouldBeNil)
+		So(torrents[0].Name, ShouldEqual, "a")
+		So(codec.marshals, ShouldEqual, 1)
+		So(codec.unmarshals, ShouldEqual, 1)
+
+		session, err := client.GetSession()
+		So(err, ShouldBeNil)
+		So(session.DownloadDir, ShouldEqual, "/downloads")
+		So(codec.marshals, ShouldEqual, 2)
+		So(codec.unmarshals, ShouldEqual, 3)
+	})
+
+	Convey("Test the codec decodes a torrent-get response once", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"arguments":{"torrents":[{"id":1,"name":"a"},{"id":2,"name":"b"},` +
+				`{"id":3,"name":"c"}]},"result":"success"}`))
+		}))
+		defer server.Close()
+		codec := &tCodec{}
+		client := New(server.URL, "", "", WithJSONCodec(codec))
+
+		torrents, err := client.GetTorrents()
+		So(err, ShouldBeNil)
+		So(len(torrents), ShouldEqual, 3)
+		// The torrents themselves are decoded by Torrent.UnmarshalJSON.
+		So(codec.unmarshals, ShouldEqual, 1)
+	})
+
+	Convey("Test codec errors are returned", t, func() {
+		failure := errors.New("codec failure")
+		client := New(tServer.URL, "test", "test", WithJSONCodec(&tCodec{err: failure}))
+
+		_, err := client.GetTorrents()
+		So(err, ShouldEqual, failure)
+	})
+
+	Convey("Test encoding/json is the default", t, func() {
+		So(transmissionClient.apiclient.jsonCodec(), ShouldHaveSameTypeAs, stdCodec{})
+	})
+}
@@ -0,0 +1,190 @@
+package transmission
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CreateTorrentOptions configures CreateTorrent.
+type CreateTorrentOptions struct {
+	// Trackers are the announce URLs in tiers, as for SetTorrentTrackers.
+	Trackers [][]string
+	// WebSeeds are HTTP URLs serving the same content.
+	WebSeeds []string
+	// PieceSize defaults to a power of two between 16 KiB and 16 MiB that
+	// keeps the number of pieces around 1500.
+	PieceSize ByteSize
+	Private   bool
+	Comment   string
+	Creator   string
+}
+
+// CreateTorrent builds the metainfo of a .torrent for the file or
+// directory at path. Files in a directory are added in lexical order.
+func CreateTorrent(path string, opts CreateTorrentOptions) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		path   string
+		parts  []string
+		length int64
+	}
+	var entries []entry
+	if info.IsDir() {
+		err = filepath.Walk(path, func(name string, fi os.FileInfo, err error) error {
+			if err != nil || !fi.Mode().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(path, name)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{name, strings.Split(filepath.ToSlash(rel), "/"), fi.Size()})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return nil, errors.New("no files in " + path)
+		}
+	} else {
+		entries = []entry{{path, nil, info.Size()}}
+	}
+
+	var total int64
+	for _, e := range entries {
+		total += e.length
+	}
+	pieceSize := opts.PieceSize
+	if pieceSize <= 0 {
+		pieceSize = defaultPieceSize(ByteSize(total))
+	}
+
+	pieces := &pieceHasher{size: int64(pieceSize), hash: sha1.New()}
+	for _, e := range entries {
+		err = hashFile(pieces, e.path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	infoDict := map[string]interface{}{
+		"name":         filepath.Base(filepath.Clean(path)),
+		"piece length": int64(pieceSize),
+		"pieces":       pieces.sum(),
+	}
+	if opts.Private {
+		infoDict["private"] = 1
+	}
+	if info.IsDir() {
+		files := make([]interface{}, len(entries))
+		for i, e := range entries {
+			files[i] = map[string]interface{}{"length": e.length, "path": e.parts}
+		}
+		infoDict["files"] = files
+	} else {
+		infoDict["length"] = total
+	}
+
+	meta := map[string]interface{}{
+		"info":          infoDict,
+		"creation date": time.Now().Unix(),
+	}
+	if len(opts.Trackers) > 0 && len(opts.Trackers[0]) > 0 {
+		meta["announce"] = opts.Trackers[0][0]
+		tiers := make([]interface{}, len(opts.Trackers))
+		for i, tier := range opts.Trackers {
+			tiers[i] = tier
+		}
+		meta["announce-list"] = tiers
+	}
+	if len(opts.WebSeeds) > 0 {
+		meta["url-list"] = opts.WebSeeds
+	}
+	if opts.Comment != "" {
+		meta["comment"] = opts.Comment
+	}
+	if opts.Creator != "" {
+		meta["created by"] = opts.Creator
+	}
+	return encodeBencode(meta)
+}
+
+// AddCreatedTorrent add a torrent from metainfo returned by CreateTorrent
+// or read from a .torrent file.
+func (ac *TransmissionClient) AddCreatedTorrent(metainfo []byte, opts AddTorrentOptions) (AddResult, error) {
+	cmd, _ := NewAddCmd()
+	cmd.Arguments.MetaInfo = base64.StdEncoding.EncodeToString(metainfo)
+	err := ac.applyAddOptions(cmd, opts)
+	if err != nil {
+		return AddResult{}, err
+	}
+	return ac.ExecuteAddCommand(cmd)
+}
+
+// defaultPieceSize picks a piece size for content of total bytes.
+func defaultPieceSize(total ByteSize) ByteSize {
+	size := 16 * KiB
+	for size < 16*MiB && total/size > 1500 {
+		size *= 2
+	}
+	return size
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// pieceHasher hashes everything written to it in pieces of size bytes.
+type pieceHasher struct {
+	size   int64
+	filled int64
+	hash   hash.Hash
+	sums   []byte
+}
+
+func (p *pieceHasher) Write(b []byte) (int, error) {
+	n := len(b)
+	for len(b) > 0 {
+		chunk := p.size - p.filled
+		if int64(len(b)) < chunk {
+			chunk = int64(len(b))
+		}
+		p.hash.Write(b[:chunk])
+		p.filled += chunk
+		b = b[chunk:]
+		if p.filled == p.size {
+			p.sums = p.hash.Sum(p.sums)
+			p.hash.Reset()
+			p.filled = 0
+		}
+	}
+	return n, nil
+}
+
+// sum returns the concatenated piece hashes, including a final short piece.
+func (p *pieceHasher) sum() []byte {
+	if p.filled > 0 {
+		p.sums = p.hash.Sum(p.sums)
+		p.hash.Reset()
+		p.filled = 0
+	}
+	return p.sums
+}
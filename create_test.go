@@ -0,0 +1,90 @@
+package transmission
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateTorrent(t *testing.T) {
+	dir := t.TempDir()
+	content := filepath.Join(dir, "album")
+	os.MkdirAll(filepath.Join(content, "disc2"), 0755)
+	a := bytes.Repeat([]byte("a"), 20000)
+	b := bytes.Repeat([]byte("b"), 15000)
+	os.WriteFile(filepath.Join(content, "01.flac"), a, 0644)
+	os.WriteFile(filepath.Join(content, "disc2", "01.flac"), b, 0644)
+
+	Convey("Test creating a torrent for a directory", t, func() {
+		data, err := CreateTorrent(content, CreateTorrentOptions{
+			Trackers: [][]string{{"https://tracker.example.com/announce"}, {"udp://backup.example.com:6969"}},
+			Private:  true,
+			Comment:  "test",
+		})
+		So(err, ShouldBeNil)
+
+		v, err := decodeBencode(data)
+		So(err, ShouldBeNil)
+		meta := v.(map[string]interface{})
+		So(meta["announce"], ShouldEqual, "https://tracker.example.com/announce")
+		So(meta["announce-list"], ShouldHaveLength, 2)
+		So(meta["comment"], ShouldEqual, "test")
+
+		info := meta["info"].(map[string]interface{})
+		So(info["name"], ShouldEqual, "album")
+		So(info["private"], ShouldEqual, 1)
+		So(info["piece length"], ShouldEqual, 16*1024)
+		So(info["files"], ShouldResemble, []interface{}{
+			map[string]interface{}{"length": int64(20000), "path": []interface{}{"01.flac"}},
+			map[string]interface{}{"length": int64(15000), "path": []interface{}{"disc2", "01.flac"}},
+		})
+
+		// 35000 bytes make two full pieces and a short one.
+		all := append(append([]byte{}, a...), b...)
+		first := sha1.Sum(all[:16384])
+		last := sha1.Sum(all[32768:])
+		pieces := info["pieces"].(string)
+		So(pieces, ShouldHaveLength, 3*sha1.Size)
+		So(pieces[:sha1.Size], ShouldEqual, string(first[:]))
+		So(pieces[2*sha1.Size:], ShouldEqual, string(last[:]))
+	})
+
+	Convey("Test creating a torrent for a single file", t, func() {
+		data, err := CreateTorrent(filepath.Join(content, "01.flac"), CreateTorrentOptions{PieceSize: 32 * KiB})
+		So(err, ShouldBeNil)
+		v, _ := decodeBencode(data)
+		info := v.(map[string]interface{})["info"].(map[string]interface{})
+		So(info["length"], ShouldEqual, 20000)
+		So(info["pieces"], ShouldHaveLength, sha1.Size)
+		So(info, ShouldNotContainKey, "private")
+	})
+
+	Convey("Test empty directories and missing paths fail", t, func() {
+		_, err := CreateTorrent(t.TempDir(), CreateTorrentOptions{})
+		So(err, ShouldNotBeNil)
+		_, err = CreateTorrent(filepath.Join(dir, "missing"), CreateTorrentOptions{})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test the default piece size", t, func() {
+		So(defaultPieceSize(0), ShouldEqual, 16*KiB)
+		So(defaultPieceSize(4*GiB), ShouldEqual, 4*MiB)
+		So(defaultPieceSize(1024*GiB), ShouldEqual, 16*MiB)
+	})
+}
+
+func TestAddCreatedTorrent(t *testing.T) {
+	tSetup(`{"arguments":{"torrent-added":{"hashString":"875a2d90068c32b4",
+  "id":3,"name":"album"}},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test adding created metainfo", t, func() {
+		added, err := transmissionClient.AddCreatedTorrent([]byte("d4:infod4:name5:albumee"), AddTorrentOptions{Paused: true})
+		So(err, ShouldBeNil)
+		So(added.Torrent.ID, ShouldEqual, 3)
+	})
+}
@@ -0,0 +1,146 @@
+package transmission
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultURL is used when no URL is configured.
+const DefaultURL = "http://127.0.0.1:9091"
+
+// NewFromEnv create a new client from the environment. The URL is read from
+// TRANSMISSION_URL (defaulting to DefaultURL) and the credentials from
+// TRANSMISSION_USERNAME and TRANSMISSION_PASSWORD, or from TR_AUTH in the
+// "username:password" form used by transmission-remote.
+func NewFromEnv(opts ...Option) TransmissionClient {
+	rawURL := os.Getenv("TRANSMISSION_URL")
+	if rawURL == "" {
+		rawURL = DefaultURL
+	}
+
+	username := os.Getenv("TRANSMISSION_USERNAME")
+	password := os.Getenv("TRANSMISSION_PASSWORD")
+	if username == "" && password == "" {
+		if auth := os.Getenv("TR_AUTH"); auth != "" {
+			username, password = splitAuth(auth)
+		}
+	}
+
+	return New(rawURL, username, password, opts...)
+}
+
+// NewFromNetrc create a new client for rawURL with the credentials of the
+// matching machine entry in the netrc file. The file is $NETRC if set and
+// ~/.netrc otherwise. A "default" entry is used when no machine matches.
+func NewFromNetrc(rawURL string, opts ...Option) (TransmissionClient, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return TransmissionClient{}, err
+	}
+
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return TransmissionClient{}, err
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return TransmissionClient{}, err
+	}
+	defer file.Close()
+
+	username, password, ok := lookupNetrc(file, u.Hostname())
+	if !ok {
+		return TransmissionClient{}, errors.New("no netrc entry for " + u.Hostname())
+	}
+
+	return New(rawURL, username, password, opts...), nil
+}
+
+// splitAuth splits "username:password".
+func splitAuth(auth string) (string, string) {
+	parts := strings.SplitN(auth, ":", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// lookupNetrc returns the login and password for machine from a netrc
+// file, falling back to the default entry.
+func lookupNetrc(r io.Reader, machine string) (string, string, bool) {
+	type entry struct {
+		login, password string
+	}
+	var (
+		found, fallback *entry
+		current         *entry
+		inMacro         bool
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			// Macro definitions run until the next empty line.
+			if strings.TrimSpace(line) == "" {
+				inMacro = false
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			next := func() string {
+				if i+1 < len(fields) {
+					i++
+					return fields[i]
+				}
+				return ""
+			}
+
+			switch fields[i] {
+			case "machine":
+				current = &entry{}
+				if next() == machine && found == nil {
+					found = current
+				}
+			case "default":
+				current = &entry{}
+				if fallback == nil {
+					fallback = current
+				}
+			case "login":
+				if current != nil {
+					current.login = next()
+				}
+			case "password":
+				if current != nil {
+					current.password = next()
+				}
+			case "account":
+				next()
+			case "macdef":
+				inMacro = true
+				i = len(fields)
+			}
+		}
+	}
+
+	if found == nil {
+		found = fallback
+	}
+	if found == nil {
+		return "", "", false
+	}
+	return found.login, found.password, true
+}
@@ -0,0 +1,78 @@
+package transmission
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testNetrc = `machine example.com login other password secret
+macdef init
+cd /pub
+
+machine seedbox.example.com
+  login test
+  password test
+default login anonymous password guest
+`
+
+func TestLookupNetrc(t *testing.T) {
+	Convey("Test looking up netrc entries", t, func() {
+		login, password, ok := lookupNetrc(strings.NewReader(testNetrc), "seedbox.example.com")
+		So(ok, ShouldBeTrue)
+		So(login, ShouldEqual, "test")
+		So(password, ShouldEqual, "test")
+
+		login, password, ok = lookupNetrc(strings.NewReader(testNetrc), "unknown.example.com")
+		So(ok, ShouldBeTrue)
+		So(login, ShouldEqual, "anonymous")
+		So(password, ShouldEqual, "guest")
+
+		_, _, ok = lookupNetrc(strings.NewReader("machine a login b"), "c")
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestNewFromNetrc(t *testing.T) {
+	tSetup(`{"arguments":{},"result":"success"}`)
+	defer tTeardown()
+
+	dir, err := ioutil.TempDir("", "netrc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".netrc")
+	ioutil.WriteFile(path, []byte("machine 127.0.0.1 login test password test\n"), 0600)
+	t.Setenv("NETRC", path)
+
+	Convey("Test creating a client from netrc", t, func() {
+		client, err := NewFromNetrc(tServer.URL)
+		So(err, ShouldBeNil)
+		So(client.Ping(context.Background()), ShouldBeNil)
+
+		_, err = NewFromNetrc("http://unknown.example.com:9091")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestNewFromEnv(t *testing.T) {
+	tSetup(`{"arguments":{},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test creating a client from the environment", t, func() {
+		t.Setenv("TRANSMISSION_URL", tServer.URL)
+		t.Setenv("TR_AUTH", "test:test")
+
+		client := NewFromEnv()
+		So(client.apiclient.username, ShouldEqual, "test")
+		So(client.apiclient.password, ShouldEqual, "test")
+		_, err := client.GetTorrents()
+		So(err, ShouldBeNil)
+	})
+}
@@ -0,0 +1,14 @@
+package transmission
+
+import "context"
+
+// MetainfoFields are the static .torrent fields that aren't requested by
+// default. Add them with Command.AddFields or use GetTorrentDetails.
+var MetainfoFields = []string{"creator", "comment", "dateCreated", "isPrivate"}
+
+// GetTorrentDetails get a torrent with the default fields plus
+// MetainfoFields
+func (ac *TransmissionClient) GetTorrentDetails(id int) (Torrent, error) {
+	fields := append(append([]string(nil), torrentGetFields...), MetainfoFields...)
+	return ac.fetchTorrent(context.Background(), fields, IDs(id))
+}
@@ -0,0 +1,39 @@
+package transmission
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetTorrentDetails(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"creator":"mktorrent 1.1",
+  "comment":"Test comment","dateCreated":1500000000,"isPrivate":true}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting the metainfo fields", t, func() {
+		cmd, _ := NewGetTorrentsCmd()
+		So(cmd.Arguments.Fields, ShouldNotContain, "creator")
+
+		torrent, err := transmissionClient.GetTorrentDetails(1)
+		So(err, ShouldBeNil)
+		So(torrent.Creator, ShouldEqual, "mktorrent 1.1")
+		So(torrent.Comment, ShouldEqual, "Test comment")
+		So(torrent.DateCreated.Equal(time.Unix(1500000000, 0)), ShouldBeTrue)
+		So(torrent.IsPrivate, ShouldBeTrue)
+	})
+}
+
+func TestGetTorrentDetailsUnsetDate(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"dateCreated":0}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test an unset creation date is the zero time", t, func() {
+		torrent, err := transmissionClient.GetTorrentDetails(1)
+		So(err, ShouldBeNil)
+		So(torrent.DateCreated.IsZero(), ShouldBeTrue)
+	})
+}
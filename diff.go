@@ -0,0 +1,68 @@
+package transmission
+
+import "reflect"
+
+// TorrentChange is a torrent whose state changed between two snapshots.
+type TorrentChange struct {
+	Torrent Torrent
+	// Fields holds the names of the Torrent fields that changed.
+	Fields []string
+}
+
+// TorrentsDiff describes how a list of torrents changed.
+type TorrentsDiff struct {
+	Added   Torrents
+	Removed Torrents
+	Changed []TorrentChange
+}
+
+// Empty reports whether nothing changed.
+func (d TorrentsDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares t with an earlier snapshot of the same torrents. Torrents
+// are matched by ID. Fields that weren't requested in both snapshots
+// compare equal as long as both are left at their zero value.
+func (t Torrents) Diff(previous Torrents) TorrentsDiff {
+	before := make(map[int]Torrent, len(previous))
+	for _, torrent := range previous {
+		before[torrent.ID] = torrent
+	}
+
+	var diff TorrentsDiff
+	seen := make(map[int]bool, len(t))
+	for _, torrent := range t {
+		seen[torrent.ID] = true
+		old, ok := before[torrent.ID]
+		if !ok {
+			diff.Added = append(diff.Added, torrent)
+			continue
+		}
+		fields := changedFields(old, torrent)
+		if len(fields) > 0 {
+			diff.Changed = append(diff.Changed, TorrentChange{Torrent: torrent, Fields: fields})
+		}
+	}
+	for _, torrent := range previous {
+		if !seen[torrent.ID] {
+			diff.Removed = append(diff.Removed, torrent)
+		}
+	}
+	return diff
+}
+
+// changedFields returns the names of the fields that differ between a and b.
+func changedFields(a, b Torrent) []string {
+	va := reflect.ValueOf(a)
+	vb := reflect.ValueOf(b)
+	typ := va.Type()
+
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			fields = append(fields, typ.Field(i).Name)
+		}
+	}
+	return fields
+}
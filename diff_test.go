@@ -0,0 +1,34 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentsDiff(t *testing.T) {
+	Convey("Test diffing two snapshots", t, func() {
+		previous := Torrents{
+			{ID: 1, Name: "a", PercentDone: 0.5},
+			{ID: 2, Name: "b"},
+			{ID: 3, Name: "c", Labels: []string{"tv"}},
+		}
+		current := Torrents{
+			{ID: 1, Name: "a", PercentDone: 0.75, Status: StatusSeed},
+			{ID: 3, Name: "c", Labels: []string{"tv"}},
+			{ID: 4, Name: "d"},
+		}
+
+		diff := current.Diff(previous)
+		So(diff.Empty(), ShouldBeFalse)
+		So(diff.Added, ShouldHaveLength, 1)
+		So(diff.Added[0].ID, ShouldEqual, 4)
+		So(diff.Removed, ShouldHaveLength, 1)
+		So(diff.Removed[0].ID, ShouldEqual, 2)
+		So(diff.Changed, ShouldHaveLength, 1)
+		So(diff.Changed[0].Torrent.ID, ShouldEqual, 1)
+		So(diff.Changed[0].Fields, ShouldResemble, []string{"Status", "PercentDone"})
+
+		So(current.Diff(current).Empty(), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,174 @@
+package transmission
+
+import (
+	"context"
+	"time"
+)
+
+// FreeSpace get the free space of a directory on the daemon's filesystem
+func (ac *TransmissionClient) FreeSpace(path string) (ByteSize, error) {
+	return ac.freeSpace(context.Background(), path)
+}
+
+func (ac *TransmissionClient) freeSpace(ctx context.Context, path string) (ByteSize, error) {
+	var out struct {
+		SizeBytes ByteSize `json:"size-bytes"`
+	}
+	err := ac.rpcContext(ctx, "free-space", map[string]interface{}{"path": path}, &out)
+	return out.SizeBytes, err
+}
+
+// DiskGuardEvent reports torrents paused or resumed by a DiskGuard.
+type DiskGuardEvent struct {
+	Dir  string
+	Free ByteSize
+	// Low is true when the torrents were paused because Free fell below
+	// the threshold, and false when they were resumed.
+	Low    bool
+	IDs    []int
+	Hashes []string
+}
+
+// DiskGuard pauses downloading torrents when the free space of their
+// download directory falls below Threshold, and resumes them once it is
+// back above ResumeAbove.
+type DiskGuard struct {
+	Threshold ByteSize
+	// ResumeAbove defaults to Threshold. Setting it higher keeps torrents
+	// from flapping around the threshold.
+	ResumeAbove ByteSize
+	// Interval between checks in Run. Defaults to one minute.
+	Interval time.Duration
+	// OnEvent is called whenever torrents are paused or resumed.
+	OnEvent func(DiskGuardEvent)
+	// OnError is called when a check fails in Run.
+	OnError func(error)
+
+	client *TransmissionClient
+	paused map[string][]string
+}
+
+// NewDiskGuard create a guard acting through client
+func NewDiskGuard(client *TransmissionClient, threshold ByteSize) *DiskGuard {
+	return &DiskGuard{
+		Threshold: threshold,
+		Interval:  time.Minute,
+		client:    client,
+		paused:    make(map[string][]string),
+	}
+}
+
+// Check runs a single check of every directory with downloading torrents
+// or torrents paused by the guard, and returns the events it caused.
+// Torrents are tracked by hash, so one removed while paused is forgotten
+// rather than resumed.
+func (g *DiskGuard) Check() ([]DiskGuardEvent, error) {
+	return g.CheckContext(context.Background())
+}
+
+// CheckContext is like Check but aborts the requests when ctx is done.
+func (g *DiskGuard) CheckContext(ctx context.Context) ([]DiskGuardEvent, error) {
+	torrents, err := g.client.fetchTorrents(ctx, torrentGetFields, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]int, len(torrents))
+	downloading := make(map[string][]string)
+	for _, torrent := range torrents {
+		ids[torrent.HashString] = torrent.ID
+		if torrent.Status == StatusDownload || torrent.Status == StatusDownloadWait {
+			downloading[torrent.DownloadDir] = append(downloading[torrent.DownloadDir], torrent.HashString)
+		}
+	}
+	dirs := make(map[string]bool)
+	for dir := range downloading {
+		dirs[dir] = true
+	}
+	for dir, hashes := range g.paused {
+		var present []string
+		for _, hash := range hashes {
+			if _, ok := ids[hash]; ok {
+				present = append(present, hash)
+			}
+		}
+		if len(present) == 0 {
+			delete(g.paused, dir)
+			continue
+		}
+		g.paused[dir] = present
+		dirs[dir] = true
+	}
+
+	resumeAbove := g.ResumeAbove
+	if resumeAbove < g.Threshold {
+		resumeAbove = g.Threshold
+	}
+
+	var events []DiskGuardEvent
+	for dir := range dirs {
+		free, err := g.client.freeSpace(ctx, dir)
+		if err != nil {
+			return events, err
+		}
+
+		switch {
+		case free < g.Threshold && len(downloading[dir]) > 0:
+			hashes := downloading[dir]
+			_, err = invoke[struct{}](ctx, g.client, "torrent-stop", idsArgs{Ids: Hashes(hashes...)})
+			if err != nil {
+				return events, err
+			}
+			g.paused[dir] = append(g.paused[dir], hashes...)
+			events = append(events, g.emit(DiskGuardEvent{Dir: dir, Free: free, Low: true, IDs: hashIDs(ids, hashes), Hashes: hashes}))
+		case free >= resumeAbove && len(g.paused[dir]) > 0:
+			hashes := g.paused[dir]
+			_, err = invoke[struct{}](ctx, g.client, "torrent-start", idsArgs{Ids: Hashes(hashes...)})
+			if err != nil {
+				return events, err
+			}
+			delete(g.paused, dir)
+			events = append(events, g.emit(DiskGuardEvent{Dir: dir, Free: free, IDs: hashIDs(ids, hashes), Hashes: hashes}))
+		}
+	}
+	return events, nil
+}
+
+// hashIDs returns the ids of hashes.
+func hashIDs(ids map[string]int, hashes []string) []int {
+	out := make([]int, len(hashes))
+	for i, hash := range hashes {
+		out[i] = ids[hash]
+	}
+	return out
+}
+
+func (g *DiskGuard) emit(event DiskGuardEvent) DiskGuardEvent {
+	if g.OnEvent != nil {
+		g.OnEvent(event)
+	}
+	return event
+}
+
+// Run checks every interval until ctx is done.
+func (g *DiskGuard) Run(ctx context.Context) error {
+	interval := g.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := g.CheckContext(ctx)
+		if err != nil && g.OnError != nil {
+			g.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
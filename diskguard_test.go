@@ -0,0 +1,127 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFreeSpace(t *testing.T) {
+	tSetup(`{"arguments":{"path":"/data","size-bytes":1073741824},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting the free space", t, func() {
+		free, err := transmissionClient.FreeSpace("/data")
+		So(err, ShouldBeNil)
+		So(free, ShouldEqual, GiB)
+	})
+}
+
+func diskGuardFixture(free ByteSize, status int) string {
+	return fmt.Sprintf(`{"arguments":{"size-bytes":%d,"torrents":[
+  {"id":1,"hashString":"aaaa","downloadDir":"/data","status":%d},
+  {"id":2,"hashString":"bbbb","downloadDir":"/data","status":6}]},"result":"success"}`, free, status)
+}
+
+func TestDiskGuard(t *testing.T) {
+	Convey("Test downloads are paused and resumed", t, func() {
+		var events []DiskGuardEvent
+
+		tSetup(diskGuardFixture(100*MiB, StatusDownload))
+		guard := NewDiskGuard(&transmissionClient, GiB)
+		guard.ResumeAbove = 2 * GiB
+		guard.OnEvent = func(event DiskGuardEvent) {
+			events = append(events, event)
+		}
+
+		_, err := guard.Check()
+		So(err, ShouldBeNil)
+		So(len(events), ShouldEqual, 1)
+		So(events[0].Low, ShouldBeTrue)
+		So(events[0].Dir, ShouldEqual, "/data")
+		So(events[0].IDs, ShouldResemble, []int{1})
+		So(events[0].Hashes, ShouldResemble, []string{"aaaa"})
+		tTeardown()
+
+		// Above the threshold but below ResumeAbove nothing happens.
+		tSetup(diskGuardFixture(GiB+MiB, StatusPaused))
+		guard.client = &transmissionClient
+		_, err = guard.Check()
+		So(err, ShouldBeNil)
+		So(len(events), ShouldEqual, 1)
+		tTeardown()
+
+		tSetup(diskGuardFixture(3*GiB, StatusPaused))
+		guard.client = &transmissionClient
+		_, err = guard.Check()
+		So(err, ShouldBeNil)
+		So(len(events), ShouldEqual, 2)
+		So(events[1].Low, ShouldBeFalse)
+		So(events[1].IDs, ShouldResemble, []int{1})
+		tTeardown()
+	})
+
+	Convey("Test a torrent removed while paused isn't resumed", t, func() {
+		var events []DiskGuardEvent
+
+		tSetup(diskGuardFixture(100*MiB, StatusDownload))
+		guard := NewDiskGuard(&transmissionClient, GiB)
+		guard.OnEvent = func(event DiskGuardEvent) {
+			events = append(events, event)
+		}
+		_, err := guard.Check()
+		So(err, ShouldBeNil)
+		So(len(events), ShouldEqual, 1)
+		tTeardown()
+
+		tSetup(`{"arguments":{"size-bytes":3221225472,"torrents":[
+  {"id":2,"hashString":"bbbb","downloadDir":"/data","status":6}]},"result":"success"}`)
+		guard.client = &transmissionClient
+		_, err = guard.Check()
+		So(err, ShouldBeNil)
+		So(len(events), ShouldEqual, 1)
+		So(guard.paused, ShouldBeEmpty)
+		tTeardown()
+	})
+
+	Convey("Test torrents aren't recorded as paused when stopping them fails", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Transmission-Session-Id") != "token" {
+				w.Header().Set("X-Transmission-Session-Id", "token")
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			var request struct {
+				Method string `json:"method"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.Method == "torrent-stop" {
+				w.Write([]byte(`{"arguments":{},"result":"unable to stop"}`))
+				return
+			}
+			w.Write([]byte(`{"arguments":{"size-bytes":104857600,"torrents":[
+  {"id":1,"hashString":"aaaa","downloadDir":"/data","status":4}]},"result":"success"}`))
+		}))
+		defer server.Close()
+		client := New(server.URL, "", "")
+		guard := NewDiskGuard(&client, GiB)
+		_, err := guard.Check()
+		So(err, ShouldNotBeNil)
+		So(guard.paused, ShouldBeEmpty)
+	})
+
+	Convey("Test Run returns while the daemon hangs", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+		So(tRunFor(NewDiskGuard(&client, GiB).Run, 50*time.Millisecond), ShouldEqual, context.DeadlineExceeded)
+	})
+}
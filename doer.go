@@ -0,0 +1,65 @@
+package transmission
+
+import (
+	"net/http"
+	"net/http/httptest"
+)
+
+// Doer sends the HTTP requests of a client. It is the seam for transports
+// other than the default one, such as a tunnel over SSH, a handler called
+// in-process by tests, or a recorder of the traffic.
+//
+// A Doer must honour the context of the request and return the response
+// as the daemon sent it: session id refreshes, authentication challenges,
+// reconnects and failover are handled by the client on top of it.
+// *http.Client implements Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to a Doer, e.g. to wrap another Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithDoer sends the requests of the client with doer instead of the
+// default *http.Client. WithTransportOptions and the transport resets of
+// WithReconnect only configure the default client, so they have no effect
+// on doer.
+func WithDoer(doer Doer) Option {
+	return func(ac *ApiClient) {
+		ac.doer = doer
+	}
+}
+
+// HandlerDoer returns a Doer that serves requests with handler in the
+// calling goroutine, without a network connection, e.g. to run a client
+// against a fake daemon in tests.
+func HandlerDoer(handler http.Handler) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		err := req.Context().Err()
+		if err != nil {
+			return nil, err
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Result(), nil
+	})
+}
+
+// do sends req with the Doer of the client.
+func (ac *ApiClient) do(req *http.Request) (*http.Response, error) {
+	var doer Doer = ac.conn.httpClient()
+	if ac.doer != nil {
+		doer = ac.doer
+	}
+	res, err := doer.Do(req)
+	if err == nil && res.Request == nil {
+		// The 409 handling reads the session id that was sent from it.
+		res.Request = req
+	}
+	return res, err
+}
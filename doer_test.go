@@ -0,0 +1,74 @@
+package transmission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDoer(t *testing.T) {
+	daemon := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Write([]byte(`{"arguments":{"torrents":[{"id":1,"name":"in-process"}]},"result":"success"}`))
+	})
+
+	Convey("Test a client runs against a handler in-process", t, func() {
+		client := New("http://daemon.invalid", "", "", WithDoer(HandlerDoer(daemon)))
+		torrents, err := client.GetTorrents()
+		So(err, ShouldBeNil)
+		So(torrents, ShouldHaveLength, 1)
+		So(torrents[0].Name, ShouldEqual, "in-process")
+	})
+
+	Convey("Test a wrapping Doer sees every request", t, func() {
+		var statuses []string
+		inner := HandlerDoer(daemon)
+		recorder := DoerFunc(func(req *http.Request) (*http.Response, error) {
+			res, err := inner.Do(req)
+			if err == nil {
+				statuses = append(statuses, res.Status)
+			}
+			return res, err
+		})
+		client := New("http://daemon.invalid", "", "", WithDoer(recorder))
+		_, err := client.GetTorrents()
+		So(err, ShouldBeNil)
+		So(statuses, ShouldResemble, []string{"409 Conflict", "200 OK"})
+	})
+
+	Convey("Test errors of the Doer are returned", t, func() {
+		failing := DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("tunnel closed")
+		})
+		client := New("http://daemon.invalid", "", "", WithDoer(failing))
+		_, err := client.GetTorrents()
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "tunnel closed")
+	})
+
+	Convey("Test HandlerDoer honours a cancelled context", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req, _ := http.NewRequestWithContext(ctx, "POST", "http://daemon.invalid", nil)
+		_, err := HandlerDoer(daemon).Do(req)
+		So(err, ShouldEqual, context.Canceled)
+	})
+
+	Convey("Test WithTimeout limits requests sent with a Doer", t, func() {
+		hanging := DoerFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+		client := New("http://daemon.invalid", "", "", WithDoer(hanging), WithTimeout(20*time.Millisecond))
+		err := client.Call(context.Background(), "session-stats", nil, nil)
+		So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,214 @@
+package transmission
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// EnsureOptions configures EnsureTorrent. DownloadDir and Labels of the
+// embedded AddTorrentOptions are also enforced on a torrent the daemon
+// already has; the other options only apply when it is added.
+type EnsureOptions struct {
+	AddTorrentOptions
+	// Move moves the data of an existing torrent whose download directory
+	// differs, otherwise the daemon looks for the data in DownloadDir.
+	Move bool
+	// Wanted are the indices of the files to download, all others are
+	// skipped. nil leaves the selection as it is.
+	Wanted []int
+}
+
+// EnsureResult reports what EnsureTorrent did.
+type EnsureResult struct {
+	Torrent TorrentAdded
+	// Added is true when the daemon didn't have the torrent.
+	Added bool
+	// Changed lists the settings of the torrent that were updated:
+	// "download-dir", "labels" and "files".
+	Changed []string
+}
+
+// ensureTorrent is a torrent with the fields EnsureTorrent compares.
+type ensureTorrent struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	HashString  string   `json:"hashString"`
+	DownloadDir string   `json:"downloadDir"`
+	Labels      []string `json:"labels"`
+	Status      int      `json:"status"`
+	FileStats   []struct {
+		Wanted bool `json:"wanted"`
+	} `json:"fileStats"`
+}
+
+var ensureFields = []string{"id", "name", "hashString", "downloadDir", "labels", "status", "fileStats"}
+
+// ensureCleanupTimeout bounds stopping a torrent that EnsureTorrent started
+// to fetch its metadata, which is done even once its context is done.
+const ensureCleanupTimeout = 10 * time.Second
+
+// EnsureTorrent makes sure the daemon has the torrent of source, with the
+// download directory, labels and file selection of opts. The torrent is
+// identified by its info hash, so source must be a magnet link or a
+// .torrent file, or a URL with FetchURL set. It is added when it is
+// missing, and otherwise its settings are updated where they differ, so
+// calling EnsureTorrent again changes nothing. A file selection on a
+// magnet link waits until the daemon has the metadata, running the torrent
+// meanwhile as a stopped one doesn't fetch it, and stopping it again if it
+// was stopped. A .torrent added with a file selection is only started once
+// the selection is applied.
+func (ac *TransmissionClient) EnsureTorrent(ctx context.Context, source string, opts EnsureOptions) (EnsureResult, error) {
+	cmd, err := ac.newAddCmd(ctx, source, opts.AddTorrentOptions)
+	if err != nil {
+		return EnsureResult{}, err
+	}
+	hash := addCmdHash(cmd)
+	if hash == "" {
+		return EnsureResult{}, &ValidationError{"source", source, "must be a magnet link or .torrent file with a v1 info hash"}
+	}
+
+	var result EnsureResult
+	torrent, found, err := ac.lookupEnsured(ctx, hash)
+	if err != nil {
+		return result, err
+	}
+	startAfter := false
+	if !found {
+		// A magnet has no files to select until it has the metadata, so
+		// it can't start downloading unwanted ones before.
+		magnet := strings.HasPrefix(cmd.Arguments.Filename, "magnet:")
+		startAfter = opts.Wanted != nil && !opts.Paused && !magnet
+		if startAfter {
+			cmd.Arguments.Paused = true
+		}
+		added, err := invoke[torrentAddResult](ctx, ac, cmd.Method, cmd.Arguments)
+		if err != nil {
+			return result, err
+		}
+		addResult, err := added.addResult()
+		if err != nil {
+			return result, err
+		}
+		result.Added = !addResult.Duplicate
+		startAfter = startAfter && result.Added
+		torrent, found, err = ac.lookupEnsured(ctx, hash)
+		if err != nil {
+			return result, err
+		}
+		if !found {
+			return result, errors.New("torrent " + hash + " missing after torrent-add")
+		}
+	}
+	result.Torrent = TorrentAdded{HashString: torrent.HashString, ID: torrent.ID, Name: torrent.Name}
+
+	if opts.DownloadDir != "" && cleanDir(opts.DownloadDir) != cleanDir(torrent.DownloadDir) {
+		err = ac.setLocation(ctx, torrent.ID, opts.DownloadDir, opts.Move)
+		if err != nil {
+			return result, err
+		}
+		result.Changed = append(result.Changed, "download-dir")
+	}
+	if opts.Labels != nil && !equalLabels(opts.Labels, torrent.Labels) {
+		err = ac.setTorrentLabels(ctx, torrent.ID, opts.Labels)
+		if err != nil {
+			return result, err
+		}
+		result.Changed = append(result.Changed, "labels")
+	}
+	if opts.Wanted != nil {
+		changed, err := ac.ensureWanted(ctx, torrent, opts.Wanted)
+		if err != nil {
+			return result, err
+		}
+		if changed {
+			result.Changed = append(result.Changed, "files")
+		}
+	}
+	if startAfter {
+		_, err = invoke[struct{}](ctx, ac, "torrent-start", idsArgs{Ids: IDs(torrent.ID)})
+	}
+	return result, err
+}
+
+// lookupEnsured fetches the torrent with hash, if the daemon has it.
+func (ac *TransmissionClient) lookupEnsured(ctx context.Context, hash string) (ensureTorrent, bool, error) {
+	result, err := invoke[struct {
+		Torrents []ensureTorrent `json:"torrents"`
+	}](ctx, ac, "torrent-get", torrentGetArgs{Fields: ensureFields, Ids: Hashes(hash)})
+	if err != nil || len(result.Torrents) == 0 {
+		return ensureTorrent{}, false, err
+	}
+	return result.Torrents[0], true, nil
+}
+
+// ensureWanted applies the file selection wanted to the torrent, waiting
+// for its metadata first if need be. It reports whether the selection
+// changed.
+func (ac *TransmissionClient) ensureWanted(ctx context.Context, torrent ensureTorrent, wanted []int) (changed bool, err error) {
+	if len(torrent.FileStats) == 0 {
+		if torrent.Status == StatusPaused {
+			// A stopped torrent doesn't connect to peers, so it would never
+			// get the metadata.
+			id := torrent.ID
+			_, err = invoke[struct{}](ctx, ac, "torrent-start", idsArgs{Ids: IDs(id)})
+			if err != nil {
+				return false, err
+			}
+			defer func() {
+				// Stop it even when ctx is done, but don't hang on it.
+				stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ensureCleanupTimeout)
+				defer cancel()
+				_, stopErr := invoke[struct{}](stopCtx, ac, "torrent-stop", idsArgs{Ids: IDs(id)})
+				if err == nil {
+					err = stopErr
+				}
+			}()
+		}
+		_, err = ac.WaitForMetadata(ctx, torrent.ID)
+		if err != nil {
+			return false, err
+		}
+		hash := torrent.HashString
+		var found bool
+		torrent, found, err = ac.lookupEnsured(ctx, hash)
+		if err != nil {
+			return false, err
+		}
+		if !found {
+			return false, errors.New("torrent " + hash + " was removed")
+		}
+	}
+
+	want := make([]bool, len(torrent.FileStats))
+	for _, index := range wanted {
+		if index < 0 || index >= len(want) {
+			return false, &ValidationError{"wanted", index, "no such file"}
+		}
+		want[index] = true
+	}
+	var (
+		filesWanted   = []int{}
+		filesUnwanted = []int{}
+	)
+	for index, stat := range torrent.FileStats {
+		if want[index] {
+			filesWanted = append(filesWanted, index)
+		} else {
+			filesUnwanted = append(filesUnwanted, index)
+		}
+		changed = changed || stat.Wanted != want[index]
+	}
+	if !changed {
+		return false, nil
+	}
+	args := map[string]interface{}{}
+	if len(filesWanted) > 0 {
+		args["files-wanted"] = filesWanted
+	}
+	if len(filesUnwanted) > 0 {
+		args["files-unwanted"] = filesUnwanted
+	}
+	return true, ac.setTorrent(torrent.ID, args)
+}
@@ -0,0 +1,211 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEnsureTorrent(t *testing.T) {
+	defer func(interval time.Duration) { metadataPollInterval = interval }(metadataPollInterval)
+	metadataPollInterval = 5 * time.Millisecond
+
+	const (
+		hash   = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		magnet = "magnet:?xt=urn:btih:" + hash + "&dn=album"
+	)
+	// A daemon only fetches the metadata of a magnet while it runs.
+	type daemonTorrent struct {
+		dir      string
+		labels   []string
+		wanted   []bool
+		paused   bool
+		metadata bool
+	}
+	var (
+		torrent *daemonTorrent
+		methods []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var request struct {
+			Method    string `json:"method"`
+			Arguments struct {
+				DownloadDir   string   `json:"download-dir"`
+				Location      string   `json:"location"`
+				Labels        []string `json:"labels"`
+				Paused        bool     `json:"paused"`
+				FilesWanted   []int    `json:"files-wanted"`
+				FilesUnwanted []int    `json:"files-unwanted"`
+			} `json:"arguments"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		args := request.Arguments
+		methods = append(methods, request.Method)
+		arguments := map[string]interface{}{}
+		switch request.Method {
+		case "session-get":
+			arguments["rpc-version"] = 17
+		case "torrent-get":
+			torrents := []map[string]interface{}{}
+			if torrent != nil {
+				stats := []map[string]interface{}{}
+				progress := 0
+				if torrent.metadata {
+					progress = 1
+					for _, wanted := range torrent.wanted {
+						stats = append(stats, map[string]interface{}{"wanted": wanted})
+					}
+				}
+				status := StatusDownload
+				if torrent.paused {
+					status = StatusPaused
+				}
+				torrents = append(torrents, map[string]interface{}{"id": 1, "name": "album", "hashString": hash,
+					"downloadDir": torrent.dir, "labels": torrent.labels, "status": status,
+					"fileStats": stats, "metadataPercentComplete": progress})
+			}
+			arguments["torrents"] = torrents
+		case "torrent-add":
+			key := "torrent-duplicate"
+			if torrent == nil {
+				key = "torrent-added"
+				torrent = &daemonTorrent{dir: args.DownloadDir, labels: args.Labels,
+					wanted: []bool{true, true, true}, paused: args.Paused, metadata: !args.Paused}
+			}
+			arguments[key] = map[string]interface{}{"id": 1, "name": "album", "hashString": hash}
+		case "torrent-set":
+			if args.Labels != nil {
+				torrent.labels = args.Labels
+			}
+			for _, index := range args.FilesWanted {
+				torrent.wanted[index] = true
+			}
+			for _, index := range args.FilesUnwanted {
+				torrent.wanted[index] = false
+			}
+		case "torrent-set-location":
+			torrent.dir = args.Location
+		case "torrent-start":
+			torrent.paused = false
+			torrent.metadata = true
+		case "torrent-stop":
+			torrent.paused = true
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": "success", "arguments": arguments})
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+	opts := EnsureOptions{
+		AddTorrentOptions: AddTorrentOptions{DownloadDir: "/music", Labels: []string{"flac"}},
+		Wanted:            []int{0, 2},
+	}
+
+	Convey("Test a missing torrent is added with its settings", t, func() {
+		torrent, methods = nil, nil
+		result, err := client.EnsureTorrent(context.Background(), magnet, opts)
+		So(err, ShouldBeNil)
+		So(result.Added, ShouldBeTrue)
+		So(result.Torrent.ID, ShouldEqual, 1)
+		So(result.Changed, ShouldResemble, []string{"files"})
+		So(torrent.dir, ShouldEqual, "/music")
+		So(torrent.labels, ShouldResemble, []string{"flac"})
+		So(torrent.wanted, ShouldResemble, []bool{true, false, true})
+		So(torrent.paused, ShouldBeFalse)
+		So(methods, ShouldNotContain, "torrent-start")
+	})
+
+	Convey("Test a magnet added paused is run until it has the metadata", t, func() {
+		torrent, methods = nil, nil
+		paused := opts
+		paused.Paused = true
+		result, err := client.EnsureTorrent(context.Background(), magnet, paused)
+		So(err, ShouldBeNil)
+		So(result.Added, ShouldBeTrue)
+		So(torrent.wanted, ShouldResemble, []bool{true, false, true})
+		So(torrent.paused, ShouldBeTrue)
+		So(methods, ShouldContain, "torrent-start")
+		So(methods[len(methods)-1], ShouldEqual, "torrent-stop")
+	})
+
+	Convey("Test ensuring again changes nothing", t, func() {
+		methods = nil
+		result, err := client.EnsureTorrent(context.Background(), magnet, opts)
+		So(err, ShouldBeNil)
+		So(result.Added, ShouldBeFalse)
+		So(result.Changed, ShouldBeEmpty)
+		So(methods, ShouldNotContain, "torrent-add")
+		So(methods, ShouldNotContain, "torrent-set")
+	})
+
+	Convey("Test an existing torrent is reconciled", t, func() {
+		torrent = &daemonTorrent{dir: "/downloads/", labels: []string{"music"}, wanted: []bool{true, true, false}, metadata: true}
+		result, err := client.EnsureTorrent(context.Background(), magnet, opts)
+		So(err, ShouldBeNil)
+		So(result.Added, ShouldBeFalse)
+		So(result.Changed, ShouldResemble, []string{"download-dir", "labels", "files"})
+		So(torrent.dir, ShouldEqual, "/music")
+		So(torrent.labels, ShouldResemble, []string{"flac"})
+		So(torrent.wanted, ShouldResemble, []bool{true, false, true})
+	})
+
+	Convey("Test invalid requests are rejected", t, func() {
+		_, err := client.EnsureTorrent(context.Background(), "https://example.com/a.torrent", opts)
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+
+		badFile := opts
+		badFile.Wanted = []int{3}
+		_, err = client.EnsureTorrent(context.Background(), magnet, badFile)
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+	})
+}
+
+func TestEnsureTorrentHangs(t *testing.T) {
+	const hash = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		var body string
+		switch request.Method {
+		case "session-get":
+			body = `{"arguments":{"rpc-version":17},"result":"success"}`
+		case "torrent-get":
+			body = `{"arguments":{"torrents":[{"id":1,"name":"album","hashString":"` + hash +
+				`","downloadDir":"/music","labels":["music"],"fileStats":[{"wanted":true}]}]},"result":"success"}`
+		default:
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write([]byte(body))
+		w.(http.Flusher).Flush()
+	})
+	defer server.Close()
+	defer close(release)
+	client := New(server.URL, "", "")
+
+	Convey("Test EnsureTorrent returns while the daemon hangs on a change", t, func() {
+		for _, opts := range []EnsureOptions{
+			{AddTorrentOptions: AddTorrentOptions{DownloadDir: "/downloads"}},
+			{AddTorrentOptions: AddTorrentOptions{Labels: []string{"flac"}}},
+		} {
+			err := tRunFor(func(ctx context.Context) error {
+				_, err := client.EnsureTorrent(ctx, "magnet:?xt=urn:btih:"+hash, opts)
+				return err
+			}, 50*time.Millisecond)
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+		}
+	})
+}
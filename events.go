@@ -0,0 +1,67 @@
+package transmission
+
+import (
+	"context"
+	"time"
+)
+
+// EventOptions configures Events.
+type EventOptions struct {
+	// Interval between polls. Defaults to five seconds.
+	Interval time.Duration
+	// Buffer is the capacity of the returned channel.
+	Buffer int
+	// OnError is called when a poll fails. Polling continues regardless.
+	OnError func(error)
+}
+
+// Events watches the torrents and sends an Event for every change until
+// ctx is done, when the channel is closed. The current state is fetched
+// before Events returns, so an unreachable daemon is reported right away
+// and torrents that already exist don't produce EventAdded.
+func (ac *TransmissionClient) Events(ctx context.Context, opts EventOptions) (<-chan Event, error) {
+	w := NewWatcher(ac)
+	if opts.Interval > 0 {
+		w.Interval = opts.Interval
+	}
+	w.OnError = opts.OnError
+
+	_, err := w.PollContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Event, opts.Buffer)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			events, err := w.PollContext(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				if w.OnError != nil {
+					w.OnError(err)
+				}
+				continue
+			}
+			for _, event := range events {
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,65 @@
+package transmission
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEvents(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1}]},"result":"success"}`)
+
+	Convey("Test the channel is closed when the context ends", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		events, err := transmissionClient.Events(ctx, EventOptions{Interval: time.Millisecond})
+		So(err, ShouldBeNil)
+
+		cancel()
+		for range events {
+		}
+	})
+
+	tTeardown()
+
+	Convey("Test an unreachable daemon is reported right away", t, func() {
+		_, err := transmissionClient.Events(context.Background(), EventOptions{})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test the channel is closed while the daemon hangs", t, func() {
+		var polls int32
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&polls, 1) > 1 {
+				return
+			}
+			body := `{"arguments":{"torrents":[]},"result":"success"}`
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.Write([]byte(body))
+			w.(http.Flusher).Flush()
+		})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		events, err := client.Events(ctx, EventOptions{Interval: time.Millisecond})
+		So(err, ShouldBeNil)
+		closed := make(chan struct{})
+		go func() {
+			for range events {
+			}
+			close(closed)
+		}()
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			So("channel still open", ShouldBeEmpty)
+		}
+	})
+}
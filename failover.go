@@ -0,0 +1,75 @@
+package transmission
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// endpointList is the set of RPC URLs a client can fail over between. It
+// remembers which one worked last and is shared between copies of the
+// ApiClient.
+type endpointList struct {
+	mu      sync.Mutex
+	urls    []string
+	current int
+}
+
+// WithFailover adds fallback endpoints, given in the same form as the URL
+// passed to New. When the current endpoint can't be reached the others are
+// tried in order, and the first one that answers is used for subsequent
+// requests.
+func WithFailover(urls ...string) Option {
+	return func(ac *ApiClient) {
+		list := &endpointList{urls: []string{ac.url}}
+		for _, url := range urls {
+			list.urls = append(list.urls, url+"/transmission/rpc")
+		}
+		ac.failover = list
+	}
+}
+
+// active returns the index and URL of the endpoint to try first.
+func (l *endpointList) active() (int, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.current, l.urls[l.current]
+}
+
+// use remembers the endpoint at index as the working one.
+func (l *endpointList) use(index int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.current = index
+}
+
+// post sends body through ac, starting at the active endpoint and moving on
+// to the next one whenever the connection fails. Errors that aren't about
+// the connection, like the context ending, are returned immediately, as
+// are failures of non-idempotent methods the daemon may have received.
+func (l *endpointList) post(ctx context.Context, ac *ApiClient, body requestBody) (int, []byte, error) {
+	start, _ := l.active()
+	var (
+		status  int
+		resBody []byte
+		err     error
+	)
+	for i := 0; i < len(l.urls); i++ {
+		index := (start + i) % len(l.urls)
+		if index != start {
+			// Another daemon or proxy will want its own session id.
+			ac.token.reset()
+			ac.stats.retry()
+			ac.log(ctx, slog.LevelWarn, "switching endpoint", slog.String("url", l.urls[index]))
+		}
+		status, resBody, err = ac.postOnce(ctx, l.urls[index], body)
+		if err == nil {
+			l.use(index)
+			return status, resBody, nil
+		}
+		if ctx.Err() != nil || nonIdempotent[body.method] && !requestUnsent(err) {
+			return status, resBody, err
+		}
+	}
+	return status, resBody, err
+}
@@ -0,0 +1,71 @@
+package transmission
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFailover(t *testing.T) {
+	Convey("Test requests fail over to the next endpoint", t, func() {
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+
+		hits := 0
+		up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			fmt.Fprint(w, `{"arguments":{},"result":"success"}`)
+		}))
+		defer up.Close()
+
+		client := New(down.URL, "", "", WithFailover(up.URL))
+		_, err := client.StartTorrent(1)
+		So(err, ShouldBeNil)
+
+		_, url := client.apiclient.failover.active()
+		So(url, ShouldEqual, up.URL+"/transmission/rpc")
+
+		_, err = client.StopTorrent(1)
+		So(err, ShouldBeNil)
+		So(hits, ShouldEqual, 4)
+	})
+
+	Convey("Test a torrent-add the daemon may have received isn't resent", t, func() {
+		dropping := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Transmission-Session-Id") != "token" {
+				w.Header().Set("X-Transmission-Session-Id", "token")
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			io.Copy(io.Discard, r.Body)
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		}))
+		defer dropping.Close()
+
+		hits := 0
+		up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			fmt.Fprint(w, `{"arguments":{"torrent-added":{"id":1}},"result":"success"}`)
+		}))
+		defer up.Close()
+
+		client := New(dropping.URL, "", "", WithFailover(up.URL))
+		_, err := client.AddTorrent("magnet:?xt=urn:btih:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", AddTorrentOptions{})
+		So(err, ShouldNotBeNil)
+		So(hits, ShouldEqual, 0)
+	})
+
+	Convey("Test the last error is returned when every endpoint is down", t, func() {
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+
+		client := New(down.URL, "", "", WithFailover(down.URL))
+		_, err := client.StartTorrent(1)
+		So(err, ShouldNotBeNil)
+	})
+}
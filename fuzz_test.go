@@ -0,0 +1,61 @@
+package transmission
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Daemon and proxy responses are untrusted input. These fuzz targets make
+// sure decoding them returns an error rather than panicking.
+
+func FuzzDecodeCommand(f *testing.F) {
+	f.Add([]byte(`{"arguments":{"torrents":[{"id":5,"name":"Test","status":6}]},"result":"success","tag":3}`))
+	f.Add([]byte(`{"arguments":{"torrent-added":{"hashString":"875a2d90","id":1,"name":"x"}},"result":"success"}`))
+	f.Add([]byte(`{"arguments":{"ids":[1,"875a2d90"]},"result":"success"}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var cmd Command
+		json.Unmarshal(data, &cmd)
+	})
+}
+
+func FuzzDecodeTorrent(f *testing.F) {
+	f.Add([]byte(`{"id":1,"dateCreated":1700000000,"secondsSeeding":60,"labels":["tv"],"files":[{"name":"a","length":10}]}`))
+	f.Add([]byte(`{"id":2,"percentDone":"0.5","trackerStats":[{}],"availability":[-1,0,3]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var torrent Torrent
+		if json.Unmarshal(data, &torrent) == nil {
+			json.Marshal(torrent)
+		}
+		decodeTorrentLenient(data)
+	})
+}
+
+func FuzzDecodeSession(f *testing.F) {
+	fixtures, _ := filepath.Glob(filepath.Join("testdata", "session-*.json"))
+	for _, fixture := range fixtures {
+		data, err := os.ReadFile(fixture)
+		if err == nil {
+			f.Add(data)
+		}
+	}
+	f.Add([]byte(`{"speed-limit-down":-1,"units":{"speed-units":null}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var session Session
+		if json.Unmarshal(data, &session) == nil {
+			json.Marshal(session)
+		}
+	})
+}
+
+func FuzzDecodeBencode(f *testing.F) {
+	f.Add([]byte("d8:announce20:http://example.com/a4:infod6:lengthi5e4:name1:a12:piece lengthi16384eee"))
+	f.Add([]byte("li-1e0:le4:spame"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := decodeBencode(data)
+		if err == nil {
+			encodeBencode(v)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package transmission
+
+// GroupByTracker groups the torrents by the TrackerIdentity of their
+// announce URLs.
+// A torrent with several trackers is in each of their groups, and one
+// without trackers is under "".
+func (t Torrents) GroupByTracker() map[string]Torrents {
+	groups := make(map[string]Torrents)
+	for _, torrent := range t {
+		seen := map[string]bool{}
+		for _, stat := range torrent.TrackerStats {
+			host := trackerHost(stat)
+			if seen[host] {
+				continue
+			}
+			seen[host] = true
+			groups[host] = append(groups[host], torrent)
+		}
+		if len(seen) == 0 {
+			groups[""] = append(groups[""], torrent)
+		}
+	}
+	return groups
+}
+
+// GroupByLabel groups the torrents by label. A torrent with several labels
+// is in each of their groups, and one without labels is under "".
+func (t Torrents) GroupByLabel() map[string]Torrents {
+	groups := make(map[string]Torrents)
+	for _, torrent := range t {
+		seen := map[string]bool{}
+		for _, label := range torrent.Labels {
+			if seen[label] {
+				continue
+			}
+			seen[label] = true
+			groups[label] = append(groups[label], torrent)
+		}
+		if len(seen) == 0 {
+			groups[""] = append(groups[""], torrent)
+		}
+	}
+	return groups
+}
+
+// GroupByStatus groups the torrents by their Status* constant.
+func (t Torrents) GroupByStatus() map[int]Torrents {
+	groups := make(map[int]Torrents)
+	for _, torrent := range t {
+		groups[torrent.Status] = append(groups[torrent.Status], torrent)
+	}
+	return groups
+}
+
+// GroupByDownloadDir groups the torrents by download directory, ignoring
+// trailing slashes.
+func (t Torrents) GroupByDownloadDir() map[string]Torrents {
+	groups := make(map[string]Torrents)
+	for _, torrent := range t {
+		dir := cleanDir(torrent.DownloadDir)
+		groups[dir] = append(groups[dir], torrent)
+	}
+	return groups
+}
+
+// trackerHost returns the identity of the tracker.
+func trackerHost(stat TrackerStat) string {
+	if stat.Announce == "" {
+		return TrackerIdentity(stat.Host)
+	}
+	return TrackerIdentity(stat.Announce)
+}
@@ -0,0 +1,51 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var tGroupTorrents = Torrents{
+	{ID: 1, Status: StatusSeed, DownloadDir: "/data/", Labels: []string{"tv", "hd"},
+		TrackerStats: []TrackerStat{
+			{Announce: "https://tracker.example.com/announce"},
+			{Announce: "https://tls.example.com:8443/announce?passkey=x"},
+			{Announce: "udp://open.example.org:6969"},
+		}},
+	{ID: 2, Status: StatusDownload, DownloadDir: "/data", Labels: []string{"tv"},
+		TrackerStats: []TrackerStat{{Announce: "udp://open.example.org:6969"}}},
+	{ID: 3, Status: StatusSeed, DownloadDir: "/other"},
+}
+
+func tIDs(torrents Torrents) []int {
+	var ids []int
+	for _, torrent := range torrents {
+		ids = append(ids, torrent.ID)
+	}
+	return ids
+}
+
+func TestGroupBy(t *testing.T) {
+	Convey("Test grouping by tracker", t, func() {
+		groups := tGroupTorrents.GroupByTracker()
+		So(groups, ShouldHaveLength, 3)
+		So(tIDs(groups["example.com"]), ShouldResemble, []int{1})
+		So(tIDs(groups["example.org"]), ShouldResemble, []int{1, 2})
+		So(tIDs(groups[""]), ShouldResemble, []int{3})
+	})
+
+	Convey("Test grouping by label", t, func() {
+		groups := tGroupTorrents.GroupByLabel()
+		So(tIDs(groups["tv"]), ShouldResemble, []int{1, 2})
+		So(tIDs(groups["hd"]), ShouldResemble, []int{1})
+		So(tIDs(groups[""]), ShouldResemble, []int{3})
+	})
+
+	Convey("Test grouping by status and directory", t, func() {
+		So(tIDs(tGroupTorrents.GroupByStatus()[StatusSeed]), ShouldResemble, []int{1, 3})
+		dirs := tGroupTorrents.GroupByDownloadDir()
+		So(dirs, ShouldHaveLength, 2)
+		So(tIDs(dirs["/data"]), ShouldResemble, []int{1, 2})
+	})
+}
@@ -0,0 +1,12 @@
+package transmission
+
+// SetTorrentGroup assign the torrent to a bandwidth group. An empty group
+// removes it from its current group. Requires RPC version 17
+// (Transmission 4.0).
+func (ac *TransmissionClient) SetTorrentGroup(id int, group string) error {
+	err := ac.requireRPCVersion("group", 17)
+	if err != nil {
+		return err
+	}
+	return ac.setTorrent(id, map[string]interface{}{"group": group})
+}
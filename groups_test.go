@@ -0,0 +1,31 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentGroup(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":17,
+  "torrents":[{"id":1,"group":"slow"}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading and setting the bandwidth group", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.Group, ShouldEqual, "slow")
+
+		So(transmissionClient.SetTorrentGroup(1, "fast"), ShouldBeNil)
+	})
+}
+
+func TestTorrentGroupOldDaemon(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":16},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test setting the group is refused on RPC 16", t, func() {
+		err := transmissionClient.SetTorrentGroup(1, "fast")
+		So(err, ShouldNotBeNil)
+	})
+}
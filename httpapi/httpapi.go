@@ -0,0 +1,270 @@
+// Package httpapi exposes a transmission client as a small REST API, so web
+// frontends can list, add and remove torrents without speaking the RPC
+// protocol:
+//
+//	GET    /torrents         list torrents
+//	POST   /torrents         add a torrent, 200 OK if the daemon already had it
+//	DELETE /torrents/{hash}  remove a torrent, ?deleteData=true deletes its data
+//	GET    /session          the daemon's session settings
+//
+// Errors are returned as {"error": "..."} with a matching status code.
+package httpapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tubbebubbe/transmission"
+)
+
+// Handler serves the API for a client.
+type Handler struct {
+	client    *transmission.TransmissionClient
+	authorize func(*http.Request) bool
+	handler   http.Handler
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithAuth rejects requests for which authorize returns false with 401
+// Unauthorized.
+func WithAuth(authorize func(*http.Request) bool) Option {
+	return func(h *Handler) {
+		h.authorize = authorize
+	}
+}
+
+// WithMiddleware wraps the API in middleware, the first one outermost.
+func WithMiddleware(middleware ...func(http.Handler) http.Handler) Option {
+	return func(h *Handler) {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			h.handler = middleware[i](h.handler)
+		}
+	}
+}
+
+// New create a handler serving the API for client
+func New(client *transmission.TransmissionClient, opts ...Option) *Handler {
+	h := &Handler{client: client}
+	h.handler = http.HandlerFunc(h.route)
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+func (h *Handler) route(w http.ResponseWriter, r *http.Request) {
+	if h.authorize != nil && !h.authorize(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/torrents" && r.Method == http.MethodGet:
+		h.listTorrents(w, r)
+	case path == "/torrents" && r.Method == http.MethodPost:
+		h.addTorrent(w, r)
+	case strings.HasPrefix(path, "/torrents/") && r.Method == http.MethodDelete:
+		h.removeTorrent(w, r, strings.TrimPrefix(path, "/torrents/"))
+	case path == "/session" && r.Method == http.MethodGet:
+		h.getSession(w, r)
+	case path == "/torrents" || strings.HasPrefix(path, "/torrents/") || path == "/session":
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// Torrent is the API's view of a torrent.
+type Torrent struct {
+	ID           int       `json:"id"`
+	Hash         string    `json:"hash"`
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Progress     float64   `json:"progress"`
+	Size         int64     `json:"size"`
+	Left         int64     `json:"left"`
+	RateDownload int64     `json:"rateDownload"`
+	RateUpload   int64     `json:"rateUpload"`
+	Ratio        float64   `json:"ratio"`
+	Eta          int       `json:"eta"`
+	DownloadDir  string    `json:"downloadDir"`
+	Labels       []string  `json:"labels"`
+	Added        time.Time `json:"added"`
+	Error        string    `json:"error,omitempty"`
+}
+
+var statusNames = map[int]string{
+	transmission.StatusPaused:       "paused",
+	transmission.StatusWait:         "check-wait",
+	transmission.StatusCheck:        "checking",
+	transmission.StatusDownloadWait: "download-wait",
+	transmission.StatusDownload:     "downloading",
+	transmission.StatisSeedWait:     "seed-wait",
+	transmission.StatusSeed:         "seeding",
+}
+
+func torrentView(t transmission.Torrent) Torrent {
+	labels := t.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	return Torrent{
+		ID:           t.ID,
+		Hash:         t.HashString,
+		Name:         t.Name,
+		Status:       statusNames[t.Status],
+		Progress:     t.PercentDone,
+		Size:         int64(t.SizeWhenDone),
+		Left:         int64(t.LeftUntilDone),
+		RateDownload: int64(t.RateDownload),
+		RateUpload:   int64(t.RateUpload),
+		Ratio:        t.UploadRatio,
+		Eta:          t.Eta,
+		DownloadDir:  t.DownloadDir,
+		Labels:       labels,
+		Added:        time.Unix(int64(t.AddedDate), 0).UTC(),
+		Error:        t.ErrorString,
+	}
+}
+
+func (h *Handler) listTorrents(w http.ResponseWriter, r *http.Request) {
+	torrents, err := h.client.GetTorrents()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	views := make([]Torrent, len(torrents))
+	for i, torrent := range torrents {
+		views[i] = torrentView(torrent)
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// AddRequest is the body of POST /torrents. Source is a magnet link or an
+// http(s) URL; MetaInfo is the base64 content of a .torrent file.
+type AddRequest struct {
+	Source      string   `json:"source"`
+	MetaInfo    string   `json:"metainfo"`
+	DownloadDir string   `json:"downloadDir"`
+	Paused      bool     `json:"paused"`
+	Labels      []string `json:"labels"`
+}
+
+// maxRequestBody limits request bodies, which carry at most a base64
+// encoded .torrent file.
+const maxRequestBody = 16 << 20
+
+func (h *Handler) addTorrent(w http.ResponseWriter, r *http.Request) {
+	var req AddRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req)
+	if errors.As(err, new(*http.MaxBytesError)) {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts := transmission.AddTorrentOptions{
+		DownloadDir: req.DownloadDir,
+		Paused:      req.Paused,
+		Labels:      req.Labels,
+	}
+
+	var added transmission.AddResult
+	switch {
+	case req.MetaInfo != "":
+		var metainfo []byte
+		metainfo, err = base64.StdEncoding.DecodeString(req.MetaInfo)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		added, err = h.client.AddCreatedTorrent(metainfo, opts)
+	case isRemote(req.Source):
+		// Local paths would read files on the API server, so only
+		// remote sources are accepted.
+		added, err = h.client.AddTorrent(req.Source, opts)
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("source must be a magnet link or an http(s) URL"))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	// A torrent the daemon already had wasn't created.
+	status := http.StatusCreated
+	if added.Duplicate {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"id":        added.Torrent.ID,
+		"hash":      added.Torrent.HashString,
+		"name":      added.Torrent.Name,
+		"duplicate": added.Duplicate,
+	})
+}
+
+func (h *Handler) removeTorrent(w http.ResponseWriter, r *http.Request, hash string) {
+	if hash == "" || strings.Contains(hash, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	_, err := h.client.GetTorrentByHash(hash)
+	if errors.Is(err, transmission.ErrTorrentNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	cmd, _ := transmission.NewDelCmdByHash(hash, r.URL.Query().Get("deleteData") == "true")
+	out, err := h.client.ExecuteCommand(cmd)
+	if err == nil && out.Result != "success" {
+		err = errors.New(out.Result)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) getSession(w http.ResponseWriter, r *http.Request) {
+	session, err := h.client.GetSession()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+func isRemote(source string) bool {
+	return strings.HasPrefix(source, "magnet:") ||
+		strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,134 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tubbebubbe/transmission"
+)
+
+// tDaemon answers every RPC request with output.
+func tDaemon(output string) (*httptest.Server, *transmission.TransmissionClient) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(output))
+	}))
+	client := transmission.New(server.URL, "", "")
+	return server, &client
+}
+
+func tRequest(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	daemon, client := tDaemon(`{"arguments":{"download-dir":"/downloads",
+  "torrent-added":{"id":3,"hashString":"cccc","name":"new"},
+  "torrents":[{"id":1,"hashString":"aaaa","name":"a","status":6,"percentDone":1,"labels":null}]},
+  "result":"success"}`)
+	defer daemon.Close()
+	h := New(client)
+
+	Convey("Test listing torrents", t, func() {
+		rec := tRequest(h, "GET", "/torrents", "")
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		var torrents []Torrent
+		So(json.Unmarshal(rec.Body.Bytes(), &torrents), ShouldBeNil)
+		So(torrents, ShouldHaveLength, 1)
+		So(torrents[0].Hash, ShouldEqual, "aaaa")
+		So(torrents[0].Status, ShouldEqual, "seeding")
+		So(torrents[0].Labels, ShouldResemble, []string{})
+	})
+
+	Convey("Test adding a torrent", t, func() {
+		rec := tRequest(h, "POST", "/torrents", `{"source":"magnet:?xt=urn:btih:cccccccccccccccccccccccccccccccccccccccc","paused":true}`)
+		So(rec.Code, ShouldEqual, http.StatusCreated)
+		So(rec.Body.String(), ShouldContainSubstring, `"hash":"cccc"`)
+
+		rec = tRequest(h, "POST", "/torrents", `{"source":"/etc/passwd"}`)
+		So(rec.Code, ShouldEqual, http.StatusBadRequest)
+		rec = tRequest(h, "POST", "/torrents", `{`)
+		So(rec.Code, ShouldEqual, http.StatusBadRequest)
+	})
+
+	Convey("Test removing a torrent", t, func() {
+		rec := tRequest(h, "DELETE", "/torrents/aaaa?deleteData=true", "")
+		So(rec.Code, ShouldEqual, http.StatusNoContent)
+	})
+
+	Convey("Test the session and unknown routes", t, func() {
+		rec := tRequest(h, "GET", "/session", "")
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(rec.Body.String(), ShouldContainSubstring, `"download-dir":"/downloads"`)
+
+		So(tRequest(h, "PUT", "/session", "").Code, ShouldEqual, http.StatusMethodNotAllowed)
+		So(tRequest(h, "GET", "/nope", "").Code, ShouldEqual, http.StatusNotFound)
+	})
+}
+
+func TestHandlerDuplicate(t *testing.T) {
+	daemon, client := tDaemon(`{"arguments":{"torrent-duplicate":{"id":3,"hashString":"cccc","name":"new"}},
+  "result":"success"}`)
+	defer daemon.Close()
+
+	Convey("Test adding a torrent the daemon already has", t, func() {
+		rec := tRequest(New(client), "POST", "/torrents", `{"source":"magnet:?xt=urn:btih:cccccccccccccccccccccccccccccccccccccccc"}`)
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(rec.Body.String(), ShouldContainSubstring, `"duplicate":true`)
+	})
+}
+
+func TestHandlerErrors(t *testing.T) {
+	missing, client := tDaemon(`{"arguments":{"torrents":[]},"result":"success"}`)
+	defer missing.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	unreachable := transmission.New(down.URL, "", "")
+
+	Convey("Test only a missing torrent is not found", t, func() {
+		So(tRequest(New(client), "DELETE", "/torrents/aaaa", "").Code, ShouldEqual, http.StatusNotFound)
+		So(tRequest(New(&unreachable), "DELETE", "/torrents/aaaa", "").Code, ShouldEqual, http.StatusBadGateway)
+	})
+
+	Convey("Test oversized request bodies are rejected", t, func() {
+		body := `{"metainfo":"` + strings.Repeat("A", maxRequestBody) + `"}`
+		So(tRequest(New(client), "POST", "/torrents", body).Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+	})
+}
+
+func TestHandlerAuth(t *testing.T) {
+	daemon, client := tDaemon(`{"arguments":{"torrents":[]},"result":"success"}`)
+	defer daemon.Close()
+
+	Convey("Test requests are authorized and wrapped", t, func() {
+		var wrapped []string
+		mark := func(name string) func(http.Handler) http.Handler {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					wrapped = append(wrapped, name)
+					next.ServeHTTP(w, r)
+				})
+			}
+		}
+		h := New(client,
+			WithAuth(func(r *http.Request) bool { return r.Header.Get("Authorization") == "Bearer secret" }),
+			WithMiddleware(mark("outer"), mark("inner")))
+
+		So(tRequest(h, "GET", "/torrents", "").Code, ShouldEqual, http.StatusUnauthorized)
+		So(wrapped, ShouldResemble, []string{"outer", "inner"})
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/torrents", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		h.ServeHTTP(rec, req)
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(rec.Body.String(), ShouldEqual, "[]\n")
+	})
+}
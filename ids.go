@@ -0,0 +1,120 @@
+package transmission
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// TorrentID addresses a torrent either by its ID or by its hash string.
+// IDs are only valid for the lifetime of the daemon process, hashes are
+// stable.
+type TorrentID struct {
+	id   int
+	hash string
+}
+
+// ByID returns a TorrentID for the torrent with id.
+func ByID(id int) TorrentID {
+	return TorrentID{id: id}
+}
+
+// ByHash returns a TorrentID for the torrent with the sha1 hash string.
+func ByHash(hash string) TorrentID {
+	return TorrentID{hash: hash}
+}
+
+// ID returns the numeric ID and whether the torrent is addressed by it.
+func (t TorrentID) ID() (int, bool) {
+	return t.id, t.hash == ""
+}
+
+// Hash returns the hash string and whether the torrent is addressed by it.
+func (t TorrentID) Hash() (string, bool) {
+	return t.hash, t.hash != ""
+}
+
+func (t TorrentID) String() string {
+	if t.hash != "" {
+		return t.hash
+	}
+	return strconv.Itoa(t.id)
+}
+
+// MarshalJSON encodes the ID as a number or the hash as a string.
+func (t TorrentID) MarshalJSON() ([]byte, error) {
+	if t.hash != "" {
+		return json.Marshal(t.hash)
+	}
+	return json.Marshal(t.id)
+}
+
+// UnmarshalJSON accepts a number or a hash string.
+func (t *TorrentID) UnmarshalJSON(data []byte) error {
+	var id int
+	if json.Unmarshal(data, &id) == nil {
+		*t = ByID(id)
+		return nil
+	}
+	var hash string
+	if json.Unmarshal(data, &hash) == nil {
+		*t = ByHash(hash)
+		return nil
+	}
+	return fmt.Errorf("invalid torrent id %s", data)
+}
+
+// TorrentIDs is a list of torrents for the ids argument. IDs and hashes
+// may be mixed.
+type TorrentIDs []TorrentID
+
+// IDs returns a TorrentIDs addressing torrents by ID.
+func IDs(ids ...int) TorrentIDs {
+	out := make(TorrentIDs, len(ids))
+	for i, id := range ids {
+		out[i] = ByID(id)
+	}
+	return out
+}
+
+// Hashes returns a TorrentIDs addressing torrents by hash string.
+func Hashes(hashes ...string) TorrentIDs {
+	out := make(TorrentIDs, len(hashes))
+	for i, hash := range hashes {
+		out[i] = ByHash(hash)
+	}
+	return out
+}
+
+// GetTorrentByHash get a torrent by its hash string
+func (ac *TransmissionClient) GetTorrentByHash(hash string) (Torrent, error) {
+	return ac.getTorrent(Hashes(hash))
+}
+
+// StartTorrentByHash start the torrent with the hash string
+func (ac *TransmissionClient) StartTorrentByHash(hash string) (string, error) {
+	return ac.sendSimpleCommand("torrent-start", Hashes(hash))
+}
+
+// StopTorrentByHash stop the torrent with the hash string
+func (ac *TransmissionClient) StopTorrentByHash(hash string) (string, error) {
+	return ac.sendSimpleCommand("torrent-stop", Hashes(hash))
+}
+
+// VerifyTorrentByHash verify the torrent with the hash string
+func (ac *TransmissionClient) VerifyTorrentByHash(hash string) (string, error) {
+	return ac.sendSimpleCommand("torrent-verify", Hashes(hash))
+}
+
+// ReannounceTorrentByHash ask the trackers of the torrent with the hash
+// string for more peers
+func (ac *TransmissionClient) ReannounceTorrentByHash(hash string) (string, error) {
+	return ac.sendSimpleCommand("torrent-reannounce", Hashes(hash))
+}
+
+// NewDelCmdByHash is like NewDelCmd for the torrent with the hash string.
+func NewDelCmdByHash(hash string, removeFile bool) (*Command, error) {
+	cmd, _ := NewDelCmd(0, removeFile)
+	cmd.Arguments.Ids = Hashes(hash)
+	return cmd, nil
+}
@@ -0,0 +1,54 @@
+package transmission
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentIDs(t *testing.T) {
+	Convey("Test mixed IDs and hashes are encoded", t, func() {
+		ids := append(IDs(1, 2), ByHash("875a2d90068c32b4ce7992eb0a8d28f6b4b3f2c8"))
+		data, err := json.Marshal(ids)
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `[1,2,"875a2d90068c32b4ce7992eb0a8d28f6b4b3f2c8"]`)
+
+		var decoded TorrentIDs
+		So(json.Unmarshal(data, &decoded), ShouldBeNil)
+		So(decoded, ShouldResemble, ids)
+
+		id, ok := decoded[0].ID()
+		So(ok, ShouldBeTrue)
+		So(id, ShouldEqual, 1)
+		hash, ok := decoded[2].Hash()
+		So(ok, ShouldBeTrue)
+		So(hash, ShouldEqual, decoded[2].String())
+
+		So(json.Unmarshal([]byte(`[true]`), &decoded), ShouldNotBeNil)
+	})
+
+	Convey("Test the remove command by hash", t, func() {
+		cmd, err := NewDelCmdByHash("875a2d90068c32b4", true)
+		So(err, ShouldBeNil)
+		data, _ := json.Marshal(cmd.Arguments.Ids)
+		So(string(data), ShouldEqual, `["875a2d90068c32b4"]`)
+		So(cmd.Arguments.DeleteData, ShouldBeTrue)
+	})
+}
+
+func TestTorrentByHash(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":5,
+  "hashString":"875a2d90068c32b4ce7992eb0a8d28f6b4b3f2c8"}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test addressing a torrent by hash", t, func() {
+		torrent, err := transmissionClient.GetTorrentByHash("875a2d90068c32b4ce7992eb0a8d28f6b4b3f2c8")
+		So(err, ShouldBeNil)
+		So(torrent.ID, ShouldEqual, 5)
+
+		result, err := transmissionClient.StartTorrentByHash(torrent.HashString)
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, "success")
+	})
+}
@@ -0,0 +1,195 @@
+package transmission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+)
+
+// IterOptions configures TorrentsIter.
+type IterOptions struct {
+	// Fields are the fields to fetch. Defaults to the ones GetTorrents
+	// fetches.
+	Fields []string
+	// ChunkSize is the number of torrents fetched per request. The IDs
+	// are listed first and the torrents then fetched ChunkSize at a time.
+	// With 0 they are all fetched in one request.
+	ChunkSize int
+}
+
+// TorrentsIter returns the torrents one at a time, decoding them from the
+// response as the loop asks for them instead of all at once, so
+// instances with many torrents can be walked without holding them all in
+// memory:
+//
+//	for torrent, err := range client.TorrentsIter(ctx, IterOptions{ChunkSize: 500}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// An error ends the iteration. Torrents removed between the listing and
+// the fetch of their chunk are skipped. Responses are decoded with
+// encoding/json whatever the JSONCodec of the client.
+func (ac *TransmissionClient) TorrentsIter(ctx context.Context, opts IterOptions) iter.Seq2[Torrent, error] {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = torrentGetFields
+	}
+	return func(yield func(Torrent, error) bool) {
+		if opts.ChunkSize <= 0 {
+			_, err := ac.streamTorrents(ctx, fields, nil, func(t Torrent) bool {
+				return yield(t, nil)
+			})
+			if err != nil {
+				yield(Torrent{}, err)
+			}
+			return
+		}
+
+		var ids []int
+		_, err := ac.streamTorrents(ctx, []string{"id"}, nil, func(t Torrent) bool {
+			ids = append(ids, t.ID)
+			return true
+		})
+		if err != nil {
+			yield(Torrent{}, err)
+			return
+		}
+		for start := 0; start < len(ids); start += opts.ChunkSize {
+			end := min(start+opts.ChunkSize, len(ids))
+			more, err := ac.streamTorrents(ctx, fields, IDs(ids[start:end]...), func(t Torrent) bool {
+				return yield(t, nil)
+			})
+			if err != nil {
+				yield(Torrent{}, err)
+				return
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// streamTorrents sends torrent-get for ids, all torrents if ids is nil, and
+// passes the torrents of the response to yield as they are decoded. It
+// returns false when yield did.
+func (ac *TransmissionClient) streamTorrents(ctx context.Context, fields []string, ids TorrentIDs,
+	yield func(Torrent) bool) (bool, error) {
+	request := rpcRequest{Method: "torrent-get", Arguments: torrentGetArgs{Fields: fields, Ids: ids}, Tag: nextTag()}
+	body, err := ac.apiclient.jsonCodec().Marshal(request)
+	if err != nil {
+		return false, err
+	}
+	_, output, err := ac.apiclient.post(ctx, request.Method, body)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		result string
+		tag    int
+	)
+	more, err := decodeTorrentStream(output, &result, &tag, yield)
+	// The result usually follows the torrents, so a response the loop
+	// stopped reading isn't counted.
+	if !more {
+		return false, nil
+	}
+	if err == nil {
+		err = checkTag(request.Tag, tag)
+	}
+	ac.apiclient.response(request.Method, result, err)
+	if err != nil {
+		return false, err
+	}
+	return true, resultError(result)
+}
+
+// decodeTorrentStream walks a torrent-get response token by token, decoding
+// arguments.torrents one element at a time and skipping everything else
+// but the result and the tag.
+func decodeTorrentStream(data []byte, result *string, tag *int, yield func(Torrent) bool) (bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	err := expectDelim(dec, '{')
+	if err != nil {
+		return true, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return true, err
+		}
+		switch key {
+		case "arguments":
+			more, err := decodeTorrentArguments(dec, yield)
+			if !more || err != nil {
+				return more, err
+			}
+		case "result":
+			err = dec.Decode(result)
+		case "tag":
+			err = dec.Decode(tag)
+		default:
+			err = dec.Decode(&json.RawMessage{})
+		}
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, expectDelim(dec, '}')
+}
+
+func decodeTorrentArguments(dec *json.Decoder, yield func(Torrent) bool) (bool, error) {
+	err := expectDelim(dec, '{')
+	if err != nil {
+		return true, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return true, err
+		}
+		if key != "torrents" {
+			err = dec.Decode(&json.RawMessage{})
+			if err != nil {
+				return true, err
+			}
+			continue
+		}
+		err = expectDelim(dec, '[')
+		if err != nil {
+			return true, err
+		}
+		for dec.More() {
+			var torrent Torrent
+			err = dec.Decode(&torrent)
+			if err != nil {
+				return true, err
+			}
+			if !yield(torrent) {
+				return false, nil
+			}
+		}
+		err = expectDelim(dec, ']')
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected %v in response, want %v", token, delim)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentsIter(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var request struct {
+			Arguments struct {
+				Fields []string `json:"fields"`
+				Ids    []int    `json:"ids"`
+			} `json:"arguments"`
+			Tag int `json:"tag"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, fmt.Sprint(request.Arguments.Fields, request.Arguments.Ids))
+
+		ids := request.Arguments.Ids
+		if ids == nil {
+			ids = []int{1, 2, 3, 4, 5}
+		}
+		torrents := make([]string, len(ids))
+		for i, id := range ids {
+			torrents[i] = fmt.Sprintf(`{"id":%d,"name":"t%d"}`, id, id)
+		}
+		fmt.Fprintf(w, `{"arguments":{"torrents":[%s],"removed":[]},"result":"success","tag":%d}`,
+			strings.Join(torrents, ","), request.Tag)
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	Convey("Test all torrents are fetched in one request", t, func() {
+		requests = nil
+		var names []string
+		for torrent, err := range client.TorrentsIter(context.Background(), IterOptions{Fields: []string{"id", "name"}}) {
+			So(err, ShouldBeNil)
+			names = append(names, torrent.Name)
+		}
+		So(names, ShouldResemble, []string{"t1", "t2", "t3", "t4", "t5"})
+		So(requests, ShouldResemble, []string{"[id name] []"})
+	})
+
+	Convey("Test torrents are fetched in chunks", t, func() {
+		requests = nil
+		var ids []int
+		for torrent, err := range client.TorrentsIter(context.Background(), IterOptions{Fields: []string{"id", "name"}, ChunkSize: 2}) {
+			So(err, ShouldBeNil)
+			ids = append(ids, torrent.ID)
+		}
+		So(ids, ShouldResemble, []int{1, 2, 3, 4, 5})
+		So(requests, ShouldResemble, []string{"[id] []", "[id name] [1 2]", "[id name] [3 4]", "[id name] [5]"})
+	})
+
+	Convey("Test breaking out of the loop stops fetching", t, func() {
+		requests = nil
+		for torrent := range client.TorrentsIter(context.Background(), IterOptions{ChunkSize: 2}) {
+			if torrent.ID == 2 {
+				break
+			}
+		}
+		So(requests, ShouldHaveLength, 2)
+	})
+}
+
+func TestTorrentsIterErrors(t *testing.T) {
+	Convey("Test a failed request ends the iteration with its error", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"arguments":{},"result":"no such method"}`))
+		}))
+		defer server.Close()
+		client := New(server.URL, "", "")
+
+		var errs []error
+		for _, err := range client.TorrentsIter(context.Background(), IterOptions{}) {
+			errs = append(errs, err)
+		}
+		So(errs, ShouldHaveLength, 1)
+		So(errs[0].Error(), ShouldEqual, "no such method")
+	})
+
+	Convey("Test a malformed response is reported", t, func() {
+		var result string
+		var tag int
+		_, err := decodeTorrentStream([]byte(`{"arguments":{"torrents":{}}}`), &result, &tag, func(Torrent) bool { return true })
+		So(err, ShouldNotBeNil)
+	})
+}
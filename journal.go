@@ -0,0 +1,157 @@
+package transmission
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// JournalStore keeps the events of a Watcher, so an application that was
+// restarted can replay the ones it missed. Events are appended in the
+// order they happened and never changed afterwards.
+type JournalStore interface {
+	Append(events ...Event) error
+	// Since returns the events that happened after t, oldest first.
+	Since(t time.Time) ([]Event, error)
+}
+
+// Replay returns the events the watcher's Journal recorded after since,
+// oldest first.
+func (w *Watcher) Replay(since time.Time) ([]Event, error) {
+	if w.Journal == nil {
+		return nil, errors.New("watcher has no journal")
+	}
+	return w.Journal.Since(since)
+}
+
+// MemoryJournalStore is a JournalStore in memory, for replaying events to
+// consumers that attach after the watcher started.
+type MemoryJournalStore struct {
+	// Limit is the number of events kept, the oldest being dropped first.
+	// 0 keeps all of them.
+	Limit int
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewMemoryJournalStore create a store keeping the last limit events, or
+// all of them if limit is 0.
+func NewMemoryJournalStore(limit int) *MemoryJournalStore {
+	return &MemoryJournalStore{Limit: limit}
+}
+
+// Append records events.
+func (s *MemoryJournalStore) Append(events ...Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	if s.Limit > 0 && len(s.events) > s.Limit {
+		s.events = append([]Event(nil), s.events[len(s.events)-s.Limit:]...)
+	}
+	return nil
+}
+
+// Since returns the events recorded after t.
+func (s *MemoryJournalStore) Since(t time.Time) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return eventsSince(s.events, t), nil
+}
+
+// FileJournalStore is a JournalStore appending events to a file, one JSON
+// object per line, readable only by its owner.
+type FileJournalStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// maxJournalLine bounds a line of the journal, an event with a torrent
+// with many files.
+const maxJournalLine = 16 << 20
+
+// NewFileJournalStore create a store in the file at path. The file is
+// created on the first append.
+func NewFileJournalStore(path string) *FileJournalStore {
+	return &FileJournalStore{Path: path}
+}
+
+// Append writes events to the end of the file in a single write.
+func (s *FileJournalStore) Append(events ...Event) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		err := encoder.Encode(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := os.OpenFile(s.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(buf.Bytes())
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Since reads the events recorded after t. A last line cut short by a
+// crash while appending is skipped.
+func (s *FileJournalStore) Since(t time.Time) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := os.Open(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		events []Event
+		broken error
+	)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxJournalLine)
+	for scanner.Scan() {
+		if broken != nil {
+			return nil, broken
+		}
+		var event Event
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			broken = err
+			continue
+		}
+		events = append(events, event)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	return eventsSince(events, t), nil
+}
+
+// eventsSince returns the events after t, which are at the end as events
+// are appended in order.
+func eventsSince(events []Event, t time.Time) []Event {
+	i := len(events)
+	for i > 0 && events[i-1].Time.After(t) {
+		i--
+	}
+	return append([]Event(nil), events[i:]...)
+}
@@ -0,0 +1,105 @@
+package transmission
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMemoryJournalStore(t *testing.T) {
+	Convey("Test the memory journal keeps the last events", t, func() {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := NewMemoryJournalStore(2)
+		for i := 1; i <= 3; i++ {
+			So(store.Append(Event{Type: EventAdded, Torrent: Torrent{ID: i}, Time: start.Add(time.Duration(i) * time.Minute)}), ShouldBeNil)
+		}
+
+		events, err := store.Since(time.Time{})
+		So(err, ShouldBeNil)
+		So(events, ShouldHaveLength, 2)
+		So(events[0].Torrent.ID, ShouldEqual, 2)
+
+		events, err = store.Since(start.Add(2 * time.Minute))
+		So(err, ShouldBeNil)
+		So(events, ShouldHaveLength, 1)
+		So(events[0].Torrent.ID, ShouldEqual, 3)
+	})
+}
+
+func TestFileJournalStore(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	Convey("Test events survive a restart", t, func() {
+		path := filepath.Join(t.TempDir(), "journal")
+		events, err := NewFileJournalStore(path).Since(time.Time{})
+		So(err, ShouldBeNil)
+		So(events, ShouldBeEmpty)
+
+		So(NewFileJournalStore(path).Append(
+			Event{Type: EventDaemonRestarted, Remapped: map[int]int{1: 5}, Time: start},
+			Event{Type: EventCompleted, Torrent: Torrent{ID: 5, HashString: "aaaa", Name: "album"}, Time: start.Add(time.Minute)},
+		), ShouldBeNil)
+
+		events, err = NewFileJournalStore(path).Since(start)
+		So(err, ShouldBeNil)
+		So(events, ShouldHaveLength, 1)
+		So(events[0].Type, ShouldEqual, EventCompleted)
+		So(events[0].Torrent.Name, ShouldEqual, "album")
+		So(events[0].Time.Equal(start.Add(time.Minute)), ShouldBeTrue)
+
+		events, err = NewFileJournalStore(path).Since(time.Time{})
+		So(err, ShouldBeNil)
+		So(events[0].Remapped, ShouldResemble, map[int]int{1: 5})
+
+		info, err := os.Stat(path)
+		So(err, ShouldBeNil)
+		So(info.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+	})
+
+	Convey("Test a line cut short by a crash is skipped", t, func() {
+		path := filepath.Join(t.TempDir(), "journal")
+		store := NewFileJournalStore(path)
+		So(store.Append(Event{Type: EventAdded, Time: start}), ShouldBeNil)
+		file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+		file.WriteString(`{"Type":3,"Torr`)
+		file.Close()
+
+		events, err := store.Since(time.Time{})
+		So(err, ShouldBeNil)
+		So(events, ShouldHaveLength, 1)
+
+		file, _ = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+		file.WriteString("\n")
+		file.Close()
+		So(store.Append(Event{Type: EventAdded, Time: start}), ShouldBeNil)
+		_, err = store.Since(time.Time{})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestWatcherJournal(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"name":"a","leftUntilDone":0,"percentDone":1,"sizeWhenDone":100}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the watcher records its events for replay", t, func() {
+		w := NewWatcher(&transmissionClient)
+		_, err := w.Replay(time.Time{})
+		So(err, ShouldNotBeNil)
+
+		w.Journal = NewMemoryJournalStore(0)
+		w.primed = true
+		w.last = Torrents{{ID: 1, Name: "a", LeftUntilDone: 50, PercentDone: 0.5, SizeWhenDone: 100}}
+		before := time.Now().Add(-time.Second)
+		events, err := w.Poll()
+		So(err, ShouldBeNil)
+
+		replayed, err := w.Replay(before)
+		So(err, ShouldBeNil)
+		So(replayed, ShouldResemble, events)
+		So(replayed[1].Type, ShouldEqual, EventCompleted)
+	})
+}
@@ -0,0 +1,104 @@
+package transmission
+
+import (
+	"context"
+	"strings"
+)
+
+// SetTorrentLabels replace the labels of the torrent. Requires RPC version
+// 16 (Transmission 3.0).
+func (ac *TransmissionClient) SetTorrentLabels(id int, labels []string) error {
+	return ac.setTorrentLabels(context.Background(), id, labels)
+}
+
+func (ac *TransmissionClient) setTorrentLabels(ctx context.Context, id int, labels []string) error {
+	err := ac.requireRPCVersionContext(ctx, "labels", 16)
+	if err != nil {
+		return err
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	return ac.setTorrentsContext(ctx, IDs(id), map[string]interface{}{"labels": labels})
+}
+
+// HasLabel reports whether the torrent has the given label.
+func (t Torrent) HasLabel(label string) bool {
+	for _, l := range t.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// RelabelTorrents pass the labels of every torrent selector accepts through
+// transform and save the ones that changed. Torrents ending up with the
+// same labels are updated with a single request. It returns the IDs of
+// the torrents whose labels changed, also when a later request failed.
+func (ac *TransmissionClient) RelabelTorrents(selector func(Torrent) bool, transform func(labels []string) []string) ([]int, error) {
+	err := ac.requireRPCVersion("labels", 16)
+	if err != nil {
+		return nil, err
+	}
+	torrents, err := ac.GetTorrents()
+	if err != nil {
+		return nil, err
+	}
+
+	// Batches are keyed by the new labels joined with a separator that
+	// can't appear in a label.
+	var order []string
+	batches := map[string]TorrentIDs{}
+	labelSets := map[string][]string{}
+	var selected []int
+	batchOf := map[int]string{}
+	for _, torrent := range torrents {
+		if !selector(torrent) {
+			continue
+		}
+		labels := transform(append([]string(nil), torrent.Labels...))
+		if labels == nil {
+			labels = []string{}
+		}
+		if equalLabels(labels, torrent.Labels) {
+			continue
+		}
+		key := strings.Join(labels, "\x00")
+		if _, ok := batches[key]; !ok {
+			order = append(order, key)
+			labelSets[key] = labels
+		}
+		batches[key] = append(batches[key], ByID(torrent.ID))
+		selected = append(selected, torrent.ID)
+		batchOf[torrent.ID] = key
+	}
+
+	saved := map[string]bool{}
+	for _, key := range order {
+		err = ac.setTorrents(batches[key], map[string]interface{}{"labels": labelSets[key]})
+		if err != nil {
+			break
+		}
+		saved[key] = true
+	}
+	var changed []int
+	for _, id := range selected {
+		if saved[batchOf[id]] {
+			changed = append(changed, id)
+		}
+	}
+	return changed, err
+}
+
+func equalLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
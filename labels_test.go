@@ -0,0 +1,94 @@
+package transmission
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentLabels(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":16,
+  "torrents":[{"id":1,"labels":["tv","hd"]}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading and setting labels", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.Labels, ShouldResemble, []string{"tv", "hd"})
+		So(torrent.HasLabel("hd"), ShouldBeTrue)
+		So(torrent.HasLabel("movies"), ShouldBeFalse)
+
+		So(transmissionClient.SetTorrentLabels(1, []string{"tv"}), ShouldBeNil)
+		So(transmissionClient.SetTorrentLabels(1, nil), ShouldBeNil)
+	})
+}
+
+func TestRelabelTorrents(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":16,"torrents":[
+  {"id":1,"labels":["TV"]},
+  {"id":2,"labels":["TV","hd"]},
+  {"id":3,"labels":["tv"]},
+  {"id":4,"labels":["Movies"]},
+  {"id":5,"labels":[]},
+  {"id":6,"labels":["TV"]}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test labels are rewritten in batches", t, func() {
+		changed, err := transmissionClient.RelabelTorrents(
+			func(t Torrent) bool { return t.ID != 4 },
+			func(labels []string) []string {
+				for i, label := range labels {
+					labels[i] = strings.ToLower(label)
+				}
+				return labels
+			})
+		So(err, ShouldBeNil)
+		So(changed, ShouldResemble, []int{1, 2, 6})
+
+		// Torrents 1 and 6 share a request.
+		So(transmissionClient.Stats().Requests["torrent-set"], ShouldEqual, 2)
+	})
+}
+
+func TestRelabelTorrentsPartially(t *testing.T) {
+	sets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var request struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Method == "torrent-set" {
+			sets++
+			if sets == 2 {
+				w.Write([]byte(`{"arguments":{},"result":"invalid argument"}`))
+				return
+			}
+		}
+		w.Write([]byte(`{"arguments":{"rpc-version":16,"torrents":[
+  {"id":1,"labels":["TV"]},{"id":2,"labels":["TV","hd"]},{"id":3,"labels":["TV"]}]},"result":"success"}`))
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	Convey("Test the torrents relabelled before a failure are returned", t, func() {
+		changed, err := client.RelabelTorrents(
+			func(t Torrent) bool { return true },
+			func(labels []string) []string {
+				for i, label := range labels {
+					labels[i] = strings.ToLower(label)
+				}
+				return labels
+			})
+		So(err, ShouldNotBeNil)
+		So(changed, ShouldResemble, []int{1, 3})
+	})
+}
@@ -0,0 +1,91 @@
+package transmission
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DecodeWarning reports a torrent field that couldn't be decoded and was
+// left at its zero value.
+type DecodeWarning struct {
+	ID    int
+	Field string
+	Err   error
+}
+
+func (w DecodeWarning) Error() string {
+	return fmt.Sprintf("torrent %d: field %s: %v", w.ID, w.Field, w.Err)
+}
+
+// TorrentsResult holds torrents decoded leniently.
+type TorrentsResult struct {
+	Torrents Torrents
+	Warnings []DecodeWarning
+}
+
+// GetTorrentsLenient is like GetTorrents, but a field whose type doesn't
+// match, as happens between daemon versions, is skipped and reported as a
+// warning instead of failing the whole call.
+func (ac *TransmissionClient) GetTorrentsLenient() (TorrentsResult, error) {
+	cmd, _ := NewGetTorrentsCmd()
+	var out struct {
+		Torrents []json.RawMessage `json:"torrents"`
+	}
+	err := ac.rpc("torrent-get", map[string]interface{}{"fields": cmd.Arguments.Fields}, &out)
+	if err != nil {
+		return TorrentsResult{}, err
+	}
+
+	var result TorrentsResult
+	for _, raw := range out.Torrents {
+		torrent, warnings, err := decodeTorrentLenient(raw)
+		if err != nil {
+			return result, err
+		}
+		result.Torrents = append(result.Torrents, torrent)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+	return result, nil
+}
+
+// decodeTorrentLenient decodes raw, dropping the fields that fail to
+// decode. Only malformed JSON is an error.
+func decodeTorrentLenient(raw json.RawMessage) (Torrent, []DecodeWarning, error) {
+	var torrent Torrent
+	err := json.Unmarshal(raw, &torrent)
+	if err == nil {
+		return torrent, nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(raw, &fields)
+	if err != nil {
+		return Torrent{}, nil, err
+	}
+	var id struct {
+		ID int `json:"id"`
+	}
+	json.Unmarshal(raw, &id)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings []DecodeWarning
+	for _, key := range keys {
+		single, _ := json.Marshal(map[string]json.RawMessage{key: fields[key]})
+		err = json.Unmarshal(single, &Torrent{})
+		if err != nil {
+			warnings = append(warnings, DecodeWarning{ID: id.ID, Field: key, Err: err})
+			delete(fields, key)
+		}
+	}
+
+	valid, _ := json.Marshal(fields)
+	torrent = Torrent{}
+	err = json.Unmarshal(valid, &torrent)
+	return torrent, warnings, err
+}
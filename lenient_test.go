@@ -0,0 +1,34 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetTorrentsLenient(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"name":"good","percentDone":0.5},
+  {"id":2,"name":"odd","percentDone":"0.5","labels":"tv","status":4}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test mismatched fields become warnings", t, func() {
+		_, err := transmissionClient.GetTorrents()
+		So(err, ShouldNotBeNil)
+
+		result, err := transmissionClient.GetTorrentsLenient()
+		So(err, ShouldBeNil)
+		So(result.Torrents, ShouldHaveLength, 2)
+		So(result.Torrents[0].PercentDone, ShouldEqual, 0.5)
+		So(result.Torrents[1].Name, ShouldEqual, "odd")
+		So(result.Torrents[1].Status, ShouldEqual, StatusDownload)
+		So(result.Torrents[1].PercentDone, ShouldEqual, 0)
+
+		So(result.Warnings, ShouldHaveLength, 2)
+		So(result.Warnings[0].ID, ShouldEqual, 2)
+		So(result.Warnings[0].Field, ShouldEqual, "labels")
+		So(result.Warnings[1].Field, ShouldEqual, "percentDone")
+		So(result.Warnings[1].Error(), ShouldStartWith, "torrent 2: field percentDone: ")
+	})
+}
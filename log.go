@@ -0,0 +1,88 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// WithLogger logs the requests of the client, its retries and session id
+// refreshes, and the events of watchers using it to logger. Requests are
+// logged at debug level and failures at warn level. Without it the client
+// logs nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(ac *ApiClient) {
+		ac.logger = logger
+	}
+}
+
+// log is a no-op without a logger.
+func (ac *ApiClient) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if ac.logger == nil {
+		return
+	}
+	ac.logger.Log(ctx, level, msg, args...)
+}
+
+// logRequest logs the outcome of a request at the HTTP level.
+func (ac *ApiClient) logRequest(ctx context.Context, body requestBody, status int, received int, started time.Time, err error) {
+	if ac.logger == nil {
+		return
+	}
+	args := []any{
+		slog.String("method", body.method),
+		slog.Int("status", status),
+		slog.Duration("duration", time.Since(started)),
+		slog.Int64("sent", body.size),
+		slog.Int("received", received),
+	}
+	if err != nil {
+		ac.logger.Log(ctx, slog.LevelWarn, "rpc request failed", append(args, slog.Any("error", err))...)
+		return
+	}
+	ac.logger.Log(ctx, slog.LevelDebug, "rpc request", args...)
+}
+
+// response records the outcome of decoding the response to method.
+func (ac *ApiClient) response(method string, result string, err error) {
+	ac.stats.response(result, err)
+	switch {
+	case err != nil:
+		ac.log(context.Background(), slog.LevelWarn, "rpc response invalid",
+			slog.String("method", method), slog.Any("error", err))
+	case result != "success":
+		ac.log(context.Background(), slog.LevelWarn, "rpc error",
+			slog.String("method", method), slog.String("result", result))
+	}
+}
+
+// rpcMethod returns the method of a request body. Only bodies marshalled
+// by the caller need it; the client passes the method of its own requests
+// along.
+func rpcMethod(body []byte) string {
+	var request struct {
+		Method string `json:"method"`
+	}
+	json.Unmarshal(body, &request)
+	return request.Method
+}
+
+// logEvent logs an event seen by a watcher.
+func (ac *ApiClient) logEvent(event Event) {
+	if event.Type == EventDaemonRestarted {
+		ac.log(context.Background(), slog.LevelInfo, "daemon restarted",
+			slog.Int("remapped", len(event.Remapped)))
+		return
+	}
+	args := []any{
+		slog.String("event", event.Type.String()),
+		slog.Int("id", event.Torrent.ID),
+		slog.String("hash", event.Torrent.HashString),
+		slog.String("name", event.Torrent.Name),
+	}
+	if len(event.Fields) > 0 {
+		args = append(args, slog.Any("fields", event.Fields))
+	}
+	ac.log(context.Background(), slog.LevelDebug, "torrent event", args...)
+}
@@ -0,0 +1,88 @@
+package transmission
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func tLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+func TestLogger(t *testing.T) {
+	Convey("Test requests and RPC errors are logged", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"arguments":{},"result":"no such method"}`))
+		}))
+		defer server.Close()
+		logger, buf := tLogger()
+		client := New(server.URL, "", "", WithLogger(logger))
+
+		err := client.Call(context.Background(), "session-stats", nil, nil)
+		So(err, ShouldNotBeNil)
+		So(buf.String(), ShouldContainSubstring, `level=DEBUG msg="rpc request" method=session-stats status=200`)
+		So(buf.String(), ShouldContainSubstring, `level=WARN msg="rpc error" method=session-stats result="no such method"`)
+	})
+
+	Convey("Test session id refreshes are logged", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Transmission-Session-Id") != "fresh" {
+				w.Header().Set("X-Transmission-Session-Id", "fresh")
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.Write([]byte(`{"arguments":{},"result":"success"}`))
+		}))
+		defer server.Close()
+		logger, buf := tLogger()
+		client := New(server.URL, "", "", WithLogger(logger))
+
+		So(client.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(buf.String(), ShouldContainSubstring, `msg="session id fetched"`)
+	})
+
+	Convey("Test failed requests are logged as warnings", t, func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		logger, buf := tLogger()
+		client := New(server.URL, "", "", WithLogger(logger))
+
+		_, err := client.GetTorrents()
+		So(err, ShouldNotBeNil)
+		So(buf.String(), ShouldContainSubstring, `level=WARN msg="rpc request failed" method=torrent-get`)
+	})
+
+	Convey("Test clients log nothing by default", t, func() {
+		client := New("http://127.0.0.1:1", "", "")
+		So(func() { client.apiclient.log(context.Background(), slog.LevelError, "ignored") }, ShouldNotPanic)
+	})
+}
+
+func TestLoggerWatcherEvents(t *testing.T) {
+	torrents := `{"arguments":{"torrents":[{"id":1,"hashString":"aaaa","name":"a"}]},"result":"success"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Transmission-Session-Id", "token")
+		w.Write([]byte(torrents))
+	}))
+	defer server.Close()
+
+	Convey("Test watcher events are logged", t, func() {
+		logger, buf := tLogger()
+		client := New(server.URL, "", "", WithLogger(logger))
+		watcher := NewWatcher(&client)
+		_, err := watcher.Poll()
+		So(err, ShouldBeNil)
+
+		torrents = `{"arguments":{"torrents":[{"id":1,"hashString":"aaaa","name":"a"},{"id":2,"hashString":"bbbb","name":"b"}]},"result":"success"}`
+		_, err = watcher.Poll()
+		So(err, ShouldBeNil)
+		So(buf.String(), ShouldContainSubstring, `msg="torrent event" event=added id=2 hash=bbbb name=b`)
+	})
+}
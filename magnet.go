@@ -0,0 +1,126 @@
+package transmission
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// GetMagnetLink get the magnet link the daemon generates for the torrent.
+// The magnetLink field isn't part of the default fields, so it is only
+// fetched on demand.
+func (ac *TransmissionClient) GetMagnetLink(id int) (string, error) {
+	torrent, err := ac.fetchTorrent(context.Background(), []string{"id", "magnetLink"}, IDs(id))
+	return torrent.MagnetLink, err
+}
+
+// Magnet is a parsed magnet link.
+type Magnet struct {
+	// InfoHash is the BitTorrent v1 info hash in lowercase hex. It is
+	// empty for v2-only links.
+	InfoHash string
+	// InfoHashV2 is the BitTorrent v2 info hash in lowercase hex, if the
+	// link has one.
+	InfoHashV2 string
+	// Name is the display name, which may be empty.
+	Name     string
+	Trackers []string
+	WebSeeds []string
+}
+
+// ParseMagnet parses and validates a magnet link. Malformed links are
+// rejected with a *ValidationError. Base32 info hashes are converted to
+// hex.
+func ParseMagnet(uri string) (Magnet, error) {
+	invalid := func(reason string) (Magnet, error) {
+		return Magnet{}, &ValidationError{"magnet", uri, reason}
+	}
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "magnet" {
+		return invalid("must be a magnet: URI")
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return invalid("malformed query")
+	}
+
+	var magnet Magnet
+	for _, xt := range query["xt"] {
+		switch {
+		case strings.HasPrefix(xt, "urn:btih:"):
+			hash, ok := parseBTIH(strings.TrimPrefix(xt, "urn:btih:"))
+			if !ok {
+				return invalid("info hash must be 40 hex or 32 base32 characters")
+			}
+			if magnet.InfoHash != "" && magnet.InfoHash != hash {
+				return invalid("conflicting info hashes")
+			}
+			magnet.InfoHash = hash
+		case strings.HasPrefix(xt, "urn:btmh:"):
+			// A multihash: 0x12 for sha2-256, then 0x20 for 32 bytes.
+			hash := strings.ToLower(strings.TrimPrefix(xt, "urn:btmh:"))
+			if !strings.HasPrefix(hash, "1220") || !isHex(hash[4:], 64) {
+				return invalid("v2 info hash must be a sha2-256 multihash")
+			}
+			magnet.InfoHashV2 = hash[4:]
+		}
+	}
+	if magnet.InfoHash == "" && magnet.InfoHashV2 == "" {
+		return invalid("missing info hash")
+	}
+
+	magnet.Name = query.Get("dn")
+	for _, tracker := range query["tr"] {
+		if !hasScheme(tracker, "http", "https", "udp", "ws", "wss") {
+			return invalid("malformed tracker " + tracker)
+		}
+		magnet.Trackers = append(magnet.Trackers, tracker)
+	}
+	for _, seed := range query["ws"] {
+		if !hasScheme(seed, "http", "https") {
+			return invalid("malformed web seed " + seed)
+		}
+		magnet.WebSeeds = append(magnet.WebSeeds, seed)
+	}
+	return magnet, nil
+}
+
+// parseBTIH returns a v1 info hash in lowercase hex.
+func parseBTIH(hash string) (string, bool) {
+	switch len(hash) {
+	case 40:
+		return strings.ToLower(hash), isHex(hash, 40)
+	case 32:
+		data, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash))
+		if err != nil {
+			return "", false
+		}
+		return hex.EncodeToString(data), true
+	}
+	return "", false
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// hasScheme reports whether raw is an absolute URL with a host and one of
+// schemes.
+func hasScheme(raw string, schemes ...string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetMagnetLink(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,
+  "magnetLink":"magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193"}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting the magnet link", t, func() {
+		link, err := transmissionClient.GetMagnetLink(1)
+		So(err, ShouldBeNil)
+		So(link, ShouldEqual, "magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193")
+	})
+}
+
+func TestGetMagnetLinkNotFound(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting the magnet link of a missing torrent", t, func() {
+		_, err := transmissionClient.GetMagnetLink(1)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestParseMagnet(t *testing.T) {
+	Convey("Test parsing a magnet link", t, func() {
+		magnet, err := ParseMagnet("magnet:?xt=urn:btih:875A2D90068C32B4CE7992EAF56CD03F5BE0D193&dn=CentOS+7.0+x64" +
+			"&tr=udp%3A%2F%2Ftracker.example.com%3A80&tr=https%3A%2F%2Ftracker.example.org%2Fannounce" +
+			"&ws=https%3A%2F%2Fmirror.example.com%2Fcentos.iso")
+		So(err, ShouldBeNil)
+		So(magnet.InfoHash, ShouldEqual, "875a2d90068c32b4ce7992eaf56cd03f5be0d193")
+		So(magnet.Name, ShouldEqual, "CentOS 7.0 x64")
+		So(magnet.Trackers, ShouldResemble, []string{"udp://tracker.example.com:80", "https://tracker.example.org/announce"})
+		So(magnet.WebSeeds, ShouldResemble, []string{"https://mirror.example.com/centos.iso"})
+	})
+
+	Convey("Test base32 and v2 info hashes", t, func() {
+		magnet, err := ParseMagnet("magnet:?xt=urn:btih:QWRNFEAGRQZLJTTZSLVPW3IDH5PQ3UMT")
+		So(err, ShouldBeNil)
+		So(magnet.InfoHash, ShouldEqual, "85a2d290068c32b4ce7992eafb6d033f5f0dd193")
+
+		magnet, err = ParseMagnet("magnet:?xt=urn:btmh:1220caf1e1c30e81cb361b9ee167c4aa64228a7fa4fa9f6105232b28ad099f3a302e")
+		So(err, ShouldBeNil)
+		So(magnet.InfoHash, ShouldBeEmpty)
+		So(magnet.InfoHashV2, ShouldEqual, "caf1e1c30e81cb361b9ee167c4aa64228a7fa4fa9f6105232b28ad099f3a302e")
+	})
+
+	Convey("Test malformed magnet links are rejected", t, func() {
+		for _, uri := range []string{
+			"http://example.com/a.torrent",
+			"magnet:?dn=nohash",
+			"magnet:?xt=urn:btih:875a2d90",
+			"magnet:?xt=urn:btih:zz5a2d90068c32b4ce7992eaf56cd03f5be0d193",
+			"magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193&xt=urn:btih:0000000000000000000000000000000000000000",
+			"magnet:?xt=urn:btmh:1114aaaa",
+			"magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193&tr=not-a-url",
+			"magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193&ws=udp%3A%2F%2Fexample.com",
+			"magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193&dn=%zz",
+		} {
+			_, err := ParseMagnet(uri)
+			So(err, ShouldHaveSameTypeAs, &ValidationError{})
+		}
+	})
+
+	Convey("Test AddTorrent rejects a malformed magnet link before sending it", t, func() {
+		client := New("http://127.0.0.1:1", "", "")
+		_, err := client.AddTorrent("magnet:?xt=urn:btih:875a2d90", AddTorrentOptions{})
+		So(err, ShouldHaveSameTypeAs, &ValidationError{})
+	})
+}
@@ -0,0 +1,42 @@
+package transmission
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// metadataPollInterval is how often WaitForMetadata polls.
+var metadataPollInterval = time.Second
+
+// metadataFields are the fields WaitForMetadata polls.
+var metadataFields = []string{"id", "metadataPercentComplete", "error", "errorString"}
+
+// WaitForMetadata waits until the daemon has the metadata of a torrent
+// added from a magnet link, and returns the torrent with its files. It
+// returns right away for a torrent that already has its metadata, e.g. to
+// select files of a torrent added paused. It fails when the torrent is
+// removed or stops on a local error.
+func (ac *TransmissionClient) WaitForMetadata(ctx context.Context, id int) (Torrent, error) {
+	ticker := time.NewTicker(metadataPollInterval)
+	defer ticker.Stop()
+
+	for {
+		torrent, err := ac.fetchTorrent(ctx, metadataFields, IDs(id))
+		if err != nil {
+			return Torrent{}, err
+		}
+		if torrent.MetadataPercentComplete >= 1 {
+			return ac.fetchTorrent(ctx, torrentGetFields, IDs(id))
+		}
+		if torrent.Error == torrentErrorLocal {
+			return Torrent{}, errors.New(torrent.ErrorString)
+		}
+
+		select {
+		case <-ctx.Done():
+			return Torrent{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
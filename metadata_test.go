@@ -0,0 +1,73 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWaitForMetadata(t *testing.T) {
+	defer func(interval time.Duration) { metadataPollInterval = interval }(metadataPollInterval)
+	metadataPollInterval = 5 * time.Millisecond
+
+	var polls int
+	progress := []float64{0, 0.5, 1}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var request struct {
+			Arguments struct {
+				Fields []string `json:"fields"`
+				Ids    []int    `json:"ids"`
+			} `json:"arguments"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Arguments.Ids[0] != 1 {
+			w.Write([]byte(`{"arguments":{"torrents":[]},"result":"success"}`))
+			return
+		}
+		if len(request.Arguments.Fields) > len(metadataFields) {
+			w.Write([]byte(`{"arguments":{"torrents":[{"id":1,"name":"album",
+  "metadataPercentComplete":1,"files":[{"name":"album/01.flac","length":10}]}]},"result":"success"}`))
+			return
+		}
+		done := progress[min(polls, len(progress)-1)]
+		polls++
+		fmt.Fprintf(w, `{"arguments":{"torrents":[{"id":1,"metadataPercentComplete":%v}]},"result":"success"}`, done)
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	Convey("Test waiting until the metadata is complete", t, func() {
+		polls = 0
+		torrent, err := client.WaitForMetadata(context.Background(), 1)
+		So(err, ShouldBeNil)
+		So(polls, ShouldEqual, 3)
+		So(torrent.Name, ShouldEqual, "album")
+		So(torrent.Files, ShouldHaveLength, 1)
+	})
+
+	Convey("Test waiting stops when the context is done", t, func() {
+		polls = 0
+		progress = []float64{0}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := client.WaitForMetadata(ctx, 1)
+		So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+	})
+
+	Convey("Test waiting for a removed torrent fails", t, func() {
+		_, err := client.WaitForMetadata(context.Background(), 2)
+		So(err, ShouldNotBeNil)
+	})
+}
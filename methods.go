@@ -0,0 +1,109 @@
+package transmission
+
+import (
+	"context"
+	"fmt"
+)
+
+// The requests the client makes itself use the arguments and results below
+// rather than Command, so a request only carries the fields its method
+// defines and a response can't fill in fields that belong to another
+// method.
+
+// rpcResponse is the envelope of a response whose arguments are a T.
+// status is the HTTP status it came with, or 0 if there was no response.
+type rpcResponse[T any] struct {
+	Arguments T      `json:"arguments"`
+	Result    string `json:"result"`
+	Tag       int    `json:"tag"`
+	status    int
+}
+
+// idsArgs are the arguments of the methods that only take the torrents to
+// act on, such as torrent-start and queue-move-top.
+type idsArgs struct {
+	Ids TorrentIDs `json:"ids,omitempty"`
+}
+
+// torrentGetArgs are the arguments of torrent-get. Without Ids all
+// torrents are returned.
+type torrentGetArgs struct {
+	Fields []string   `json:"fields"`
+	Ids    TorrentIDs `json:"ids,omitempty"`
+}
+
+type torrentGetResult struct {
+	Torrents Torrents `json:"torrents"`
+}
+
+// torrentAddArgs are the arguments of torrent-add. Filename is a magnet
+// link, a URL or a path on the daemon's host; MetaInfo the base64 content
+// of a .torrent file instead.
+type torrentAddArgs struct {
+	Filename    string   `json:"filename,omitempty"`
+	MetaInfo    string   `json:"metainfo,omitempty"`
+	DownloadDir string   `json:"download-dir,omitempty"`
+	Paused      bool     `json:"paused,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Cookies     string   `json:"cookies,omitempty"`
+}
+
+// torrentAddResult is the result of torrent-add, which has the torrent
+// under torrent-duplicate when the daemon already had it.
+type torrentAddResult struct {
+	TorrentAdded     *TorrentAdded `json:"torrent-added"`
+	TorrentDuplicate *TorrentAdded `json:"torrent-duplicate"`
+}
+
+type torrentRemoveArgs struct {
+	Ids        TorrentIDs `json:"ids"`
+	DeleteData bool       `json:"delete-local-data,omitempty"`
+}
+
+type torrentSetLocationArgs struct {
+	Ids      TorrentIDs `json:"ids"`
+	Location string     `json:"location"`
+	Move     bool       `json:"move,omitempty"`
+}
+
+// roundTrip sends method with args and decodes the response, arguments
+// included, in one pass. A 5xx status is returned as an error, the result
+// isn't checked.
+func roundTrip[T any](ctx context.Context, ac *TransmissionClient, method string, args interface{}) (rpcResponse[T], error) {
+	var response rpcResponse[T]
+	request := rpcRequest{Method: method, Arguments: args, Tag: nextTag()}
+	body, err := ac.apiclient.jsonCodec().Marshal(request)
+	if err != nil {
+		return response, err
+	}
+	status, output, err := ac.apiclient.post(ctx, method, body)
+	response.status = status
+	if err != nil {
+		return response, err
+	}
+	if status >= 500 {
+		err = fmt.Errorf("%s: HTTP %d", method, status)
+		ac.apiclient.response(method, "", err)
+		return response, err
+	}
+	err = ac.apiclient.jsonCodec().Unmarshal(output, &response)
+	if err == nil {
+		err = checkTag(request.Tag, response.Tag)
+	}
+	ac.apiclient.response(method, response.Result, err)
+	return response, err
+}
+
+// invoke is roundTrip returning only the arguments. A result other than
+// "success" is returned as an error.
+func invoke[T any](ctx context.Context, ac *TransmissionClient, method string, args interface{}) (T, error) {
+	response, err := roundTrip[T](ctx, ac, method, args)
+	if err == nil {
+		err = resultError(response.Result)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return response.Arguments, nil
+}
@@ -0,0 +1,52 @@
+package transmission
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMethodArguments(t *testing.T) {
+	var request string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		request = string(body)
+		w.Write([]byte(`{"arguments":{"torrents":[{"id":1,"magnetLink":"magnet:?xt=urn:btih:aaaa"}],
+  "torrent-added":{"id":2}},"result":"success"}`))
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	Convey("Test requests only carry the arguments of their method", t, func() {
+		_, err := client.GetMagnetLink(1)
+		So(err, ShouldBeNil)
+		So(request, ShouldContainSubstring, `"arguments":{"fields":["id","magnetLink"],"ids":[1]}`)
+
+		_, err = client.StartTorrent(1)
+		So(err, ShouldBeNil)
+		So(request, ShouldContainSubstring, `"arguments":{"ids":[1]}`)
+
+		So(client.SetLocation(1, "/data", true), ShouldBeNil)
+		So(request, ShouldContainSubstring, `"arguments":{"ids":[1],"location":"/data","move":true}`)
+	})
+
+	Convey("Test responses only fill the result of their method", t, func() {
+		result, err := invoke[torrentGetResult](context.Background(), &client, "torrent-get",
+			torrentGetArgs{Fields: []string{"id"}})
+		So(err, ShouldBeNil)
+		So(result.Torrents, ShouldHaveLength, 1)
+
+		added, err := invoke[torrentAddResult](context.Background(), &client, "torrent-add", nil)
+		So(err, ShouldBeNil)
+		So(added.TorrentAdded.ID, ShouldEqual, 2)
+	})
+}
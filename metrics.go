@@ -0,0 +1,280 @@
+package transmission
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metrics is a snapshot of the session and torrent metrics of a daemon.
+type Metrics struct {
+	Time     time.Time
+	Session  SessionStats
+	Torrents Torrents
+}
+
+// CollectMetrics fetches the session statistics and the torrents.
+func (ac *TransmissionClient) CollectMetrics() (Metrics, error) {
+	stats, err := ac.GetSessionStats()
+	if err != nil {
+		return Metrics{}, err
+	}
+	torrents, err := ac.GetTorrents()
+	if err != nil {
+		return Metrics{}, err
+	}
+	return Metrics{Time: time.Now(), Session: stats, Torrents: torrents}, nil
+}
+
+// metric is a single named value. Counters and sizes are integers so
+// InfluxDB stores them as such.
+type metric struct {
+	name    string
+	integer int64
+	float   float64
+	isFloat bool
+}
+
+func intMetric(name string, value int64) metric {
+	return metric{name: name, integer: value}
+}
+
+func floatMetric(name string, value float64) metric {
+	return metric{name: name, float: value, isFloat: true}
+}
+
+func sessionMetrics(s SessionStats) []metric {
+	return []metric{
+		intMetric("torrents", int64(s.TorrentCount)),
+		intMetric("active_torrents", int64(s.ActiveTorrentCount)),
+		intMetric("paused_torrents", int64(s.PausedTorrentCount)),
+		intMetric("download_rate", int64(s.DownloadSpeed)),
+		intMetric("upload_rate", int64(s.UploadSpeed)),
+		intMetric("downloaded_bytes", int64(s.CumulativeStats.DownloadedBytes)),
+		intMetric("uploaded_bytes", int64(s.CumulativeStats.UploadedBytes)),
+	}
+}
+
+func torrentMetrics(t Torrent) []metric {
+	return []metric{
+		floatMetric("percent_done", t.PercentDone),
+		floatMetric("ratio", t.UploadRatio),
+		intMetric("download_rate", int64(t.RateDownload)),
+		intMetric("upload_rate", int64(t.RateUpload)),
+		intMetric("downloaded_bytes", int64(t.DownloadedEver)),
+		intMetric("uploaded_bytes", int64(t.UploadedEver)),
+		intMetric("left_bytes", int64(t.LeftUntilDone)),
+		intMetric("size_bytes", int64(t.SizeWhenDone)),
+		intMetric("status", int64(t.Status)),
+	}
+}
+
+// torrentTags returns the tags identifying a torrent. The tracker is the
+// TrackerIdentity of its first tracker.
+func torrentTags(t Torrent) map[string]string {
+	tags := map[string]string{
+		"hash": t.HashString,
+		"name": t.Name,
+	}
+	if len(t.TrackerStats) > 0 {
+		tags["tracker"] = trackerHost(t.TrackerStats[0])
+	}
+	if len(t.Labels) > 0 {
+		tags["labels"] = strings.Join(t.Labels, ",")
+	}
+	return tags
+}
+
+// WriteInflux writes the metrics in InfluxDB line protocol, one
+// "transmission_session" line and a "transmission_torrent" line per
+// torrent. tags are added to every line.
+func (m Metrics) WriteInflux(w io.Writer, tags map[string]string) error {
+	var buf bytes.Buffer
+	timestamp := m.Time.UnixNano()
+	writeInfluxLine(&buf, "transmission_session", tags, nil, sessionMetrics(m.Session), timestamp)
+	for _, torrent := range m.Torrents {
+		writeInfluxLine(&buf, "transmission_torrent", tags, torrentTags(torrent), torrentMetrics(torrent), timestamp)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeInfluxLine(buf *bytes.Buffer, measurement string, common, own map[string]string, metrics []metric, timestamp int64) {
+	tags := make(map[string]string, len(common)+len(own))
+	for key, value := range common {
+		tags[key] = value
+	}
+	for key, value := range own {
+		tags[key] = value
+	}
+	keys := make([]string, 0, len(tags))
+	for key, value := range tags {
+		// Empty tag values aren't allowed.
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	buf.WriteString(influxEscaper.Replace(measurement))
+	for _, key := range keys {
+		buf.WriteByte(',')
+		buf.WriteString(influxTagEscaper.Replace(key))
+		buf.WriteByte('=')
+		buf.WriteString(influxTagEscaper.Replace(tags[key]))
+	}
+	for i, metric := range metrics {
+		if i == 0 {
+			buf.WriteByte(' ')
+		} else {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(influxTagEscaper.Replace(metric.name))
+		buf.WriteByte('=')
+		if metric.isFloat {
+			buf.WriteString(strconv.FormatFloat(metric.float, 'g', -1, 64))
+		} else {
+			buf.WriteString(strconv.FormatInt(metric.integer, 10))
+			buf.WriteByte('i')
+		}
+	}
+	fmt.Fprintf(buf, " %d\n", timestamp)
+}
+
+var (
+	influxEscaper    = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	influxTagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+)
+
+// WriteStatsd writes the metrics as statsd gauges named
+// "<prefix>.session.<metric>" and "<prefix>.torrent.<hash>.<metric>". Every
+// gauge is written separately, so each ends up in its own packet when w is
+// a UDP connection.
+func (m Metrics) WriteStatsd(w io.Writer, prefix string) error {
+	if prefix == "" {
+		prefix = "transmission"
+	}
+	for _, metric := range sessionMetrics(m.Session) {
+		err := writeStatsdGauge(w, prefix+".session."+metric.name, metric)
+		if err != nil {
+			return err
+		}
+	}
+	for _, torrent := range m.Torrents {
+		name := prefix + ".torrent." + statsdName(torrent.HashString) + "."
+		for _, metric := range torrentMetrics(torrent) {
+			err := writeStatsdGauge(w, name+metric.name, metric)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeStatsdGauge writes metric as a gauge. statsd reads a signed value
+// as a change to the gauge, so a negative one, such as the ratio of a
+// torrent with nothing downloaded, is set by zeroing the gauge first.
+func writeStatsdGauge(w io.Writer, name string, metric metric) error {
+	value := strconv.FormatInt(metric.integer, 10)
+	negative := metric.integer < 0
+	if metric.isFloat {
+		value = strconv.FormatFloat(metric.float, 'f', -1, 64)
+		negative = metric.float < 0
+	}
+	line := name + ":" + value + "|g\n"
+	if negative {
+		line = name + ":0|g\n" + line
+	}
+	_, err := io.WriteString(w, line)
+	return err
+}
+
+// statsdName replaces the characters statsd uses as separators.
+func statsdName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '.', ' ', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// MetricsFormat selects the output of a MetricsExporter.
+type MetricsFormat int
+
+const (
+	// FormatInflux writes InfluxDB line protocol.
+	FormatInflux MetricsFormat = iota
+	// FormatStatsd writes statsd gauges.
+	FormatStatsd
+)
+
+// MetricsExporter periodically writes the metrics of a client to a writer,
+// e.g. an HTTP request body for InfluxDB or a UDP connection to statsd.
+type MetricsExporter struct {
+	Format MetricsFormat
+	// Tags are added to every line in FormatInflux.
+	Tags map[string]string
+	// Prefix of the gauge names in FormatStatsd. Defaults to
+	// "transmission".
+	Prefix string
+	// Interval between exports in Run. Defaults to ten seconds.
+	Interval time.Duration
+	// OnError is called when an export fails in Run.
+	OnError func(error)
+
+	client *TransmissionClient
+	w      io.Writer
+}
+
+// NewMetricsExporter create an exporter writing the metrics of client to w
+func NewMetricsExporter(client *TransmissionClient, w io.Writer, format MetricsFormat) *MetricsExporter {
+	return &MetricsExporter{
+		Format:   format,
+		Interval: 10 * time.Second,
+		client:   client,
+		w:        w,
+	}
+}
+
+// Export collects and writes the metrics once.
+func (e *MetricsExporter) Export() error {
+	metrics, err := e.client.CollectMetrics()
+	if err != nil {
+		return err
+	}
+	if e.Format == FormatStatsd {
+		return metrics.WriteStatsd(e.w, e.Prefix)
+	}
+	return metrics.WriteInflux(e.w, e.Tags)
+}
+
+// Run exports every interval until ctx is done.
+func (e *MetricsExporter) Run(ctx context.Context) error {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := e.Export()
+		if err != nil && e.OnError != nil {
+			e.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
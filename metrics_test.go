@@ -0,0 +1,77 @@
+package transmission
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func tMetrics() Metrics {
+	return Metrics{
+		Time: time.Unix(1700000000, 0),
+		Session: SessionStats{TorrentCount: 2, ActiveTorrentCount: 1, DownloadSpeed: 1500,
+			CumulativeStats: TransferStats{DownloadedBytes: 4096}},
+		Torrents: Torrents{{
+			HashString:   "aaaa",
+			Name:         "my show, s01",
+			Labels:       []string{"tv", "hd"},
+			PercentDone:  0.5,
+			UploadRatio:  1.25,
+			RateDownload: 300,
+			TrackerStats: []TrackerStat{{Announce: "https://tracker.example.org/announce"}},
+		}},
+	}
+}
+
+func TestWriteInflux(t *testing.T) {
+	Convey("Test writing line protocol", t, func() {
+		var buf bytes.Buffer
+		So(tMetrics().WriteInflux(&buf, map[string]string{"host": "nas", "empty": ""}), ShouldBeNil)
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		So(lines, ShouldHaveLength, 2)
+		So(lines[0], ShouldEqual, "transmission_session,host=nas torrents=2i,active_torrents=1i,paused_torrents=0i,"+
+			"download_rate=1500i,upload_rate=0i,downloaded_bytes=4096i,uploaded_bytes=0i 1700000000000000000")
+		So(lines[1], ShouldStartWith, `transmission_torrent,hash=aaaa,host=nas,labels=tv\,hd,name=my\ show\,\ s01,tracker=example.org `)
+		So(lines[1], ShouldContainSubstring, " percent_done=0.5,ratio=1.25,download_rate=300i,")
+	})
+}
+
+func TestWriteStatsd(t *testing.T) {
+	Convey("Test writing statsd gauges", t, func() {
+		var buf bytes.Buffer
+		So(tMetrics().WriteStatsd(&buf, ""), ShouldBeNil)
+		So(buf.String(), ShouldStartWith, "transmission.session.torrents:2|g\n")
+		So(buf.String(), ShouldContainSubstring, "transmission.torrent.aaaa.percent_done:0.5|g\n")
+		So(buf.String(), ShouldContainSubstring, "transmission.torrent.aaaa.download_rate:300|g\n")
+		So(statsdName("a.b:c|d"), ShouldEqual, "a_b_c_d")
+	})
+
+	Convey("Test negative gauges are zeroed first", t, func() {
+		var buf bytes.Buffer
+		So(writeStatsdGauge(&buf, "ratio", metric{float: -1, isFloat: true}), ShouldBeNil)
+		So(writeStatsdGauge(&buf, "eta", metric{integer: -2}), ShouldBeNil)
+		So(buf.String(), ShouldEqual, "ratio:0|g\nratio:-1|g\neta:0|g\neta:-2|g\n")
+	})
+}
+
+func TestMetricsExporter(t *testing.T) {
+	tSetup(`{"arguments":{"torrentCount":1,"torrents":[{"id":1,"hashString":"aaaa","name":"a"}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test exporting the metrics of the client", t, func() {
+		var buf bytes.Buffer
+		exporter := NewMetricsExporter(&transmissionClient, &buf, FormatStatsd)
+		exporter.Prefix = "bt"
+		So(exporter.Export(), ShouldBeNil)
+		So(buf.String(), ShouldStartWith, "bt.session.torrents:1|g\n")
+		So(buf.String(), ShouldContainSubstring, "bt.torrent.aaaa.status:0|g\n")
+
+		buf.Reset()
+		exporter.Format = FormatInflux
+		So(exporter.Export(), ShouldBeNil)
+		So(buf.String(), ShouldContainSubstring, "transmission_torrent,hash=aaaa,name=a ")
+	})
+}
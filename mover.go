@@ -0,0 +1,147 @@
+package transmission
+
+import (
+	"context"
+	"time"
+)
+
+// MoveRule moves completed torrents from one download directory to
+// another, like an incomplete directory that feeds a library.
+type MoveRule struct {
+	// FromDir the torrent must be in. Empty matches every directory.
+	FromDir string
+	ToDir   string
+	// Label the torrent must have. Empty matches every torrent.
+	Label string
+	// Tracker is matched against the torrent's announce URLs. Empty
+	// matches every torrent.
+	Tracker string
+}
+
+// Matches reports whether the rule moves the torrent. Only completed
+// torrents that aren't in ToDir yet match.
+func (r MoveRule) Matches(t Torrent) bool {
+	if t.PercentDone < 1 || t.LeftUntilDone > 0 {
+		return false
+	}
+	dir := cleanDir(t.DownloadDir)
+	if dir == cleanDir(r.ToDir) {
+		return false
+	}
+	if r.FromDir != "" && dir != cleanDir(r.FromDir) {
+		return false
+	}
+	if r.Label != "" && !t.HasLabel(r.Label) {
+		return false
+	}
+	return usesTracker(t, r.Tracker)
+}
+
+// MoveEvent reports a torrent moved by a CompletedMover.
+type MoveEvent struct {
+	ID   int
+	Name string
+	From string
+	To   string
+}
+
+// CompletedMover moves completed torrents according to its rules. The
+// first matching rule wins.
+type CompletedMover struct {
+	Rules []MoveRule
+	// Interval between checks in Run. Defaults to one minute.
+	Interval time.Duration
+	// OnMove is called for every torrent moved.
+	OnMove func(MoveEvent)
+	// OnError is called when a check fails in Run.
+	OnError func(error)
+
+	client *TransmissionClient
+	// pending holds the target of moves the daemon hasn't finished yet,
+	// by hash.
+	pending map[string]string
+}
+
+// NewCompletedMover create a mover acting through client
+func NewCompletedMover(client *TransmissionClient, rules ...MoveRule) *CompletedMover {
+	return &CompletedMover{
+		Rules:    rules,
+		Interval: time.Minute,
+		client:   client,
+		pending:  make(map[string]string),
+	}
+}
+
+// Check moves every completed torrent matching a rule and returns the
+// moves it started.
+func (m *CompletedMover) Check() ([]MoveEvent, error) {
+	return m.CheckContext(context.Background())
+}
+
+// CheckContext is like Check but aborts the requests when ctx is done.
+func (m *CompletedMover) CheckContext(ctx context.Context) ([]MoveEvent, error) {
+	torrents, err := m.client.fetchTorrents(ctx, torrentGetFields, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	present := make(map[string]bool, len(torrents))
+	for _, torrent := range torrents {
+		present[torrent.HashString] = true
+	}
+	for hash := range m.pending {
+		if !present[hash] {
+			delete(m.pending, hash)
+		}
+	}
+
+	var moved []MoveEvent
+	for _, torrent := range torrents {
+		if to, ok := m.pending[torrent.HashString]; ok {
+			if cleanDir(torrent.DownloadDir) != cleanDir(to) {
+				continue
+			}
+			delete(m.pending, torrent.HashString)
+		}
+		for _, rule := range m.Rules {
+			if !rule.Matches(torrent) {
+				continue
+			}
+			err = m.client.setLocation(ctx, torrent.ID, rule.ToDir, true)
+			if err != nil {
+				return moved, err
+			}
+			m.pending[torrent.HashString] = rule.ToDir
+			event := MoveEvent{ID: torrent.ID, Name: torrent.Name, From: torrent.DownloadDir, To: rule.ToDir}
+			if m.OnMove != nil {
+				m.OnMove(event)
+			}
+			moved = append(moved, event)
+			break
+		}
+	}
+	return moved, nil
+}
+
+// Run checks every interval until ctx is done.
+func (m *CompletedMover) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := m.CheckContext(ctx)
+		if err != nil && m.OnError != nil {
+			m.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
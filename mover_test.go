@@ -0,0 +1,81 @@
+package transmission
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMoveRuleMatches(t *testing.T) {
+	Convey("Test which torrents a rule moves", t, func() {
+		rule := MoveRule{FromDir: "/incomplete", ToDir: "/library/tv", Label: "tv"}
+		done := Torrent{PercentDone: 1, DownloadDir: "/incomplete/", Labels: []string{"tv"}}
+		So(rule.Matches(done), ShouldBeTrue)
+
+		downloading := done
+		downloading.PercentDone = 0.5
+		So(rule.Matches(downloading), ShouldBeFalse)
+
+		moved := done
+		moved.DownloadDir = "/library/tv"
+		So(rule.Matches(moved), ShouldBeFalse)
+
+		other := done
+		other.Labels = []string{"movies"}
+		So(rule.Matches(other), ShouldBeFalse)
+
+		rule.Tracker = "example.com"
+		So(rule.Matches(done), ShouldBeFalse)
+	})
+}
+
+func TestCompletedMover(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"hashString":"aaaa","name":"a","downloadDir":"/incomplete","percentDone":1,"labels":["tv"]},
+  {"id":2,"hashString":"bbbb","name":"b","downloadDir":"/incomplete","percentDone":0.2,"labels":["tv"]},
+  {"id":3,"hashString":"cccc","name":"c","downloadDir":"/incomplete","percentDone":1,"labels":[]}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test completed torrents are moved once", t, func() {
+		var seen []MoveEvent
+		mover := NewCompletedMover(&transmissionClient,
+			MoveRule{FromDir: "/incomplete", ToDir: "/library/tv", Label: "tv"},
+			MoveRule{FromDir: "/incomplete", ToDir: "/library/other"})
+		mover.OnMove = func(event MoveEvent) { seen = append(seen, event) }
+
+		moved, err := mover.Check()
+		So(err, ShouldBeNil)
+		So(moved, ShouldResemble, []MoveEvent{
+			{ID: 1, Name: "a", From: "/incomplete", To: "/library/tv"},
+			{ID: 3, Name: "c", From: "/incomplete", To: "/library/other"},
+		})
+		So(seen, ShouldResemble, moved)
+
+		// The daemon still reports the old directory, so the moves are
+		// considered in progress.
+		moved, err = mover.Check()
+		So(err, ShouldBeNil)
+		So(moved, ShouldBeEmpty)
+		So(mover.pending, ShouldHaveLength, 2)
+
+		// Moves of torrents the daemon no longer has are forgotten.
+		mover.pending["dddd"] = "/library/tv"
+		_, err = mover.Check()
+		So(err, ShouldBeNil)
+		So(mover.pending, ShouldHaveLength, 2)
+		So(mover.pending, ShouldNotContainKey, "dddd")
+	})
+
+	Convey("Test Run returns while the daemon hangs", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+		mover := NewCompletedMover(&client, MoveRule{FromDir: "/incomplete", ToDir: "/library"})
+		So(tRunFor(mover.Run, 50*time.Millisecond), ShouldEqual, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,67 @@
+package transmission
+
+// PortState describes the peer port as seen by the daemon.
+type PortState struct {
+	Port              int
+	ForwardingEnabled bool
+	Open              bool
+}
+
+// PortStatus returns the configured peer port, whether port forwarding
+// (UPnP/NAT-PMP) is enabled and whether the port is reachable from the
+// outside according to port-test.
+func (ac *TransmissionClient) PortStatus() (PortState, error) {
+	session, err := ac.GetSession()
+	if err != nil {
+		return PortState{}, err
+	}
+
+	var test struct {
+		PortIsOpen bool `json:"port-is-open"`
+	}
+	err = ac.rpc("port-test", nil, &test)
+	if err != nil {
+		return PortState{}, err
+	}
+
+	return PortState{
+		Port:              session.PeerPort,
+		ForwardingEnabled: session.PortForwardingEnabled,
+		Open:              test.PortIsOpen,
+	}, nil
+}
+
+// SetPeerPort set the peer port and whether it is randomized on start
+func (ac *TransmissionClient) SetPeerPort(port int, randomOnStart bool) error {
+	return ac.setSession(map[string]interface{}{
+		"peer-port":                 port,
+		"peer-port-random-on-start": randomOnStart,
+	})
+}
+
+// SetPortForwarding enable or disable UPnP/NAT-PMP port forwarding
+func (ac *TransmissionClient) SetPortForwarding(enabled bool) error {
+	return ac.setSession(map[string]interface{}{
+		"port-forwarding-enabled": enabled,
+	})
+}
+
+// SetDHTEnabled enable or disable the distributed hash table
+func (ac *TransmissionClient) SetDHTEnabled(enabled bool) error {
+	return ac.setSession(map[string]interface{}{"dht-enabled": enabled})
+}
+
+// SetPEXEnabled enable or disable peer exchange
+func (ac *TransmissionClient) SetPEXEnabled(enabled bool) error {
+	return ac.setSession(map[string]interface{}{"pex-enabled": enabled})
+}
+
+// SetLPDEnabled enable or disable local peer discovery
+func (ac *TransmissionClient) SetLPDEnabled(enabled bool) error {
+	return ac.setSession(map[string]interface{}{"lpd-enabled": enabled})
+}
+
+// SetUTPEnabled enable or disable the uTP transport
+func (ac *TransmissionClient) SetUTPEnabled(enabled bool) error {
+	return ac.setSession(map[string]interface{}{"utp-enabled": enabled})
+}
@@ -0,0 +1,73 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPortStatus(t *testing.T) {
+	tSetup(`{"arguments":{"peer-port":51413,"port-forwarding-enabled":true,
+  "port-is-open":true},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test port status", t, func() {
+		status, err := transmissionClient.PortStatus()
+		So(err, ShouldBeNil)
+		So(status.Port, ShouldEqual, 51413)
+		So(status.ForwardingEnabled, ShouldBeTrue)
+		So(status.Open, ShouldBeTrue)
+	})
+}
+
+func TestSetPeerPort(t *testing.T) {
+	tSetup(`{"arguments":{},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test setting the peer port", t, func() {
+		err := transmissionClient.SetPeerPort(51413, false)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestSetPeerPortFailure(t *testing.T) {
+	tSetup(`{"arguments":{},"result":"invalid argument"}`)
+	defer tTeardown()
+
+	Convey("Test a failed session-set is returned as error", t, func() {
+		err := transmissionClient.SetPeerPort(51413, false)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "invalid argument")
+	})
+}
+
+func TestPeerDiscoveryToggles(t *testing.T) {
+	tSetup(`{"arguments":{"dht-enabled":false,"pex-enabled":false,
+  "lpd-enabled":true},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading and setting DHT/PEX/LPD", t, func() {
+		session, err := transmissionClient.GetSession()
+		So(err, ShouldBeNil)
+		So(session.DHTEnabled, ShouldBeFalse)
+		So(session.PEXEnabled, ShouldBeFalse)
+		So(session.LPDEnabled, ShouldBeTrue)
+
+		So(transmissionClient.SetDHTEnabled(false), ShouldBeNil)
+		So(transmissionClient.SetPEXEnabled(false), ShouldBeNil)
+		So(transmissionClient.SetLPDEnabled(false), ShouldBeNil)
+	})
+}
+
+func TestUTPToggle(t *testing.T) {
+	tSetup(`{"arguments":{"utp-enabled":true},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading and setting uTP", t, func() {
+		session, err := transmissionClient.GetSession()
+		So(err, ShouldBeNil)
+		So(session.UTPEnabled, ShouldBeTrue)
+
+		So(transmissionClient.SetUTPEnabled(false), ShouldBeNil)
+	})
+}
@@ -0,0 +1,151 @@
+package transmission
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Optional holds a value that may be left unset. Request structs use it to
+// tell "don't change this" apart from "set this to the zero value".
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+// Some returns an Optional set to value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, set: true}
+}
+
+// Get returns the value and whether it is set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// IsSet reports whether a value is set.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// MarshalJSON encodes the value, or null when it is unset.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON decodes a value and marks it set. null leaves it unset.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*o = Optional[T]{}
+		return nil
+	}
+	err := json.Unmarshal(data, &o.value)
+	if err != nil {
+		return err
+	}
+	o.set = true
+	return nil
+}
+
+func (o Optional[T]) optional() (interface{}, bool) {
+	return o.value, o.set
+}
+
+// optionalValue is implemented by every Optional.
+type optionalValue interface {
+	optional() (interface{}, bool)
+}
+
+// optionalArgs collects the set Optional fields of the request struct v
+// into RPC arguments keyed by their json tag.
+func optionalArgs(v interface{}) map[string]interface{} {
+	args := map[string]interface{}{}
+	value := reflect.ValueOf(v)
+	typ := value.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field, ok := value.Field(i).Interface().(optionalValue)
+		if !ok {
+			continue
+		}
+		if v, set := field.optional(); set {
+			key := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+			args[key] = wireValue(v)
+		}
+	}
+	return args
+}
+
+// TorrentSetRequest holds the torrent-set arguments to change. Unset
+// fields are left untouched by the daemon. Speed limits are applied in
+// whole KB/s, and at least 1 KB/s.
+type TorrentSetRequest struct {
+	BandwidthPriority   Optional[int]      `json:"bandwidthPriority"`
+	DownloadLimit       Optional[Rate]     `json:"downloadLimit"`
+	DownloadLimited     Optional[bool]     `json:"downloadLimited"`
+	Group               Optional[string]   `json:"group"`
+	HonorsSessionLimits Optional[bool]     `json:"honorsSessionLimits"`
+	Labels              Optional[[]string] `json:"labels"`
+	PeerLimit           Optional[int]      `json:"peer-limit"`
+	QueuePosition       Optional[int]      `json:"queuePosition"`
+	SeedIdleLimit       Optional[int]      `json:"seedIdleLimit"`
+	SeedIdleMode        Optional[int]      `json:"seedIdleMode"`
+	SeedRatioLimit      Optional[float64]  `json:"seedRatioLimit"`
+	SeedRatioMode       Optional[int]      `json:"seedRatioMode"`
+	SequentialDownload  Optional[bool]     `json:"sequential_download"`
+	UploadLimit         Optional[Rate]     `json:"uploadLimit"`
+	UploadLimited       Optional[bool]     `json:"uploadLimited"`
+}
+
+// SetTorrent change the settings of a torrent that are set in req. Labels,
+// Group and SequentialDownload need RPC version 16, 17 and 18.
+func (ac *TransmissionClient) SetTorrent(id int, req TorrentSetRequest) error {
+	args := optionalArgs(req)
+	if len(args) == 0 {
+		return nil
+	}
+	for _, need := range []struct {
+		key     string
+		version int
+	}{{"labels", 16}, {"group", 17}, {"sequential_download", 18}} {
+		if _, ok := args[need.key]; !ok {
+			continue
+		}
+		err := ac.requireRPCVersion(need.key, need.version)
+		if err != nil {
+			return err
+		}
+	}
+	return ac.setTorrent(id, args)
+}
+
+// SessionSetRequest holds the most common session-set arguments. Unset
+// fields are left untouched by the daemon; see SessionPatch for the rest.
+type SessionSetRequest struct {
+	AltSpeedDown          Optional[Rate]    `json:"alt-speed-down"`
+	AltSpeedEnabled       Optional[bool]    `json:"alt-speed-enabled"`
+	AltSpeedUp            Optional[Rate]    `json:"alt-speed-up"`
+	DownloadDir           Optional[string]  `json:"download-dir"`
+	DownloadQueueEnabled  Optional[bool]    `json:"download-queue-enabled"`
+	DownloadQueueSize     Optional[int]     `json:"download-queue-size"`
+	IncompleteDir         Optional[string]  `json:"incomplete-dir"`
+	IncompleteDirEnabled  Optional[bool]    `json:"incomplete-dir-enabled"`
+	PeerPort              Optional[int]     `json:"peer-port"`
+	SeedQueueEnabled      Optional[bool]    `json:"seed-queue-enabled"`
+	SeedQueueSize         Optional[int]     `json:"seed-queue-size"`
+	SeedRatioLimit        Optional[float64] `json:"seedRatioLimit"`
+	SeedRatioLimited      Optional[bool]    `json:"seedRatioLimited"`
+	SpeedLimitDown        Optional[Rate]    `json:"speed-limit-down"`
+	SpeedLimitDownEnabled Optional[bool]    `json:"speed-limit-down-enabled"`
+	SpeedLimitUp          Optional[Rate]    `json:"speed-limit-up"`
+	SpeedLimitUpEnabled   Optional[bool]    `json:"speed-limit-up-enabled"`
+	StartAddedTorrents    Optional[bool]    `json:"start-added-torrents"`
+}
+
+// Patch returns the fields set in req as a patch for SetSession.
+func (req SessionSetRequest) Patch() SessionPatch {
+	return SessionPatch(optionalArgs(req))
+}
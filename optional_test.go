@@ -0,0 +1,72 @@
+package transmission
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOptional(t *testing.T) {
+	Convey("Test set, zero and unset values", t, func() {
+		var unset Optional[int]
+		_, ok := unset.Get()
+		So(ok, ShouldBeFalse)
+
+		zero := Some(0)
+		value, ok := zero.Get()
+		So(ok, ShouldBeTrue)
+		So(value, ShouldEqual, 0)
+	})
+
+	Convey("Test JSON round trip", t, func() {
+		data, err := json.Marshal(struct {
+			A Optional[bool] `json:"a"`
+			B Optional[bool] `json:"b"`
+		}{A: Some(false)})
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `{"a":false,"b":null}`)
+
+		var out struct {
+			A Optional[bool] `json:"a"`
+			B Optional[bool] `json:"b"`
+		}
+		So(json.Unmarshal(data, &out), ShouldBeNil)
+		So(out.A.IsSet(), ShouldBeTrue)
+		So(out.B.IsSet(), ShouldBeFalse)
+	})
+}
+
+func TestRequestArgs(t *testing.T) {
+	Convey("Test only set fields are sent", t, func() {
+		args := optionalArgs(TorrentSetRequest{
+			DownloadLimit:   Some(512 * KBps),
+			DownloadLimited: Some(false),
+			Labels:          Some([]string{}),
+		})
+		So(args, ShouldResemble, map[string]interface{}{
+			"downloadLimit":   int64(512),
+			"downloadLimited": false,
+			"labels":          []string{},
+		})
+
+		patch := SessionSetRequest{SpeedLimitUp: Some(2 * MBps), PeerPort: Some(51413)}.Patch()
+		So(patch, ShouldResemble, SessionPatch{"speed-limit-up": int64(2000), "peer-port": 51413})
+	})
+}
+
+func TestSetTorrent(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":15},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test setting a torrent through a request struct", t, func() {
+		err := transmissionClient.SetTorrent(1, TorrentSetRequest{SeedRatioLimit: Some(2.0)})
+		So(err, ShouldBeNil)
+
+		err = transmissionClient.SetTorrent(1, TorrentSetRequest{PeerLimit: Some(-1)})
+		So(err, ShouldNotBeNil)
+
+		err = transmissionClient.SetTorrent(1, TorrentSetRequest{Labels: Some([]string{"tv"})})
+		So(err, ShouldHaveSameTypeAs, ErrUnsupportedRPCVersion{})
+	})
+}
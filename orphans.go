@@ -0,0 +1,102 @@
+package transmission
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// Orphan is a file or directory in a download directory that no torrent
+// references.
+type Orphan struct {
+	// Path is relative to the download directory, with forward slashes.
+	Path  string
+	IsDir bool
+	// Size is the total size of the file or of everything in the directory.
+	Size ByteSize
+}
+
+// FindOrphans compares the entries in downloadDir with the files of the
+// torrents stored there and returns the ones no torrent references. fsys
+// gives access to the directory; when it is nil, downloadDir is read from
+// the local filesystem. A directory without referenced files is reported
+// as a whole. Partial files with a .part suffix count as referenced.
+func (ac *TransmissionClient) FindOrphans(downloadDir string, fsys fs.FS) ([]Orphan, error) {
+	if fsys == nil {
+		fsys = os.DirFS(downloadDir)
+	}
+	torrents, err := ac.GetTorrents()
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]bool{}
+	dirs := map[string]bool{}
+	for _, torrent := range torrents {
+		if cleanDir(torrent.DownloadDir) != cleanDir(downloadDir) {
+			continue
+		}
+		names := []string{torrent.Name}
+		for _, file := range torrent.Files {
+			names = append(names, file.Name)
+		}
+		for _, name := range names {
+			name = path.Clean(strings.TrimPrefix(name, "/"))
+			files[name] = true
+			files[name+".part"] = true
+			for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+				dirs[dir] = true
+			}
+		}
+	}
+
+	var orphans []Orphan
+	err = fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case name == "." || dirs[name]:
+			return nil
+		case files[name] && entry.IsDir():
+			// Named by a torrent whose file list is unknown, e.g. a
+			// magnet link still fetching metadata.
+			return fs.SkipDir
+		case files[name]:
+			return nil
+		}
+		if entry.IsDir() {
+			size, err := dirSize(fsys, name)
+			if err != nil {
+				return err
+			}
+			orphans = append(orphans, Orphan{Path: name, IsDir: true, Size: size})
+			return fs.SkipDir
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		orphans = append(orphans, Orphan{Path: name, Size: ByteSize(info.Size())})
+		return nil
+	})
+	return orphans, err
+}
+
+// dirSize returns the total size of the regular files under dir.
+func dirSize(fsys fs.FS, dir string) (ByteSize, error) {
+	var size ByteSize
+	err := fs.WalkDir(fsys, dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += ByteSize(info.Size())
+		return nil
+	})
+	return size, err
+}
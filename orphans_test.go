@@ -0,0 +1,41 @@
+package transmission
+
+import (
+	"testing"
+	"testing/fstest"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFindOrphans(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"name":"album","downloadDir":"/data/","files":[
+    {"name":"album/01.flac","length":10},{"name":"album/disc2/01.flac","length":10}]},
+  {"id":2,"name":"debian.iso","downloadDir":"/data","files":[
+    {"name":"debian.iso","length":10}]},
+  {"id":3,"name":"fetching","downloadDir":"/data","files":[]},
+  {"id":4,"name":"elsewhere","downloadDir":"/other","files":[
+    {"name":"old.iso","length":10}]}]},"result":"success"}`)
+	defer tTeardown()
+
+	fsys := fstest.MapFS{
+		"album/01.flac":       {Data: make([]byte, 10)},
+		"album/disc2/01.flac": {Data: make([]byte, 10)},
+		"album/cover.jpg":     {Data: make([]byte, 3)},
+		"debian.iso.part":     {Data: make([]byte, 5)},
+		"fetching/x":          {Data: make([]byte, 1)},
+		"old.iso":             {Data: make([]byte, 7)},
+		"leftover/a/b.mkv":    {Data: make([]byte, 4)},
+		"leftover/c.nfo":      {Data: make([]byte, 2)},
+	}
+
+	Convey("Test unreferenced entries are reported", t, func() {
+		orphans, err := transmissionClient.FindOrphans("/data", fsys)
+		So(err, ShouldBeNil)
+		So(orphans, ShouldResemble, []Orphan{
+			{Path: "album/cover.jpg", Size: 3},
+			{Path: "leftover", IsDir: true, Size: 6},
+			{Path: "old.iso", Size: 7},
+		})
+	})
+}
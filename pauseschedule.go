@@ -0,0 +1,166 @@
+package transmission
+
+import (
+	"context"
+	"time"
+)
+
+// PauseRule pauses the torrents it selects during a time window, such as
+// the seeds labelled iso during work hours:
+//
+//	window, _ := ParseScheduleRule("Mon-Fri 09:00-17:00", 0, 0)
+//	rule := PauseRule{Window: window, Label: "iso"}
+type PauseRule struct {
+	// Window is when the torrents are paused. Its Down and Up are unused.
+	Window ScheduleRule
+	// Label selects the torrents with the label.
+	Label string
+	// Match selects the torrents it returns true for. With Label set too,
+	// a torrent must be selected by both.
+	Match func(Torrent) bool
+}
+
+// Selects reports whether the rule applies to the torrent.
+func (r PauseRule) Selects(t Torrent) bool {
+	if r.Label == "" && r.Match == nil {
+		return false
+	}
+	if r.Label != "" && !t.HasLabel(r.Label) {
+		return false
+	}
+	return r.Match == nil || r.Match(t)
+}
+
+// PauseEvent reports torrents paused or resumed by a PauseScheduler.
+type PauseEvent struct {
+	// Paused is true when the torrents were paused because a window
+	// started, and false when they were resumed after it ended.
+	Paused bool
+	Hashes []string
+}
+
+// PauseScheduler pauses the torrents selected by an active rule, and
+// resumes them once no rule applies anymore. Unlike the daemon's alt-speed
+// schedule it acts on single torrents. Only torrents it paused itself are
+// resumed, so torrents paused by hand stay paused.
+type PauseScheduler struct {
+	Rules []PauseRule
+	// Interval between checks in Run. Defaults to one minute.
+	Interval time.Duration
+	// OnEvent is called whenever torrents are paused or resumed.
+	OnEvent func(PauseEvent)
+	// OnError is called when a check fails in Run.
+	OnError func(error)
+
+	client *TransmissionClient
+	now    func() time.Time
+	paused map[string]bool
+}
+
+// NewPauseScheduler create a scheduler applying rules through client
+func NewPauseScheduler(client *TransmissionClient, rules ...PauseRule) *PauseScheduler {
+	return &PauseScheduler{
+		Rules:    rules,
+		Interval: time.Minute,
+		client:   client,
+		now:      time.Now,
+		paused:   make(map[string]bool),
+	}
+}
+
+// ShouldPause reports whether an active rule at now selects the torrent.
+func (s *PauseScheduler) ShouldPause(t Torrent, now time.Time) bool {
+	for _, rule := range s.Rules {
+		if rule.Window.Active(now) && rule.Selects(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check runs a single check of every torrent and returns the events it
+// caused. A torrent resumed by hand during a window is paused again.
+func (s *PauseScheduler) Check() ([]PauseEvent, error) {
+	return s.CheckContext(context.Background())
+}
+
+// CheckContext is like Check but aborts the requests when ctx is done.
+func (s *PauseScheduler) CheckContext(ctx context.Context) ([]PauseEvent, error) {
+	torrents, err := s.client.fetchTorrents(ctx, torrentGetFields, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	var pause, resume []string
+	present := make(map[string]bool, len(torrents))
+	for _, torrent := range torrents {
+		present[torrent.HashString] = true
+		switch {
+		case s.ShouldPause(torrent, now):
+			if torrent.Status != StatusPaused {
+				pause = append(pause, torrent.HashString)
+			}
+		case s.paused[torrent.HashString]:
+			resume = append(resume, torrent.HashString)
+		}
+	}
+	for hash := range s.paused {
+		if !present[hash] {
+			delete(s.paused, hash)
+		}
+	}
+
+	var events []PauseEvent
+	if len(pause) > 0 {
+		_, err = invoke[struct{}](ctx, s.client, "torrent-stop", idsArgs{Ids: Hashes(pause...)})
+		if err != nil {
+			return events, err
+		}
+		for _, hash := range pause {
+			s.paused[hash] = true
+		}
+		events = append(events, s.emit(PauseEvent{Paused: true, Hashes: pause}))
+	}
+	if len(resume) > 0 {
+		_, err = invoke[struct{}](ctx, s.client, "torrent-start", idsArgs{Ids: Hashes(resume...)})
+		if err != nil {
+			return events, err
+		}
+		for _, hash := range resume {
+			delete(s.paused, hash)
+		}
+		events = append(events, s.emit(PauseEvent{Hashes: resume}))
+	}
+	return events, nil
+}
+
+func (s *PauseScheduler) emit(event PauseEvent) PauseEvent {
+	if s.OnEvent != nil {
+		s.OnEvent(event)
+	}
+	return event
+}
+
+// Run checks every interval until ctx is done.
+func (s *PauseScheduler) Run(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := s.CheckContext(ctx)
+		if err != nil && s.OnError != nil {
+			s.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPauseScheduler(t *testing.T) {
+	status := map[string]int{"aaaa": StatusSeed, "bbbb": StatusSeed, "cccc": StatusDownload}
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var request struct {
+			Method    string `json:"method"`
+			Arguments struct {
+				Ids []string `json:"ids"`
+			} `json:"arguments"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		switch request.Method {
+		case "torrent-stop", "torrent-start":
+			calls = append(calls, request.Method+" "+strings.Join(request.Arguments.Ids, ","))
+			for _, hash := range request.Arguments.Ids {
+				if request.Method == "torrent-stop" {
+					status[hash] = StatusPaused
+				} else {
+					status[hash] = StatusSeed
+				}
+			}
+			w.Write([]byte(`{"arguments":{},"result":"success"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": "success",
+			"arguments": map[string]interface{}{"torrents": []map[string]interface{}{
+				{"id": 1, "hashString": "aaaa", "status": status["aaaa"], "labels": []string{"iso"}},
+				{"id": 2, "hashString": "bbbb", "status": status["bbbb"], "labels": []string{"tv"}},
+				{"id": 3, "hashString": "cccc", "status": status["cccc"], "labels": []string{"iso"}},
+			}},
+		})
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	window, err := ParseScheduleRule("Mon-Fri 09:00-17:00", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seeding := func(t Torrent) bool { return t.Status == StatusSeed || t.Status == StatusPaused }
+
+	Convey("Test torrents are paused during the window and resumed after", t, func() {
+		calls = nil
+		var events []PauseEvent
+		scheduler := NewPauseScheduler(&client, PauseRule{Window: window, Label: "iso", Match: seeding})
+		scheduler.OnEvent = func(event PauseEvent) {
+			events = append(events, event)
+		}
+
+		// Monday 2024-01-01 at 08:00, before the window.
+		now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+		scheduler.now = func() time.Time { return now }
+		_, err := scheduler.Check()
+		So(err, ShouldBeNil)
+		So(calls, ShouldBeEmpty)
+
+		now = now.Add(2 * time.Hour)
+		_, err = scheduler.Check()
+		So(err, ShouldBeNil)
+		So(calls, ShouldResemble, []string{"torrent-stop aaaa"})
+		So(events, ShouldResemble, []PauseEvent{{Paused: true, Hashes: []string{"aaaa"}}})
+
+		// Already paused, nothing to do.
+		_, err = scheduler.Check()
+		So(err, ShouldBeNil)
+		So(calls, ShouldHaveLength, 1)
+
+		now = now.Add(8 * time.Hour)
+		_, err = scheduler.Check()
+		So(err, ShouldBeNil)
+		So(calls, ShouldResemble, []string{"torrent-stop aaaa", "torrent-start aaaa"})
+		So(events[1], ShouldResemble, PauseEvent{Hashes: []string{"aaaa"}})
+	})
+
+	Convey("Test torrents paused by hand are not resumed", t, func() {
+		calls = nil
+		status["bbbb"] = StatusPaused
+		scheduler := NewPauseScheduler(&client, PauseRule{Window: window, Label: "tv"})
+		now := time.Date(2024, 1, 6, 10, 0, 0, 0, time.UTC)
+		scheduler.now = func() time.Time { return now }
+		_, err := scheduler.Check()
+		So(err, ShouldBeNil)
+		So(calls, ShouldBeEmpty)
+	})
+
+	Convey("Test a rule without a label or predicate selects nothing", t, func() {
+		So(PauseRule{Window: window}.Selects(Torrent{}), ShouldBeFalse)
+		So(PauseRule{Label: "iso"}.Selects(Torrent{Labels: []string{"iso"}}), ShouldBeTrue)
+	})
+
+	Convey("Test Run returns while the daemon hangs", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+		scheduler := NewPauseScheduler(&client, PauseRule{Label: "iso"})
+		So(tRunFor(scheduler.Run, 50*time.Millisecond), ShouldEqual, context.DeadlineExceeded)
+	})
+}
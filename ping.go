@@ -0,0 +1,98 @@
+package transmission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// PingFailure classifies why a Ping failed.
+type PingFailure int
+
+const (
+	// PingNetwork means the daemon couldn't be reached at all.
+	PingNetwork PingFailure = iota
+	// PingAuth means the credentials were rejected.
+	PingAuth
+	// PingCSRF means the daemon kept rejecting the session id.
+	PingCSRF
+	// PingWrongPath means something answered, but not a Transmission RPC
+	// endpoint.
+	PingWrongPath
+	// PingBadResponse means the daemon answered with an error.
+	PingBadResponse
+)
+
+func (f PingFailure) String() string {
+	switch f {
+	case PingNetwork:
+		return "network"
+	case PingAuth:
+		return "auth"
+	case PingCSRF:
+		return "csrf"
+	case PingWrongPath:
+		return "wrong path"
+	case PingBadResponse:
+		return "bad response"
+	}
+	return "unknown"
+}
+
+// PingError is returned by Ping.
+type PingError struct {
+	Failure    PingFailure
+	StatusCode int
+	Err        error
+}
+
+func (e *PingError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("ping failed (%s): %v", e.Failure, e.Err)
+	}
+	return fmt.Sprintf("ping failed (%s): HTTP %d", e.Failure, e.StatusCode)
+}
+
+func (e *PingError) Unwrap() error {
+	return e.Err
+}
+
+// Ping performs a minimal authenticated request against the daemon. On
+// failure it returns a *PingError telling whether the network, the
+// credentials, the session id handshake or the RPC path is at fault.
+func (ac *TransmissionClient) Ping(ctx context.Context) error {
+	body, err := ac.apiclient.jsonCodec().Marshal(rpcRequest{
+		Method:    "session-get",
+		Arguments: map[string]interface{}{"fields": []string{"version"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	status, output, err := ac.apiclient.post(ctx, "session-get", body)
+	if err != nil {
+		return &PingError{Failure: PingNetwork, Err: err}
+	}
+
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return &PingError{Failure: PingAuth, StatusCode: status}
+	case status == http.StatusConflict:
+		return &PingError{Failure: PingCSRF, StatusCode: status}
+	case status != http.StatusOK:
+		return &PingError{Failure: PingWrongPath, StatusCode: status}
+	}
+
+	var response struct {
+		Result *string `json:"result"`
+	}
+	err = ac.apiclient.jsonCodec().Unmarshal(output, &response)
+	if err != nil || response.Result == nil {
+		return &PingError{Failure: PingWrongPath, StatusCode: status, Err: err}
+	}
+	if *response.Result != "success" {
+		return &PingError{Failure: PingBadResponse, StatusCode: status,
+			Err: fmt.Errorf("%s", *response.Result)}
+	}
+	return nil
+}
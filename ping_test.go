@@ -0,0 +1,59 @@
+package transmission
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPing(t *testing.T) {
+	tSetup(`{"arguments":{"version":"4.0.5"},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test ping succeeds against a daemon", t, func() {
+		So(transmissionClient.Ping(context.Background()), ShouldBeNil)
+	})
+
+	Convey("Test ping reports bad credentials", t, func() {
+		client := New(tServer.URL, "test", "wrong")
+		err := client.Ping(context.Background())
+		So(err, ShouldNotBeNil)
+		So(err.(*PingError).Failure, ShouldEqual, PingAuth)
+	})
+}
+
+func TestPingFailures(t *testing.T) {
+	Convey("Test ping reports a wrong path", t, func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		err := client.Ping(context.Background())
+		So(err.(*PingError).Failure, ShouldEqual, PingWrongPath)
+		So(err.(*PingError).StatusCode, ShouldEqual, http.StatusNotFound)
+	})
+
+	Convey("Test ping reports a CSRF loop", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Transmission-Session-Id", "new")
+			w.WriteHeader(http.StatusConflict)
+		}))
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		err := client.Ping(context.Background())
+		So(err.(*PingError).Failure, ShouldEqual, PingCSRF)
+	})
+
+	Convey("Test ping reports an unreachable daemon", t, func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		client := New(server.URL, "", "")
+		err := client.Ping(context.Background())
+		So(err.(*PingError).Failure, ShouldEqual, PingNetwork)
+	})
+}
@@ -0,0 +1,36 @@
+package transmission
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// bufferPool holds the buffers responses are read into, the allocation
+// made on every poll that grows with the number of torrents. Commands used
+// to be pooled too, but requests and responses are now small per-method
+// types rather than a Command carrying every argument, and pooling their
+// envelopes makes no difference to BenchmarkGetTorrents.
+var bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// maxPooledBuffer keeps the buffer of an unusually large response from
+// being held on to.
+const maxPooledBuffer = 16 << 20
+
+// readBody reads r through a pooled buffer. The buffer grows to the size of
+// the typical response once, after which reading only costs the copy that
+// is returned.
+func readBody(r io.Reader) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			buf.Reset()
+			bufferPool.Put(buf)
+		}
+	}()
+	_, err := buf.ReadFrom(r)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
@@ -0,0 +1,90 @@
+package transmission
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPools(t *testing.T) {
+	Convey("Test bodies read through the pool don't share memory", t, func() {
+		first, err := readBody(strings.NewReader("first"))
+		So(err, ShouldBeNil)
+		second, err := readBody(strings.NewReader("second"))
+		So(err, ShouldBeNil)
+		So(string(first), ShouldEqual, "first")
+		So(string(second), ShouldEqual, "second")
+	})
+
+	Convey("Test torrent lists survive the next poll", t, func() {
+		tSetup(`{"arguments":{"torrents":[{"id":1,"name":"a"}]},"result":"success"}`)
+		defer tTeardown()
+
+		first, err := transmissionClient.GetTorrents()
+		So(err, ShouldBeNil)
+		_, err = transmissionClient.GetTorrents()
+		So(err, ShouldBeNil)
+		So(first, ShouldResemble, Torrents{{ID: 1, Name: "a"}})
+	})
+}
+
+// tTorrentList returns a torrent-get response with n torrents.
+func tTorrentList(n int) string {
+	torrents := make([]string, n)
+	for i := range torrents {
+		torrents[i] = fmt.Sprintf(`{"id":%d,"name":"torrent %d","hashString":"%040d","status":6,"percentDone":1,"rateUpload":1000,"labels":["tv"]}`, i, i, i)
+	}
+	return `{"arguments":{"torrents":[` + strings.Join(torrents, ",") + `]},"result":"success"}`
+}
+
+// BenchmarkGetTorrents measures a poll of 500 torrents, as made every
+// second by dashboards and watchers.
+func BenchmarkGetTorrents(b *testing.B) {
+	output := []byte(tTorrentList(500))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(output)
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := client.GetTorrents()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadBody and BenchmarkIOReadAll read the response of a 500
+// torrent poll through the pool and through io.ReadAll, which readBody
+// replaced.
+func BenchmarkReadBody(b *testing.B) {
+	body := tTorrentList(500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := readBody(strings.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIOReadAll(b *testing.B) {
+	body := tTorrentList(500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := io.ReadAll(strings.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
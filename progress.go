@@ -0,0 +1,93 @@
+package transmission
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// displayProgress returns the fraction to show for the torrent and what it
+// is the progress of: verifying and fetching metadata report their own
+// progress rather than percentDone.
+func (t Torrent) displayProgress() (float64, string) {
+	switch {
+	case t.Status == StatusCheck:
+		return clampFraction(t.RecheckProgress), "verifying"
+	case t.TotalSize == 0 && t.MetadataPercentComplete < 1 && t.MagnetLink != "":
+		return clampFraction(t.MetadataPercentComplete), "metadata"
+	}
+	return clampFraction(t.PercentDone), ""
+}
+
+func clampFraction(f float64) float64 {
+	if math.IsNaN(f) || f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+// ProgressBar renders the progress of the torrent as a bar of exactly
+// width characters, brackets included, e.g. "[#####.....]". The bar is
+// only full once the torrent is done, so an almost finished torrent never
+// looks complete. Returns "" if width is too small for a bar.
+func (t Torrent) ProgressBar(width int) string {
+	inner := width - 2
+	if inner < 1 {
+		return ""
+	}
+	progress, _ := t.displayProgress()
+	filled := int(progress * float64(inner))
+	if filled == inner && progress < 1 {
+		filled--
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", inner-filled) + "]"
+}
+
+// StatusLine renders the torrent on a single line with its name, percent
+// done, rates and ETA, e.g.
+//
+//	ubuntu.iso  45.2%  ↓ 1.20 MB/s  ↑ 20.0 kB/s  ETA 3h02m
+func (t Torrent) StatusLine() string {
+	progress, what := t.displayProgress()
+	parts := []string{t.Name, formatPercent(progress)}
+	if what != "" {
+		parts[1] += " " + what
+	}
+	parts = append(parts, "↓ "+t.RateDownload.String(), "↑ "+t.RateUpload.String())
+	if eta := formatETA(t.Eta); eta != "" {
+		parts = append(parts, "ETA "+eta)
+	}
+	if t.ErrorString != "" {
+		parts = append(parts, "error: "+t.ErrorString)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// formatPercent formats a fraction with one decimal, rounding down so that
+// only a finished torrent shows 100.0%.
+func formatPercent(f float64) string {
+	return fmt.Sprintf("%.1f%%", math.Floor(f*1000)/10)
+}
+
+// formatETA formats the eta field of a torrent, which is -1 when it isn't
+// downloading and -2 when the ETA is unknown.
+func formatETA(seconds int) string {
+	switch {
+	case seconds == -1:
+		return ""
+	case seconds < 0:
+		return "unknown"
+	}
+	eta := time.Duration(seconds) * time.Second
+	switch {
+	case eta >= 24*time.Hour:
+		return fmt.Sprintf("%dd%02dh", eta/(24*time.Hour), eta%(24*time.Hour)/time.Hour)
+	case eta >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", eta/time.Hour, eta%time.Hour/time.Minute)
+	}
+	return fmt.Sprintf("%dm%02ds", eta/time.Minute, eta%time.Minute/time.Second)
+}
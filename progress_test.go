@@ -0,0 +1,54 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProgressBar(t *testing.T) {
+	Convey("Test the bar has the requested width", t, func() {
+		So(Torrent{PercentDone: 0.5}.ProgressBar(12), ShouldEqual, "[#####.....]")
+		So(Torrent{PercentDone: 0}.ProgressBar(4), ShouldEqual, "[..]")
+		So(Torrent{PercentDone: 1}.ProgressBar(4), ShouldEqual, "[##]")
+		So(Torrent{PercentDone: 1}.ProgressBar(2), ShouldEqual, "")
+	})
+
+	Convey("Test an unfinished torrent never shows a full bar", t, func() {
+		So(Torrent{PercentDone: 0.999}.ProgressBar(12), ShouldEqual, "[#########.]")
+		So(Torrent{PercentDone: 1.5}.ProgressBar(4), ShouldEqual, "[##]")
+		So(Torrent{PercentDone: -1}.ProgressBar(4), ShouldEqual, "[..]")
+	})
+
+	Convey("Test verifying torrents show the recheck progress", t, func() {
+		So(Torrent{Status: StatusCheck, PercentDone: 1, RecheckProgress: 0.2}.ProgressBar(12), ShouldEqual, "[##........]")
+	})
+}
+
+func TestStatusLine(t *testing.T) {
+	Convey("Test a downloading torrent", t, func() {
+		torrent := Torrent{Name: "ubuntu.iso", PercentDone: 0.4529, RateDownload: 1200 * KBps, RateUpload: 20 * KBps, Eta: 3*3600 + 120}
+		So(torrent.StatusLine(), ShouldEqual, "ubuntu.iso  45.2%  ↓ 1.20 MB/s  ↑ 20.0 kB/s  ETA 3h02m")
+	})
+
+	Convey("Test percentages round down", t, func() {
+		So(Torrent{Name: "a", PercentDone: 0.9999, Eta: -1}.StatusLine(), ShouldStartWith, "a  99.9%  ")
+	})
+
+	Convey("Test an idle torrent has no ETA", t, func() {
+		So(Torrent{Name: "a", PercentDone: 1, Eta: -1}.StatusLine(), ShouldEqual, "a  100.0%  ↓ 0 B/s  ↑ 0 B/s")
+		So(Torrent{Name: "a", Eta: -2}.StatusLine(), ShouldEndWith, "ETA unknown")
+	})
+
+	Convey("Test magnets waiting for metadata and errors", t, func() {
+		torrent := Torrent{Name: "m", MagnetLink: "magnet:?xt=urn:btih:aaaa", MetadataPercentComplete: 0.25, Eta: -1, ErrorString: "tracker down"}
+		So(torrent.StatusLine(), ShouldEqual, "m  25.0% metadata  ↓ 0 B/s  ↑ 0 B/s  error: tracker down")
+	})
+}
+
+func TestFormatETA(t *testing.T) {
+	Convey("Test formatting the ETA", t, func() {
+		So(formatETA(90), ShouldEqual, "1m30s")
+		So(formatETA(2*86400+3600), ShouldEqual, "2d01h")
+	})
+}
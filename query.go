@@ -0,0 +1,315 @@
+package transmission
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query is a compiled torrent filter, see ParseQuery.
+type Query struct {
+	source string
+	terms  []queryTerm
+	now    func() time.Time
+}
+
+type queryTerm struct {
+	negate bool
+	match  func(t Torrent, now time.Time) bool
+}
+
+// QueryError is returned by ParseQuery for a term it can't compile.
+type QueryError struct {
+	Term   string
+	Reason string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query term %q: %s", e.Term, e.Reason)
+}
+
+// queryStatuses are the names of the Status* constants in queries.
+var queryStatuses = map[string]int{
+	"paused":        StatusPaused,
+	"check-wait":    StatusWait,
+	"checking":      StatusCheck,
+	"download-wait": StatusDownloadWait,
+	"downloading":   StatusDownload,
+	"seed-wait":     StatisSeedWait,
+	"seeding":       StatusSeed,
+}
+
+// ParseQuery compiles a query such as
+//
+//	status:seeding ratio>1.5 tracker:example label:tv added<30d
+//
+// A torrent matches when it matches every term. A bare word matches the
+// name, and the other terms are a key, an operator and a value:
+//
+//	name:x      the name contains x, ignoring case
+//	status:x    the status is paused, check-wait, checking, download-wait,
+//	            downloading, seed-wait or seeding
+//	label:x     the torrent has label x
+//	tracker:x   the announce URL of a tracker contains x
+//	dir:x       the download directory is x or below it
+//	ratio       the upload ratio
+//	progress    the percent done, 0 to 100
+//	size        the size of the wanted files, with the units K, M, G and T,
+//	            optionally followed by B or iB, as powers of 1024
+//	added       how long ago the torrent was added
+//	active      how long ago the torrent last sent or received data
+//	done        how long ago the torrent finished downloading
+//
+// The numeric keys take the operators :, =, <, <=, > and >=, the others
+// : and =. Ages are numbers with the unit s, m, h, d or w, so added<30d
+// matches the torrents added in the last 30 days. A term is negated with a
+// leading -, and values with spaces are double-quoted: label:"tv shows".
+func ParseQuery(query string) (*Query, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	q := &Query{source: query, now: time.Now}
+	for _, token := range tokens {
+		term, err := parseQueryTerm(token)
+		if err != nil {
+			return nil, err
+		}
+		q.terms = append(q.terms, term)
+	}
+	return q, nil
+}
+
+// Match reports whether the torrent matches every term of the query.
+func (q *Query) Match(t Torrent) bool {
+	now := q.now()
+	for _, term := range q.terms {
+		if term.match(t, now) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the torrents matching the query.
+func (q *Query) Filter(torrents Torrents) Torrents {
+	var matched Torrents
+	for _, torrent := range torrents {
+		if q.Match(torrent) {
+			matched = append(matched, torrent)
+		}
+	}
+	return matched
+}
+
+func (q *Query) String() string {
+	return q.source
+}
+
+// tokenizeQuery splits a query at spaces outside double quotes, and drops
+// the quotes.
+func tokenizeQuery(query string) ([]string, error) {
+	var (
+		tokens  []string
+		current strings.Builder
+		quoted  bool
+		started bool
+	)
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if started {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if quoted {
+		return nil, &QueryError{query, "unterminated quote"}
+	}
+	if started {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+func parseQueryTerm(token string) (queryTerm, error) {
+	term := queryTerm{}
+	if len(token) > 1 && token[0] == '-' {
+		term.negate = true
+		token = token[1:]
+	}
+	invalid := func(reason string) (queryTerm, error) {
+		return queryTerm{}, &QueryError{token, reason}
+	}
+
+	key, op, value := "name", ":", token
+	if i := strings.IndexAny(token, ":=<>"); i >= 0 {
+		key, op, value = strings.ToLower(token[:i]), token[i:i+1], token[i+1:]
+	}
+	if strings.HasPrefix(value, "=") && (op == "<" || op == ">") {
+		op, value = op+"=", value[1:]
+	}
+	if value == "" {
+		return invalid("missing value")
+	}
+	equality := op == ":" || op == "="
+
+	switch key {
+	case "name", "status", "label", "tracker", "dir":
+		if !equality {
+			return invalid(key + " only takes : and =")
+		}
+	}
+
+	switch key {
+	case "name":
+		value = strings.ToLower(value)
+		term.match = func(t Torrent, _ time.Time) bool {
+			return strings.Contains(strings.ToLower(t.Name), value)
+		}
+	case "status":
+		status, ok := queryStatuses[strings.ToLower(value)]
+		if !ok {
+			return invalid("unknown status")
+		}
+		term.match = func(t Torrent, _ time.Time) bool {
+			return t.Status == status
+		}
+	case "label":
+		term.match = func(t Torrent, _ time.Time) bool {
+			return t.HasLabel(value)
+		}
+	case "tracker":
+		value = strings.ToLower(value)
+		term.match = func(t Torrent, _ time.Time) bool {
+			for _, stat := range t.TrackerStats {
+				if strings.Contains(strings.ToLower(stat.Announce), value) ||
+					strings.Contains(strings.ToLower(stat.Host), value) {
+					return true
+				}
+			}
+			return false
+		}
+	case "dir":
+		dir := cleanDir(value)
+		term.match = func(t Torrent, _ time.Time) bool {
+			torrentDir := cleanDir(t.DownloadDir)
+			return torrentDir == dir || strings.HasPrefix(torrentDir, strings.TrimSuffix(dir, "/")+"/")
+		}
+	case "ratio", "progress":
+		limit, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return invalid("value must be a number")
+		}
+		term.match = func(t Torrent, _ time.Time) bool {
+			if key == "ratio" {
+				return compareQuery(t.UploadRatio, op, limit)
+			}
+			return compareQuery(t.PercentDone*100, op, limit)
+		}
+	case "size":
+		limit, err := parseQuerySize(value)
+		if err != nil {
+			return invalid("value must be a size such as 700M or 1.5GiB")
+		}
+		term.match = func(t Torrent, _ time.Time) bool {
+			return compareQuery(float64(t.SizeWhenDone), op, float64(limit))
+		}
+	case "added", "active", "done":
+		limit, err := parseQueryAge(value)
+		if err != nil {
+			return invalid("value must be an age such as 12h or 30d")
+		}
+		term.match = func(t Torrent, now time.Time) bool {
+			var at time.Time
+			switch key {
+			case "added":
+				at = time.Unix(int64(t.AddedDate), 0)
+			case "active":
+				at = t.ActivityDate
+			case "done":
+				at = t.DoneDate
+			}
+			if at.IsZero() || at.Unix() <= 0 {
+				return false
+			}
+			return compareQuery(float64(now.Sub(at)), op, float64(limit))
+		}
+	default:
+		return invalid("unknown key")
+	}
+	return term, nil
+}
+
+func compareQuery(actual float64, op string, limit float64) bool {
+	switch op {
+	case "<":
+		return actual < limit
+	case "<=":
+		return actual <= limit
+	case ">":
+		return actual > limit
+	case ">=":
+		return actual >= limit
+	}
+	return actual == limit
+}
+
+// parseQuerySize parses a size such as 700M, 1.5GiB or 1024.
+func parseQuerySize(value string) (ByteSize, error) {
+	upper := strings.ToUpper(value)
+	upper = strings.TrimSuffix(strings.TrimSuffix(upper, "B"), "I")
+	unit := Byte
+	if n := len(upper); n > 0 {
+		switch upper[n-1] {
+		case 'K':
+			unit = KiB
+		case 'M':
+			unit = MiB
+		case 'G':
+			unit = GiB
+		case 'T':
+			unit = TiB
+		}
+		if unit != Byte {
+			upper = upper[:n-1]
+		}
+	}
+	number, err := strconv.ParseFloat(upper, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return ByteSize(number * float64(unit)), nil
+}
+
+// parseQueryAge parses an age such as 90s, 12h, 30d or 2w.
+func parseQueryAge(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	number, err := strconv.ParseFloat(value[:len(value)-1], 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return time.Duration(number * float64(unit)), nil
+}
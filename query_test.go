@@ -0,0 +1,61 @@
+package transmission
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	torrents := Torrents{
+		{ID: 1, Name: "Show S01E01", Status: StatusSeed, UploadRatio: 2.5, PercentDone: 1,
+			Labels: []string{"tv"}, DownloadDir: "/data/tv", SizeWhenDone: 2 * GiB,
+			AddedDate: int(now.Add(-10 * 24 * time.Hour).Unix()), ActivityDate: now.Add(-time.Hour),
+			TrackerStats: []TrackerStat{{Announce: "https://tracker.example.com/announce"}}},
+		{ID: 2, Name: "Debian 12", Status: StatusSeed, UploadRatio: 0.5, PercentDone: 1,
+			DownloadDir: "/data/iso", SizeWhenDone: 600 * MiB,
+			AddedDate:    int(now.Add(-60 * 24 * time.Hour).Unix()),
+			TrackerStats: []TrackerStat{{Announce: "udp://tracker.debian.org:6969"}}},
+		{ID: 3, Name: "Movie", Status: StatusDownload, PercentDone: 0.4,
+			Labels: []string{"movies", "tv shows"}, DownloadDir: "/data/tv-archive", SizeWhenDone: 8 * GiB,
+			AddedDate: int(now.Add(-time.Hour).Unix())},
+	}
+	ids := func(query string) []int {
+		q, err := ParseQuery(query)
+		So(err, ShouldBeNil)
+		q.now = func() time.Time { return now }
+		var matched []int
+		for _, torrent := range q.Filter(torrents) {
+			matched = append(matched, torrent.ID)
+		}
+		return matched
+	}
+
+	Convey("Test queries filter torrents", t, func() {
+		So(ids("status:seeding ratio>1.5 tracker:example label:tv added<30d"), ShouldResemble, []int{1})
+		So(ids("status:seeding"), ShouldResemble, []int{1, 2})
+		So(ids("-status:seeding"), ShouldResemble, []int{3})
+		So(ids("debian"), ShouldResemble, []int{2})
+		So(ids("-debian"), ShouldResemble, []int{1, 3})
+		So(ids("ratio<=0.5"), ShouldResemble, []int{2, 3})
+		So(ids("progress<50%"), ShouldResemble, []int{3})
+		So(ids("size>1GiB size<4g"), ShouldResemble, []int{1})
+		So(ids("added>30d"), ShouldResemble, []int{2})
+		So(ids("active<2h"), ShouldResemble, []int{1})
+		So(ids("dir:/data/tv/"), ShouldResemble, []int{1})
+		So(ids(`label:"tv shows"`), ShouldResemble, []int{3})
+		So(ids(""), ShouldResemble, []int{1, 2, 3})
+	})
+
+	Convey("Test malformed queries are rejected", t, func() {
+		for _, query := range []string{
+			"status:sleeping", "ratio>high", "size>lots", "added<30", "added<30y",
+			"label>tv", "colour:red", "name:", `label:"tv`,
+		} {
+			_, err := ParseQuery(query)
+			So(err, ShouldHaveSameTypeAs, &QueryError{})
+		}
+	})
+}
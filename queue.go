@@ -0,0 +1,199 @@
+package transmission
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SetQueueStalled set whether torrents idle for the given number of minutes
+// are considered stalled and no longer count against the queue limits.
+func (ac *TransmissionClient) SetQueueStalled(enabled bool, minutes int) error {
+	return ac.setSession(map[string]interface{}{
+		"queue-stalled-enabled": enabled,
+		"queue-stalled-minutes": minutes,
+	})
+}
+
+// IsStalled reports whether the daemon considers the torrent stalled. The
+// isStalled field must have been requested.
+func (t Torrent) IsStalled() bool {
+	return t.Stalled
+}
+
+// GetStalledTorrents get the active torrents the daemon considers stalled.
+// Torrents waiting in a queue are not included.
+func (ac *TransmissionClient) GetStalledTorrents() (Torrents, error) {
+	torrents, err := ac.GetTorrents()
+	if err != nil {
+		return nil, err
+	}
+
+	var stalled Torrents
+	for _, torrent := range torrents {
+		if !torrent.IsStalled() {
+			continue
+		}
+		if torrent.Status != StatusDownload && torrent.Status != StatusSeed {
+			continue
+		}
+		stalled = append(stalled, torrent)
+	}
+	return stalled, nil
+}
+
+// SetQueuePosition move the torrent to the given position in the queue,
+// where 0 is the front
+func (ac *TransmissionClient) SetQueuePosition(id int, position int) error {
+	return ac.setTorrent(id, map[string]interface{}{"queuePosition": position})
+}
+
+// QueueMoveTop move the torrent to the front of the queue
+func (ac *TransmissionClient) QueueMoveTop(id int) (string, error) {
+	return ac.sendSimpleCommand("queue-move-top", IDs(id))
+}
+
+// QueueMoveUp move the torrent one step towards the front of the queue
+func (ac *TransmissionClient) QueueMoveUp(id int) (string, error) {
+	return ac.sendSimpleCommand("queue-move-up", IDs(id))
+}
+
+// QueueMoveDown move the torrent one step towards the back of the queue
+func (ac *TransmissionClient) QueueMoveDown(id int) (string, error) {
+	return ac.sendSimpleCommand("queue-move-down", IDs(id))
+}
+
+// QueueMoveBottom move the torrent to the back of the queue
+func (ac *TransmissionClient) QueueMoveBottom(id int) (string, error) {
+	return ac.sendSimpleCommand("queue-move-bottom", IDs(id))
+}
+
+// queueMove sets the queue position of one torrent.
+type queueMove struct {
+	ID       int
+	Position int
+}
+
+// ApplyQueueOrder reorder the queue so the torrents in ids come first, in
+// that order. Torrents not listed keep their relative order behind them.
+// Only the torrents that are out of place are moved, so reordering a
+// mostly sorted queue costs a handful of requests.
+func (ac *TransmissionClient) ApplyQueueOrder(ids []int) error {
+	torrents, err := ac.GetTorrents()
+	if err != nil {
+		return err
+	}
+
+	queue := make(Torrents, len(torrents))
+	copy(queue, torrents)
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].QueuePosition < queue[j].QueuePosition
+	})
+	current := make([]int, len(queue))
+	for i, torrent := range queue {
+		current[i] = torrent.ID
+	}
+
+	moves, err := planQueueMoves(current, ids)
+	if err != nil {
+		return err
+	}
+	for _, move := range moves {
+		err = ac.SetQueuePosition(move.ID, move.Position)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planQueueMoves returns the moves that turn the current queue into target.
+// The longest run of torrents that are already in target order stays put;
+// every other torrent is moved directly behind its predecessor in target.
+func planQueueMoves(current, target []int) ([]queueMove, error) {
+	inQueue := make(map[int]bool, len(current))
+	for _, id := range current {
+		inQueue[id] = true
+	}
+	rank := make(map[int]int, len(current))
+	for _, id := range target {
+		if !inQueue[id] {
+			return nil, fmt.Errorf("torrent %d is not in the queue", id)
+		}
+		if _, ok := rank[id]; ok {
+			return nil, fmt.Errorf("torrent %d is listed twice", id)
+		}
+		rank[id] = len(rank)
+	}
+	order := append([]int(nil), target...)
+	for _, id := range current {
+		if _, ok := rank[id]; !ok {
+			rank[id] = len(rank)
+			order = append(order, id)
+		}
+	}
+
+	keep := longestIncreasing(current, rank)
+	queue := append([]int(nil), current...)
+	var moves []queueMove
+	for i, id := range order {
+		if keep[id] {
+			continue
+		}
+		position := 0
+		queue = removeID(queue, id)
+		if i > 0 {
+			position = indexOf(queue, order[i-1]) + 1
+		}
+		queue = append(queue[:position], append([]int{id}, queue[position:]...)...)
+		moves = append(moves, queueMove{ID: id, Position: position})
+	}
+	return moves, nil
+}
+
+// longestIncreasing returns the longest subsequence of ids whose ranks are
+// increasing.
+func longestIncreasing(ids []int, rank map[int]int) map[int]bool {
+	// tails[k] is the index in ids of the smallest tail of a run of length k+1.
+	var tails []int
+	prev := make([]int, len(ids))
+	for i, id := range ids {
+		k := sort.Search(len(tails), func(k int) bool {
+			return rank[ids[tails[k]]] >= rank[id]
+		})
+		prev[i] = -1
+		if k > 0 {
+			prev[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	keep := make(map[int]bool, len(tails))
+	if len(tails) == 0 {
+		return keep
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		keep[ids[i]] = true
+	}
+	return keep
+}
+
+func indexOf(ids []int, id int) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeID(ids []int, id int) []int {
+	i := indexOf(ids, id)
+	if i < 0 {
+		return ids
+	}
+	return append(ids[:i], ids[i+1:]...)
+}
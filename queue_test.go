@@ -0,0 +1,126 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueueStalledSettings(t *testing.T) {
+	tSetup(`{"arguments":{"queue-stalled-enabled":true,
+  "queue-stalled-minutes":30},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting and setting the stalled queue settings", t, func() {
+		session, err := transmissionClient.GetSession()
+		So(err, ShouldBeNil)
+		So(session.QueueStalledEnabled, ShouldBeTrue)
+		So(session.QueueStalledMinutes, ShouldEqual, 30)
+
+		So(transmissionClient.SetQueueStalled(true, 15), ShouldBeNil)
+	})
+}
+
+func TestTorrentIsStalled(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"isStalled":true},
+  {"id":2,"isStalled":false}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the stalled accessor", t, func() {
+		torrents, err := transmissionClient.GetTorrents()
+		So(err, ShouldBeNil)
+		So(torrents[0].IsStalled(), ShouldBeTrue)
+		So(torrents[1].IsStalled(), ShouldBeFalse)
+	})
+}
+
+func TestGetStalledTorrents(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"isStalled":true,"status":4},
+  {"id":2,"isStalled":true,"status":3},
+  {"id":3,"isStalled":false,"status":4}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test only active stalled torrents are listed", t, func() {
+		cmd, _ := NewGetTorrentsCmd()
+		So(cmd.Arguments.Fields, ShouldContain, "isStalled")
+
+		torrents, err := transmissionClient.GetStalledTorrents()
+		So(err, ShouldBeNil)
+		So(len(torrents), ShouldEqual, 1)
+		So(torrents[0].ID, ShouldEqual, 1)
+	})
+}
+
+func TestQueuePosition(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"queuePosition":3}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading and changing the queue position", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.QueuePosition, ShouldEqual, 3)
+
+		So(transmissionClient.SetQueuePosition(1, 0), ShouldBeNil)
+
+		result, err := transmissionClient.QueueMoveBottom(1)
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, "success")
+	})
+}
+
+// tApplyMoves replays moves the way the daemon applies queuePosition.
+func tApplyMoves(queue []int, moves []queueMove) []int {
+	queue = append([]int(nil), queue...)
+	for _, move := range moves {
+		queue = removeID(queue, move.ID)
+		queue = append(queue[:move.Position], append([]int{move.ID}, queue[move.Position:]...)...)
+	}
+	return queue
+}
+
+func TestPlanQueueMoves(t *testing.T) {
+	Convey("Test planning queue moves", t, func() {
+		current := []int{4, 1, 2, 3}
+		moves, err := planQueueMoves(current, []int{1, 2, 3, 4})
+		So(err, ShouldBeNil)
+		So(moves, ShouldResemble, []queueMove{{ID: 4, Position: 3}})
+
+		moves, err = planQueueMoves(current, current)
+		So(err, ShouldBeNil)
+		So(moves, ShouldBeEmpty)
+
+		target := []int{3, 4, 2, 1}
+		moves, err = planQueueMoves(current, target)
+		So(err, ShouldBeNil)
+		So(tApplyMoves(current, moves), ShouldResemble, target)
+		So(len(moves), ShouldEqual, 2)
+	})
+
+	Convey("Test unlisted torrents stay behind the listed ones", t, func() {
+		current := []int{1, 2, 3, 4, 5}
+		moves, err := planQueueMoves(current, []int{5, 3})
+		So(err, ShouldBeNil)
+		So(tApplyMoves(current, moves), ShouldResemble, []int{5, 3, 1, 2, 4})
+	})
+
+	Convey("Test unknown and repeated torrents are rejected", t, func() {
+		_, err := planQueueMoves([]int{1, 2}, []int{3})
+		So(err, ShouldNotBeNil)
+		_, err = planQueueMoves([]int{1, 2}, []int{1, 1})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestApplyQueueOrder(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"queuePosition":1},
+  {"id":2,"queuePosition":0}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test applying a queue order", t, func() {
+		So(transmissionClient.ApplyQueueOrder([]int{1, 2}), ShouldBeNil)
+		So(transmissionClient.ApplyQueueOrder([]int{7}), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,14 @@
+package transmission
+
+// RealRatio returns the upload ratio against everything actually pulled
+// from the swarm. The daemon takes pieces that fail their hash check out of
+// DownloadedEver, so CorruptEver is added back and the wasted volume counts
+// against the ratio, unlike uploadRatio. Returns 0 if nothing was
+// downloaded.
+func (t Torrent) RealRatio() float64 {
+	pulled := t.DownloadedEver + t.CorruptEver
+	if pulled <= 0 {
+		return 0
+	}
+	return float64(t.UploadedEver) / float64(pulled)
+}
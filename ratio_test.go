@@ -0,0 +1,25 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRealRatio(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"downloadedEver":2000,
+  "uploadedEver":3000,"corruptEver":500}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the lifetime counters and real ratio", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.DownloadedEver, ShouldEqual, 2000)
+		So(torrent.UploadedEver, ShouldEqual, 3000)
+		So(torrent.CorruptEver, ShouldEqual, 500)
+		So(torrent.RealRatio(), ShouldEqual, 1.2)
+
+		So(Torrent{UploadedEver: 10}.RealRatio(), ShouldEqual, 0)
+		So(Torrent{UploadedEver: 10, CorruptEver: 10}.RealRatio(), ShouldEqual, 1)
+	})
+}
@@ -0,0 +1,190 @@
+package transmission
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReconnectPolicy configures connection supervision. When a request fails
+// at the transport level it is retried with exponential backoff, and once
+// FailureThreshold consecutive failures have been seen the HTTP transport
+// and session token are thrown away so the next attempt starts from a
+// clean connection. A torrent-add or queue move is only resent when it
+// failed before reaching the daemon, as resending it could apply it twice;
+// see AddTorrentWithRetry for retrying adds.
+type ReconnectPolicy struct {
+	// FailureThreshold is the number of consecutive transport failures
+	// after which the transport is reset. Defaults to 3.
+	FailureThreshold int
+	// MaxRetries is the number of times a failed request is retried.
+	// Defaults to 5.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry. Defaults to
+	// 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Defaults to 30s.
+	MaxBackoff time.Duration
+}
+
+// WithReconnect enables connection supervision with the given policy.
+func WithReconnect(policy ReconnectPolicy) Option {
+	return func(ac *ApiClient) {
+		if policy.FailureThreshold <= 0 {
+			policy.FailureThreshold = 3
+		}
+		if policy.MaxRetries <= 0 {
+			policy.MaxRetries = 5
+		}
+		if policy.InitialBackoff <= 0 {
+			policy.InitialBackoff = 500 * time.Millisecond
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = 30 * time.Second
+		}
+		ac.reconnect = &policy
+	}
+}
+
+// nonIdempotent are the methods that are not resent, by reconnects or
+// failover, after a failure that may have happened once the daemon
+// received the request: adding twice and moving in the queue twice aren't
+// the same as doing it once.
+var nonIdempotent = map[string]bool{
+	"torrent-add":     true,
+	"queue-move-up":   true,
+	"queue-move-down": true,
+}
+
+// supervisedPost is post with the retries and transport resets of the
+// client's ReconnectPolicy.
+func (ac *ApiClient) supervisedPost(ctx context.Context, body requestBody) (int, []byte, error) {
+	policy := ac.reconnect
+	backoff := policy.InitialBackoff
+
+	for attempt := 0; ; attempt++ {
+		status, resBody, err := ac.postAny(ctx, body)
+		if err == nil {
+			ac.conn.succeeded()
+			return status, resBody, nil
+		}
+		if ctx.Err() != nil || attempt >= policy.MaxRetries {
+			return status, resBody, err
+		}
+		if nonIdempotent[body.method] && !requestUnsent(err) {
+			return status, resBody, err
+		}
+
+		ac.stats.retry()
+		ac.log(ctx, slog.LevelWarn, "retrying rpc request",
+			slog.Int("attempt", attempt+1), slog.Duration("backoff", backoff), slog.Any("error", err))
+		if ac.conn.failed(policy.FailureThreshold, ac.newTransport) {
+			ac.token.reset()
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, resBody, err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// requestUnsent reports whether err happened before the request could
+// reach the daemon, so resending it can't apply it twice.
+func requestUnsent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial" || errors.As(err, new(CircuitOpenError))
+}
+
+// resetTransport drops all pooled connections and the session token.
+func (ac *ApiClient) resetTransport() {
+	ac.conn.reset(ac.newTransport)
+	ac.token.reset()
+}
+
+// connection is the HTTP client of an ApiClient and the count of
+// consecutive transport failures seen through it. The copies of an
+// ApiClient share it, so requests running concurrently see one consistent
+// transport.
+type connection struct {
+	mu       sync.Mutex
+	client   http.Client
+	failures int
+}
+
+// httpClient returns the client to send a request with. A reset replaces
+// the transport for the requests that follow, while the ones in flight
+// finish on the old one.
+func (c *connection) httpClient() *http.Client {
+	if c == nil {
+		return &http.Client{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client := c.client
+	return &client
+}
+
+// clone returns a connection with the same client and no failures.
+func (c *connection) clone() *connection {
+	return &connection{client: *c.httpClient()}
+}
+
+// closeIdle closes the idle connections of the transport, unless it is
+// http.DefaultTransport.
+func (c *connection) closeIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if closer, ok := c.client.Transport.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func (c *connection) succeeded() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = 0
+}
+
+// failed counts a transport failure, and resets the transport once there
+// were threshold of them in a row. It reports whether it did.
+func (c *connection) failed(threshold int, newTransport func() http.RoundTripper) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures++
+	if c.failures < threshold {
+		return false
+	}
+	c.resetLocked(newTransport)
+	return true
+}
+
+func (c *connection) reset(newTransport func() http.RoundTripper) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resetLocked(newTransport)
+}
+
+// resetLocked swaps in a new transport and closes the idle connections of
+// the old one. A nil transport is http.DefaultTransport, which is shared
+// with the rest of the process and so left alone.
+func (c *connection) resetLocked(newTransport func() http.RoundTripper) {
+	old := c.client.Transport
+	c.client.Transport = newTransport()
+	c.failures = 0
+	if closer, ok := old.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,130 @@
+package transmission
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// flakyServer answers RPC requests on a fixed address after the first
+// fails requests have had their connection dropped.
+func flakyServer(fails int) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	requests := 0
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= fails {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Header().Set("X-Transmission-Session-Id", "123")
+		fmt.Fprint(w, `{"arguments":{},"result":"success"}`)
+	})}
+	go server.Serve(listener)
+	return "http://" + listener.Addr().String(), func() { server.Close() }
+}
+
+func TestReconnect(t *testing.T) {
+	Convey("Test requests are retried after transport failures", t, func() {
+		url, stop := flakyServer(4)
+		defer stop()
+
+		client := New(url, "", "", WithReconnect(ReconnectPolicy{
+			FailureThreshold: 2,
+			InitialBackoff:   time.Millisecond,
+		}))
+		_, err := client.StartTorrent(1)
+		So(err, ShouldBeNil)
+		So(client.apiclient.conn.failures, ShouldEqual, 0)
+	})
+
+	Convey("Test retries give up after MaxRetries", t, func() {
+		url, stop := flakyServer(100)
+		defer stop()
+
+		client := New(url, "", "", WithReconnect(ReconnectPolicy{
+			MaxRetries:     2,
+			InitialBackoff: time.Millisecond,
+		}))
+		_, err := client.StartTorrent(1)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test retries stop when the context is done", t, func() {
+		url, stop := flakyServer(100)
+		defer stop()
+
+		client := New(url, "", "", WithReconnect(ReconnectPolicy{
+			InitialBackoff: time.Hour,
+		}))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := client.Ping(ctx)
+		So(err, ShouldNotBeNil)
+		So(time.Since(start), ShouldBeLessThan, time.Second)
+	})
+	Convey("Test concurrent requests share the failure count and transport", t, func() {
+		var requests atomic.Int64
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= 8 {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+			w.Header().Set("X-Transmission-Session-Id", "123")
+			fmt.Fprint(w, `{"arguments":{},"result":"success"}`)
+		})}
+		go server.Serve(listener)
+		defer server.Close()
+
+		client := New("http://"+listener.Addr().String(), "", "", WithReconnect(ReconnectPolicy{
+			FailureThreshold: 1,
+			MaxRetries:       20,
+			InitialBackoff:   time.Millisecond,
+		}))
+		var wg sync.WaitGroup
+		errs := make([]error, 4)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = client.CallRaw(context.Background(), "session-stats", nil)
+			}(i)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			So(err, ShouldBeNil)
+		}
+	})
+
+	Convey("Test torrent-add isn't resent once the daemon may have it", t, func() {
+		url, stop := flakyServer(100)
+		defer stop()
+
+		client := New(url, "", "", WithReconnect(ReconnectPolicy{
+			InitialBackoff: time.Millisecond,
+		}))
+		_, err := client.AddTorrent("magnet:?xt=urn:btih:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", AddTorrentOptions{})
+		So(err, ShouldNotBeNil)
+		So(client.Stats().Retries, ShouldEqual, 0)
+
+		_, err = client.QueueMoveUp(1)
+		So(err, ShouldNotBeNil)
+		So(client.Stats().Retries, ShouldEqual, 0)
+	})
+}
@@ -0,0 +1,264 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RemovalAction is what a RemovalPolicy does with a torrent that is due.
+type RemovalAction int
+
+const (
+	// ActionStop stops the torrent.
+	ActionStop RemovalAction = iota
+	// ActionRemove removes the torrent and keeps its data.
+	ActionRemove
+	// ActionRemoveData removes the torrent and deletes its data.
+	ActionRemoveData
+)
+
+func (a RemovalAction) String() string {
+	switch a {
+	case ActionStop:
+		return "stop"
+	case ActionRemove:
+		return "remove"
+	case ActionRemoveData:
+		return "remove+delete"
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the action by name.
+func (a RemovalAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an action name as returned by String.
+func (a *RemovalAction) UnmarshalText(text []byte) error {
+	for _, action := range []RemovalAction{ActionStop, ActionRemove, ActionRemoveData} {
+		if action.String() == string(text) {
+			*a = action
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown removal action %q", text)
+}
+
+// RemovalRule selects finished torrents by label and tracker and makes them
+// due once they reach MinRatio or have seeded for MinSeedTime, whichever
+// comes first, or both with RequireAll. A zero threshold is ignored.
+type RemovalRule struct {
+	// Label the torrent must have. Empty matches every torrent.
+	Label string
+	// Tracker is matched against the host of the torrent's announce URLs.
+	// Empty matches every torrent.
+	Tracker     string
+	MinRatio    float64
+	MinSeedTime time.Duration
+	// RequireAll makes the torrent due only once every threshold that is
+	// set has been reached.
+	RequireAll bool
+	Action     RemovalAction
+}
+
+// Matches reports whether the rule applies to the torrent.
+func (r RemovalRule) Matches(t Torrent) bool {
+	if r.Label != "" && !t.HasLabel(r.Label) {
+		return false
+	}
+	return usesTracker(t, r.Tracker)
+}
+
+// usesTracker reports whether one of the torrent's announce URLs contains
+// tracker or has the same TrackerIdentity. An empty tracker matches every
+// torrent.
+func usesTracker(t Torrent, tracker string) bool {
+	if tracker == "" {
+		return true
+	}
+	identity := TrackerIdentity(tracker)
+	for _, stat := range t.TrackerStats {
+		if strings.Contains(stat.Host, tracker) || strings.Contains(stat.Announce, tracker) {
+			return true
+		}
+		if trackerHost(stat) == identity {
+			return true
+		}
+	}
+	return false
+}
+
+// Due reports whether the torrent has finished and reached one of the
+// rule's thresholds.
+func (r RemovalRule) Due(t Torrent) bool {
+	if t.PercentDone < 1 {
+		return false
+	}
+	ratio := r.MinRatio > 0 && t.UploadRatio >= r.MinRatio
+	seedTime := r.MinSeedTime > 0 && t.SecondsSeeding >= r.MinSeedTime
+	if r.RequireAll {
+		return (r.MinRatio > 0 || r.MinSeedTime > 0) &&
+			(ratio || r.MinRatio <= 0) && (seedTime || r.MinSeedTime <= 0)
+	}
+	return ratio || seedTime
+}
+
+// RemovalDecision is an action a RemovalPolicy took, or would take in dry
+// run mode.
+type RemovalDecision struct {
+	Torrent Torrent
+	Rule    RemovalRule
+	Action  RemovalAction
+}
+
+// RemovalPolicy stops or removes torrents according to the first rule
+// that matches them.
+type RemovalPolicy struct {
+	Rules []RemovalRule
+	// DryRun makes Apply and Run only report what they would do.
+	DryRun bool
+	// Interval between evaluations in Run. Defaults to ten minutes.
+	Interval time.Duration
+	// OnDecision is called for every decision Run makes.
+	OnDecision func(RemovalDecision)
+	// OnError is called when Run fails to fetch or act on torrents.
+	OnError func(error)
+	// AuditLog receives an AuditEntry as a line of JSON for every decision
+	// Apply carries out, or would carry out in dry run mode.
+	AuditLog io.Writer
+
+	client *TransmissionClient
+}
+
+// AuditEntry records a decision of a RemovalPolicy.
+type AuditEntry struct {
+	Time   time.Time   `json:"time"`
+	ID     int         `json:"id"`
+	Hash   string      `json:"hash"`
+	Name   string      `json:"name"`
+	Action string      `json:"action"`
+	Rule   RemovalRule `json:"rule"`
+	DryRun bool        `json:"dryRun"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// audit writes an entry for decision to the audit log.
+func (p *RemovalPolicy) audit(decision RemovalDecision, err error) {
+	if p.AuditLog == nil {
+		return
+	}
+	entry := AuditEntry{
+		Time:   time.Now(),
+		ID:     decision.Torrent.ID,
+		Hash:   decision.Torrent.HashString,
+		Name:   decision.Torrent.Name,
+		Action: decision.Action.String(),
+		Rule:   decision.Rule,
+		DryRun: p.DryRun,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	line, _ := json.Marshal(entry)
+	p.AuditLog.Write(append(line, '\n'))
+}
+
+// NewRemovalPolicy create a policy acting through client
+func NewRemovalPolicy(client *TransmissionClient, rules ...RemovalRule) *RemovalPolicy {
+	return &RemovalPolicy{
+		Rules:    rules,
+		Interval: 10 * time.Minute,
+		client:   client,
+	}
+}
+
+// Evaluate returns the decisions for torrents without acting on them.
+func (p *RemovalPolicy) Evaluate(torrents Torrents) []RemovalDecision {
+	var decisions []RemovalDecision
+	for _, torrent := range torrents {
+		for _, rule := range p.Rules {
+			if !rule.Matches(torrent) {
+				continue
+			}
+			if rule.Due(torrent) && !(rule.Action == ActionStop && torrent.Status == StatusPaused) {
+				decisions = append(decisions, RemovalDecision{torrent, rule, rule.Action})
+			}
+			break
+		}
+	}
+	return decisions
+}
+
+// Apply fetches the torrents, evaluates them and carries out the decisions
+// unless DryRun is set. It returns the decisions that were carried out, or
+// would have been, and stops at the first error.
+func (p *RemovalPolicy) Apply() ([]RemovalDecision, error) {
+	return p.ApplyContext(context.Background())
+}
+
+// ApplyContext is like Apply but aborts the requests when ctx is done.
+func (p *RemovalPolicy) ApplyContext(ctx context.Context) ([]RemovalDecision, error) {
+	torrents, err := p.client.fetchTorrents(ctx, torrentGetFields, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := p.Evaluate(torrents)
+	if p.DryRun {
+		for _, decision := range decisions {
+			p.audit(decision, nil)
+		}
+		return decisions, nil
+	}
+
+	for i, decision := range decisions {
+		err = p.client.carryOut(ctx, decision.Torrent.ID, decision.Action)
+		p.audit(decision, err)
+		if err != nil {
+			return decisions[:i], err
+		}
+	}
+	return decisions, nil
+}
+
+// carryOut performs action on the torrent with id.
+func (ac *TransmissionClient) carryOut(ctx context.Context, id int, action RemovalAction) error {
+	if action == ActionStop {
+		_, err := invoke[struct{}](ctx, ac, "torrent-stop", idsArgs{Ids: IDs(id)})
+		return err
+	}
+	return ac.rpcContext(ctx, "torrent-remove", torrentRemoveArgs{Ids: IDs(id), DeleteData: action == ActionRemoveData}, nil)
+}
+
+// Run applies the policy every interval until ctx is done.
+func (p *RemovalPolicy) Run(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		decisions, err := p.ApplyContext(ctx)
+		if err != nil && p.OnError != nil {
+			p.OnError(err)
+		}
+		if p.OnDecision != nil {
+			for _, decision := range decisions {
+				p.OnDecision(decision)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package transmission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRemovalRules(t *testing.T) {
+	seeded := Torrent{
+		ID:             1,
+		PercentDone:    1,
+		UploadRatio:    2.5,
+		SecondsSeeding: 3 * 24 * time.Hour,
+		Labels:         []string{"tv"},
+		TrackerStats:   []TrackerStat{{Host: "tracker.example.com:443"}},
+	}
+
+	Convey("Test rules match by label and tracker", t, func() {
+		So(RemovalRule{}.Matches(seeded), ShouldBeTrue)
+		So(RemovalRule{Label: "tv"}.Matches(seeded), ShouldBeTrue)
+		So(RemovalRule{Label: "movies"}.Matches(seeded), ShouldBeFalse)
+		So(RemovalRule{Tracker: "example.com"}.Matches(seeded), ShouldBeTrue)
+		So(RemovalRule{Tracker: "other.org"}.Matches(seeded), ShouldBeFalse)
+	})
+
+	Convey("Test rules are due on ratio or seed time", t, func() {
+		So(RemovalRule{MinRatio: 2}.Due(seeded), ShouldBeTrue)
+		So(RemovalRule{MinRatio: 3}.Due(seeded), ShouldBeFalse)
+		So(RemovalRule{MinRatio: 3, MinSeedTime: 48 * time.Hour}.Due(seeded), ShouldBeTrue)
+		So(RemovalRule{}.Due(seeded), ShouldBeFalse)
+
+		downloading := seeded
+		downloading.PercentDone = 0.5
+		So(RemovalRule{MinRatio: 2}.Due(downloading), ShouldBeFalse)
+	})
+
+	Convey("Test the first matching rule decides", t, func() {
+		policy := NewRemovalPolicy(nil,
+			RemovalRule{Label: "tv", MinRatio: 10, Action: ActionRemove},
+			RemovalRule{MinRatio: 1, Action: ActionRemoveData},
+		)
+		So(policy.Evaluate(Torrents{seeded}), ShouldBeEmpty)
+
+		policy.Rules[0].MinRatio = 2
+		decisions := policy.Evaluate(Torrents{seeded})
+		So(len(decisions), ShouldEqual, 1)
+		So(decisions[0].Action, ShouldEqual, ActionRemove)
+		So(decisions[0].Action.String(), ShouldEqual, "remove")
+	})
+}
+
+func TestRemovalPolicyApply(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"percentDone":1,"uploadRatio":2.5,"status":6},
+  {"id":2,"percentDone":1,"uploadRatio":0.5,"status":6},
+  {"id":3,"percentDone":1,"uploadRatio":3,"status":0}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test a dry run reports without acting", t, func() {
+		policy := NewRemovalPolicy(&transmissionClient,
+			RemovalRule{MinRatio: 2, Action: ActionStop})
+		policy.DryRun = true
+
+		decisions, err := policy.Apply()
+		So(err, ShouldBeNil)
+		So(len(decisions), ShouldEqual, 1)
+		So(decisions[0].Torrent.ID, ShouldEqual, 1)
+	})
+
+	Convey("Test applying the policy", t, func() {
+		policy := NewRemovalPolicy(&transmissionClient,
+			RemovalRule{MinRatio: 2, Action: ActionRemoveData})
+
+		decisions, err := policy.Apply()
+		So(err, ShouldBeNil)
+		So(len(decisions), ShouldEqual, 2)
+	})
+}
+
+func TestRemovalRuleRequireAll(t *testing.T) {
+	Convey("Test every threshold must be reached with RequireAll", t, func() {
+		rule := RemovalRule{MinRatio: 2, MinSeedTime: 72 * time.Hour, RequireAll: true}
+		So(rule.Due(Torrent{PercentDone: 1, UploadRatio: 3, SecondsSeeding: time.Hour}), ShouldBeFalse)
+		So(rule.Due(Torrent{PercentDone: 1, UploadRatio: 3, SecondsSeeding: 80 * time.Hour}), ShouldBeTrue)
+
+		rule.MinSeedTime = 0
+		So(rule.Due(Torrent{PercentDone: 1, UploadRatio: 3}), ShouldBeTrue)
+		So(RemovalRule{RequireAll: true}.Due(Torrent{PercentDone: 1, UploadRatio: 3}), ShouldBeFalse)
+	})
+}
+
+func TestRemovalPolicyAuditLog(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"name":"a","hashString":"aaaa","percentDone":1,"uploadRatio":2.5,"status":6},
+  {"id":2,"percentDone":1,"uploadRatio":0.5,"status":6}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test decisions are written to the audit log", t, func() {
+		var log bytes.Buffer
+		policy := NewRemovalPolicy(&transmissionClient,
+			RemovalRule{Tracker: "", MinRatio: 2, Action: ActionRemove})
+		policy.DryRun = true
+		policy.AuditLog = &log
+
+		_, err := policy.Apply()
+		So(err, ShouldBeNil)
+
+		var entry AuditEntry
+		So(json.Unmarshal(log.Bytes(), &entry), ShouldBeNil)
+		So(entry.ID, ShouldEqual, 1)
+		So(entry.Hash, ShouldEqual, "aaaa")
+		So(entry.Action, ShouldEqual, "remove")
+		So(entry.DryRun, ShouldBeTrue)
+		So(log.String(), ShouldContainSubstring, `"Action":"remove"`)
+	})
+
+	Convey("Test Run returns while the daemon hangs", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+		policy := NewRemovalPolicy(&client, RemovalRule{MinRatio: 2, Action: ActionRemove})
+		So(tRunFor(policy.Run, 50*time.Millisecond), ShouldEqual, context.DeadlineExceeded)
+	})
+}
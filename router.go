@@ -0,0 +1,82 @@
+package transmission
+
+import "context"
+
+// DirRouter maps labels to download directories.
+type DirRouter struct {
+	// Routes maps a label to the directory its torrents belong in.
+	Routes map[string]string
+	// Default is used for torrents without a routed label. Empty leaves
+	// the daemon's default download directory.
+	Default string
+}
+
+// DirFor returns the directory for a torrent with labels. The first label
+// with a route wins.
+func (r DirRouter) DirFor(labels []string) (string, bool) {
+	for _, label := range labels {
+		if dir, ok := r.Routes[label]; ok {
+			return dir, true
+		}
+	}
+	if r.Default != "" {
+		return r.Default, true
+	}
+	return "", false
+}
+
+// Route returns opts with the download directory set from its labels,
+// unless one was set explicitly. Use it with AddTorrent:
+//
+//	client.AddTorrent(magnet, router.Route(AddTorrentOptions{Labels: []string{"tv"}}))
+func (r DirRouter) Route(opts AddTorrentOptions) AddTorrentOptions {
+	if opts.DownloadDir != "" {
+		return opts
+	}
+	if dir, ok := r.DirFor(opts.Labels); ok {
+		opts.DownloadDir = dir
+	}
+	return opts
+}
+
+// SetLocation change where the torrent's data is stored. With move the
+// daemon moves the data, otherwise it looks for the data in location.
+func (ac *TransmissionClient) SetLocation(id int, location string, move bool) error {
+	return ac.setLocation(context.Background(), id, location, move)
+}
+
+func (ac *TransmissionClient) setLocation(ctx context.Context, id int, location string, move bool) error {
+	return ac.rpcContext(ctx, "torrent-set-location",
+		torrentSetLocationArgs{Ids: IDs(id), Location: location, Move: move}, nil)
+}
+
+// RouteExisting moves every torrent whose download directory doesn't match
+// its route, and returns the IDs that were moved.
+func (ac *TransmissionClient) RouteExisting(router DirRouter) ([]int, error) {
+	torrents, err := ac.GetTorrents()
+	if err != nil {
+		return nil, err
+	}
+
+	var moved []int
+	for _, torrent := range torrents {
+		dir, ok := router.DirFor(torrent.Labels)
+		if !ok || cleanDir(dir) == cleanDir(torrent.DownloadDir) {
+			continue
+		}
+		err = ac.SetLocation(torrent.ID, dir, true)
+		if err != nil {
+			return moved, err
+		}
+		moved = append(moved, torrent.ID)
+	}
+	return moved, nil
+}
+
+// cleanDir strips trailing slashes so "/data/" and "/data" compare equal.
+func cleanDir(dir string) string {
+	for len(dir) > 1 && dir[len(dir)-1] == '/' {
+		dir = dir[:len(dir)-1]
+	}
+	return dir
+}
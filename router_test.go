@@ -0,0 +1,52 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testRouter = DirRouter{
+	Routes: map[string]string{
+		"tv":     "/data/tv",
+		"movies": "/data/movies",
+	},
+}
+
+func TestDirRouter(t *testing.T) {
+	Convey("Test routing labels to directories", t, func() {
+		dir, ok := testRouter.DirFor([]string{"hd", "movies", "tv"})
+		So(ok, ShouldBeTrue)
+		So(dir, ShouldEqual, "/data/movies")
+
+		_, ok = testRouter.DirFor([]string{"books"})
+		So(ok, ShouldBeFalse)
+
+		withDefault := testRouter
+		withDefault.Default = "/data/other"
+		dir, _ = withDefault.DirFor(nil)
+		So(dir, ShouldEqual, "/data/other")
+	})
+
+	Convey("Test routing add options", t, func() {
+		opts := testRouter.Route(AddTorrentOptions{Labels: []string{"tv"}})
+		So(opts.DownloadDir, ShouldEqual, "/data/tv")
+
+		opts = testRouter.Route(AddTorrentOptions{Labels: []string{"tv"}, DownloadDir: "/tmp"})
+		So(opts.DownloadDir, ShouldEqual, "/tmp")
+	})
+}
+
+func TestRouteExisting(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"labels":["tv"],"downloadDir":"/data/tv/"},
+  {"id":2,"labels":["movies"],"downloadDir":"/downloads"},
+  {"id":3,"labels":[],"downloadDir":"/downloads"}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test misplaced torrents are moved", t, func() {
+		moved, err := transmissionClient.RouteExisting(testRouter)
+		So(err, ShouldBeNil)
+		So(moved, ShouldResemble, []int{2})
+	})
+}
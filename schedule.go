@@ -0,0 +1,191 @@
+package transmission
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduleRule applies speed limits during a daily time window on some
+// days of the week.
+type ScheduleRule struct {
+	// Days the rule applies on. Empty means every day.
+	Days []time.Weekday
+	// Start and End are offsets from midnight. If End is before Start the
+	// window wraps past midnight and ends the next day.
+	Start, End time.Duration
+	Down, Up   Rate
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday,
+	"wed": time.Wednesday, "thu": time.Thursday, "fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseScheduleRule parse a rule like "Mon-Fri 09:00-17:00". The days can
+// be "*", a single day, a range or a comma separated list of those.
+func ParseScheduleRule(spec string, down, up Rate) (ScheduleRule, error) {
+	rule := ScheduleRule{Down: down, Up: up}
+	fields := strings.Fields(spec)
+	if len(fields) != 2 {
+		return rule, fmt.Errorf("schedule rule %q: want \"<days> <HH:MM>-<HH:MM>\"", spec)
+	}
+
+	if fields[0] != "*" {
+		for _, part := range strings.Split(strings.ToLower(fields[0]), ",") {
+			bounds := strings.SplitN(part, "-", 2)
+			first, ok := weekdays[bounds[0]]
+			if !ok {
+				return rule, fmt.Errorf("schedule rule %q: unknown day %q", spec, bounds[0])
+			}
+			last := first
+			if len(bounds) == 2 {
+				last, ok = weekdays[bounds[1]]
+				if !ok {
+					return rule, fmt.Errorf("schedule rule %q: unknown day %q", spec, bounds[1])
+				}
+			}
+			for day := first; ; day = (day + 1) % 7 {
+				rule.Days = append(rule.Days, day)
+				if day == last {
+					break
+				}
+			}
+		}
+	}
+
+	window := strings.SplitN(fields[1], "-", 2)
+	if len(window) != 2 {
+		return rule, fmt.Errorf("schedule rule %q: want a time window like 09:00-17:00", spec)
+	}
+	var err error
+	rule.Start, err = parseClock(window[0])
+	if err != nil {
+		return rule, fmt.Errorf("schedule rule %q: %v", spec, err)
+	}
+	rule.End, err = parseClock(window[1])
+	if err != nil {
+		return rule, fmt.Errorf("schedule rule %q: %v", spec, err)
+	}
+	return rule, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(clock string) (time.Duration, error) {
+	parts := strings.SplitN(clock, ":", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 24 {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// Active reports whether the rule applies at t.
+func (r ScheduleRule) Active(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+
+	if r.Start <= r.End {
+		return r.onDay(t.Weekday()) && offset >= r.Start && offset < r.End
+	}
+	// The window wraps midnight: the evening part belongs to today, the
+	// morning part to the day before.
+	if offset >= r.Start {
+		return r.onDay(t.Weekday())
+	}
+	return offset < r.End && r.onDay((t.Weekday()+6)%7)
+}
+
+func (r ScheduleRule) onDay(day time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, d := range r.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// BandwidthScheduler applies the speed limits of the first active rule,
+// or the default limits when none is active, with session-set.
+type BandwidthScheduler struct {
+	Rules []ScheduleRule
+	// DefaultDown and DefaultUp apply outside of all rules. 0 is unlimited.
+	DefaultDown, DefaultUp Rate
+	// Interval between checks. Defaults to one minute.
+	Interval time.Duration
+	// OnError is called when the limits couldn't be applied. The scheduler
+	// keeps running and tries again on the next check.
+	OnError func(error)
+
+	client  *TransmissionClient
+	now     func() time.Time
+	applied *[2]Rate
+}
+
+// NewBandwidthScheduler create a scheduler applying rules through client
+func NewBandwidthScheduler(client *TransmissionClient, rules ...ScheduleRule) *BandwidthScheduler {
+	return &BandwidthScheduler{
+		Rules:    rules,
+		Interval: time.Minute,
+		client:   client,
+		now:      time.Now,
+	}
+}
+
+// Limits returns the download and upload limits that apply at t.
+func (s *BandwidthScheduler) Limits(t time.Time) (Rate, Rate) {
+	for _, rule := range s.Rules {
+		if rule.Active(t) {
+			return rule.Down, rule.Up
+		}
+	}
+	return s.DefaultDown, s.DefaultUp
+}
+
+// Run applies the limits now and then on every interval until ctx is done.
+// The limits are only sent when they change. Start it with go s.Run(ctx).
+func (s *BandwidthScheduler) Run(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.apply(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *BandwidthScheduler) apply(ctx context.Context) {
+	down, up := s.Limits(s.now())
+	if s.applied != nil && s.applied[0] == down && s.applied[1] == up {
+		return
+	}
+	err := s.client.setSpeedLimits(ctx, down, up)
+	if err != nil {
+		if s.OnError != nil {
+			s.OnError(err)
+		}
+		return
+	}
+	s.applied = &[2]Rate{down, up}
+}
@@ -0,0 +1,116 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseScheduleRule(t *testing.T) {
+	Convey("Test parsing schedule rules", t, func() {
+		rule, err := ParseScheduleRule("Mon-Fri 09:00-17:30", MBps, 100*KBps)
+		So(err, ShouldBeNil)
+		So(rule.Days, ShouldResemble, []time.Weekday{time.Monday, time.Tuesday,
+			time.Wednesday, time.Thursday, time.Friday})
+		So(rule.Start, ShouldEqual, 9*time.Hour)
+		So(rule.End, ShouldEqual, 17*time.Hour+30*time.Minute)
+		So(rule.Down, ShouldEqual, MBps)
+
+		rule, err = ParseScheduleRule("Fri-Mon,Wed 23:00-06:00", 0, 0)
+		So(err, ShouldBeNil)
+		So(len(rule.Days), ShouldEqual, 5)
+
+		rule, err = ParseScheduleRule("* 00:00-24:00", 0, 0)
+		So(err, ShouldBeNil)
+		So(rule.Days, ShouldBeEmpty)
+
+		for _, spec := range []string{"", "Mon", "Funday 09:00-10:00",
+			"Mon 9-10", "Mon 25:00-26:00", "Mon 09:60-10:00"} {
+			_, err = ParseScheduleRule(spec, 0, 0)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestScheduleRuleActive(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	Convey("Test rule windows", t, func() {
+		work, _ := ParseScheduleRule("Mon-Fri 09:00-17:00", 0, 0)
+		So(work.Active(at(1, 9, 0)), ShouldBeTrue)
+		So(work.Active(at(1, 16, 59)), ShouldBeTrue)
+		So(work.Active(at(1, 17, 0)), ShouldBeFalse)
+		So(work.Active(at(6, 12, 0)), ShouldBeFalse)
+
+		night, _ := ParseScheduleRule("Fri 23:00-06:00", 0, 0)
+		So(night.Active(at(5, 23, 30)), ShouldBeTrue)
+		So(night.Active(at(6, 5, 0)), ShouldBeTrue)
+		So(night.Active(at(6, 23, 30)), ShouldBeFalse)
+		So(night.Active(at(5, 5, 0)), ShouldBeFalse)
+	})
+}
+
+func TestBandwidthScheduler(t *testing.T) {
+	Convey("Test the scheduler applies limits when they change", t, func() {
+		var mu sync.Mutex
+		var sets []map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			var req struct {
+				Method    string                 `json:"method"`
+				Arguments map[string]interface{} `json:"arguments"`
+			}
+			json.Unmarshal(body, &req)
+			if req.Method == "session-set" {
+				mu.Lock()
+				sets = append(sets, req.Arguments)
+				mu.Unlock()
+			}
+			fmt.Fprint(w, `{"arguments":{},"result":"success"}`)
+		}))
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		work, _ := ParseScheduleRule("* 09:00-17:00", 500*KBps, 50*KBps)
+		scheduler := NewBandwidthScheduler(&client, work)
+		scheduler.Interval = time.Millisecond
+
+		now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+		scheduler.now = func() time.Time { return now }
+
+		scheduler.apply(context.Background())
+		scheduler.apply(context.Background())
+		now = now.Add(2 * time.Hour)
+		scheduler.apply(context.Background())
+
+		So(len(sets), ShouldEqual, 2)
+		So(sets[0]["speed-limit-down-enabled"], ShouldEqual, false)
+		So(sets[1]["speed-limit-down"], ShouldEqual, 500)
+		So(sets[1]["speed-limit-up"], ShouldEqual, 50)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		So(scheduler.Run(ctx), ShouldEqual, context.DeadlineExceeded)
+		So(len(sets), ShouldEqual, 2)
+	})
+
+	Convey("Test Run returns while the daemon hangs", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+		scheduler := NewBandwidthScheduler(&client)
+		So(tRunFor(scheduler.Run, 50*time.Millisecond), ShouldEqual, context.DeadlineExceeded)
+	})
+}
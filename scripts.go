@@ -0,0 +1,36 @@
+package transmission
+
+// SetScriptTorrentDone configure the script run when a torrent finishes
+// downloading.
+func (ac *TransmissionClient) SetScriptTorrentDone(enabled bool, filename string) error {
+	return ac.setSession(map[string]interface{}{
+		"script-torrent-done-enabled":  enabled,
+		"script-torrent-done-filename": filename,
+	})
+}
+
+// SetScriptTorrentAdded configure the script run when a torrent is added.
+// Requires RPC version 17 (Transmission 4.0).
+func (ac *TransmissionClient) SetScriptTorrentAdded(enabled bool, filename string) error {
+	err := ac.requireRPCVersion("script-torrent-added", 17)
+	if err != nil {
+		return err
+	}
+	return ac.setSession(map[string]interface{}{
+		"script-torrent-added-enabled":  enabled,
+		"script-torrent-added-filename": filename,
+	})
+}
+
+// SetScriptTorrentDoneSeeding configure the script run when a torrent
+// finishes seeding. Requires RPC version 17 (Transmission 4.0).
+func (ac *TransmissionClient) SetScriptTorrentDoneSeeding(enabled bool, filename string) error {
+	err := ac.requireRPCVersion("script-torrent-done-seeding", 17)
+	if err != nil {
+		return err
+	}
+	return ac.setSession(map[string]interface{}{
+		"script-torrent-done-seeding-enabled":  enabled,
+		"script-torrent-done-seeding-filename": filename,
+	})
+}
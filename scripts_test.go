@@ -0,0 +1,43 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScriptHooks(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":17,
+  "script-torrent-added-enabled":true,
+  "script-torrent-added-filename":"/usr/local/bin/added.sh",
+  "script-torrent-done-seeding-enabled":false,
+  "script-torrent-done-seeding-filename":""},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading the script hooks", t, func() {
+		session, err := transmissionClient.GetSession()
+		So(err, ShouldBeNil)
+		So(session.ScriptTorrentAddedEnabled, ShouldBeTrue)
+		So(session.ScriptTorrentAddedFilename, ShouldEqual, "/usr/local/bin/added.sh")
+		So(session.ScriptTorrentDoneSeedingEnabled, ShouldBeFalse)
+	})
+
+	Convey("Test setting the script hooks", t, func() {
+		So(transmissionClient.SetScriptTorrentDone(true, "/done.sh"), ShouldBeNil)
+		So(transmissionClient.SetScriptTorrentAdded(true, "/added.sh"), ShouldBeNil)
+		So(transmissionClient.SetScriptTorrentDoneSeeding(true, "/seeded.sh"), ShouldBeNil)
+	})
+}
+
+func TestScriptHooksOldDaemon(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":15},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the new script hooks are refused on RPC 15", t, func() {
+		err := transmissionClient.SetScriptTorrentAdded(true, "/added.sh")
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "requires RPC version 17")
+
+		So(transmissionClient.SetScriptTorrentDone(true, "/done.sh"), ShouldBeNil)
+	})
+}
@@ -0,0 +1,43 @@
+package transmission
+
+import "regexp"
+
+// SearchMatch is a torrent found by SearchTorrents.
+type SearchMatch struct {
+	Torrent Torrent
+	// NameMatched is set when the name of the torrent matched.
+	NameMatched bool
+	// Files are the indexes in Torrent.Files of the files whose path
+	// matched.
+	Files []int
+}
+
+// SearchTorrents returns the torrents whose name matches re and, with
+// includeFiles, the ones with a file whose path in the torrent does, e.g.
+// to find which torrent contains a given file.
+func (ac *TransmissionClient) SearchTorrents(re *regexp.Regexp, includeFiles bool) ([]SearchMatch, error) {
+	torrents, err := ac.GetTorrents()
+	if err != nil {
+		return nil, err
+	}
+	return torrents.Search(re, includeFiles), nil
+}
+
+// Search is SearchTorrents for torrents that were already fetched.
+func (t Torrents) Search(re *regexp.Regexp, includeFiles bool) []SearchMatch {
+	var matches []SearchMatch
+	for _, torrent := range t {
+		match := SearchMatch{Torrent: torrent, NameMatched: re.MatchString(torrent.Name)}
+		if includeFiles {
+			for i, file := range torrent.Files {
+				if re.MatchString(file.Name) {
+					match.Files = append(match.Files, i)
+				}
+			}
+		}
+		if match.NameMatched || len(match.Files) > 0 {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
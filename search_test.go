@@ -0,0 +1,38 @@
+package transmission
+
+import (
+	"regexp"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchTorrents(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"name":"Album","files":[{"name":"Album/01 Intro.flac","length":10},{"name":"Album/cover.jpg","length":1}]},
+  {"id":2,"name":"Photos 2023","files":[{"name":"Photos 2023/beach.jpg","length":5}]},
+  {"id":3,"name":"Movie","files":[{"name":"Movie/movie.mkv","length":50}]}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test searching torrent names", t, func() {
+		matches, err := transmissionClient.SearchTorrents(regexp.MustCompile(`(?i)photos|movie`), false)
+		So(err, ShouldBeNil)
+		So(matches, ShouldHaveLength, 2)
+		So(matches[0].Torrent.ID, ShouldEqual, 2)
+		So(matches[0].NameMatched, ShouldBeTrue)
+		So(matches[0].Files, ShouldBeEmpty)
+		So(matches[1].Torrent.ID, ShouldEqual, 3)
+	})
+
+	Convey("Test searching file paths", t, func() {
+		matches, err := transmissionClient.SearchTorrents(regexp.MustCompile(`\.jpg$`), true)
+		So(err, ShouldBeNil)
+		So(matches, ShouldHaveLength, 2)
+		So(matches[0].Torrent.ID, ShouldEqual, 1)
+		So(matches[0].NameMatched, ShouldBeFalse)
+		So(matches[0].Files, ShouldResemble, []int{1})
+		So(matches[1].Torrent.ID, ShouldEqual, 2)
+		So(matches[1].Files, ShouldResemble, []int{0})
+	})
+}
@@ -0,0 +1,10 @@
+package transmission
+
+// SetIdleSeedingLimit set the global number of idle minutes after which
+// seeding torrents are stopped, and whether the limit is enforced.
+func (ac *TransmissionClient) SetIdleSeedingLimit(minutes int, enabled bool) error {
+	return ac.setSession(map[string]interface{}{
+		"idle-seeding-limit":         minutes,
+		"idle-seeding-limit-enabled": enabled,
+	})
+}
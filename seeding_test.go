@@ -0,0 +1,22 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIdleSeedingLimit(t *testing.T) {
+	tSetup(`{"arguments":{"idle-seeding-limit":30,
+  "idle-seeding-limit-enabled":true},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting and setting the idle seeding limit", t, func() {
+		session, err := transmissionClient.GetSession()
+		So(err, ShouldBeNil)
+		So(session.IdleSeedingLimit, ShouldEqual, 30)
+		So(session.IdleSeedingLimitEnabled, ShouldBeTrue)
+
+		So(transmissionClient.SetIdleSeedingLimit(60, true), ShouldBeNil)
+	})
+}
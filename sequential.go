@@ -0,0 +1,14 @@
+package transmission
+
+// SetSequentialDownload enable or disable downloading the torrent's pieces
+// in order, e.g. to start playing media before it completes. Requires RPC
+// version 18 (Transmission 4.1).
+func (ac *TransmissionClient) SetSequentialDownload(id int, enabled bool) error {
+	err := ac.requireRPCVersion("sequential_download", 18)
+	if err != nil {
+		return err
+	}
+	return ac.setTorrent(id, map[string]interface{}{
+		"sequential_download": enabled,
+	})
+}
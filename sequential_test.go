@@ -0,0 +1,31 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSequentialDownload(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":18,
+  "torrents":[{"id":1,"sequential_download":true}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading and setting sequential download", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.SequentialDownload, ShouldBeTrue)
+
+		So(transmissionClient.SetSequentialDownload(1, false), ShouldBeNil)
+	})
+}
+
+func TestSequentialDownloadOldDaemon(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":17},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test sequential download is refused on RPC 17", t, func() {
+		err := transmissionClient.SetSequentialDownload(1, true)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,146 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Session holds the daemon settings returned by session-get. It covers
+// every documented session argument up to RPC version 17; fields a daemon
+// doesn't know about are left at their zero value. Speed limits are sent
+// in whole KB/s, so a Rate is rounded down to a multiple of KBps, and
+// a positive one below 1 KB/s up to KBps.
+type Session struct {
+	AltSpeedDown                     Rate         `json:"alt-speed-down"`
+	AltSpeedEnabled                  bool         `json:"alt-speed-enabled"`
+	AltSpeedTimeBegin                int          `json:"alt-speed-time-begin"`
+	AltSpeedTimeDay                  int          `json:"alt-speed-time-day"`
+	AltSpeedTimeEnabled              bool         `json:"alt-speed-time-enabled"`
+	AltSpeedTimeEnd                  int          `json:"alt-speed-time-end"`
+	AltSpeedUp                       Rate         `json:"alt-speed-up"`
+	BlocklistEnabled                 bool         `json:"blocklist-enabled"`
+	BlocklistSize                    int          `json:"blocklist-size"`
+	BlocklistURL                     string       `json:"blocklist-url"`
+	CacheSizeMB                      int          `json:"cache-size-mb"`
+	ConfigDir                        string       `json:"config-dir"`
+	DefaultTrackers                  string       `json:"default-trackers"`
+	DHTEnabled                       bool         `json:"dht-enabled"`
+	DownloadDir                      string       `json:"download-dir"`
+	DownloadDirFreeSpace             ByteSize     `json:"download-dir-free-space"`
+	DownloadQueueEnabled             bool         `json:"download-queue-enabled"`
+	DownloadQueueSize                int          `json:"download-queue-size"`
+	Encryption                       string       `json:"encryption"`
+	IdleSeedingLimit                 int          `json:"idle-seeding-limit"`
+	IdleSeedingLimitEnabled          bool         `json:"idle-seeding-limit-enabled"`
+	IncompleteDir                    string       `json:"incomplete-dir"`
+	IncompleteDirEnabled             bool         `json:"incomplete-dir-enabled"`
+	LPDEnabled                       bool         `json:"lpd-enabled"`
+	PeerLimitGlobal                  int          `json:"peer-limit-global"`
+	PeerLimitPerTorrent              int          `json:"peer-limit-per-torrent"`
+	PeerPort                         int          `json:"peer-port"`
+	PeerPortRandomOnStart            bool         `json:"peer-port-random-on-start"`
+	PEXEnabled                       bool         `json:"pex-enabled"`
+	PortForwardingEnabled            bool         `json:"port-forwarding-enabled"`
+	QueueStalledEnabled              bool         `json:"queue-stalled-enabled"`
+	QueueStalledMinutes              int          `json:"queue-stalled-minutes"`
+	RenamePartialFiles               bool         `json:"rename-partial-files"`
+	RPCVersion                       int          `json:"rpc-version"`
+	RPCVersionMinimum                int          `json:"rpc-version-minimum"`
+	RPCVersionSemver                 string       `json:"rpc-version-semver"`
+	ScriptTorrentAddedEnabled        bool         `json:"script-torrent-added-enabled"`
+	ScriptTorrentAddedFilename       string       `json:"script-torrent-added-filename"`
+	ScriptTorrentDoneEnabled         bool         `json:"script-torrent-done-enabled"`
+	ScriptTorrentDoneFilename        string       `json:"script-torrent-done-filename"`
+	ScriptTorrentDoneSeedingEnabled  bool         `json:"script-torrent-done-seeding-enabled"`
+	ScriptTorrentDoneSeedingFilename string       `json:"script-torrent-done-seeding-filename"`
+	SeedQueueEnabled                 bool         `json:"seed-queue-enabled"`
+	SeedQueueSize                    int          `json:"seed-queue-size"`
+	SeedRatioLimit                   float64      `json:"seedRatioLimit"`
+	SeedRatioLimited                 bool         `json:"seedRatioLimited"`
+	SessionID                        string       `json:"session-id"`
+	SpeedLimitDown                   Rate         `json:"speed-limit-down"`
+	SpeedLimitDownEnabled            bool         `json:"speed-limit-down-enabled"`
+	SpeedLimitUp                     Rate         `json:"speed-limit-up"`
+	SpeedLimitUpEnabled              bool         `json:"speed-limit-up-enabled"`
+	StartAddedTorrents               bool         `json:"start-added-torrents"`
+	TrashOriginalTorrentFiles        bool         `json:"trash-original-torrent-files"`
+	Units                            SessionUnits `json:"units"`
+	UTPEnabled                       bool         `json:"utp-enabled"`
+	Version                          string       `json:"version"`
+}
+
+// SessionUnits describes the units the daemon uses when formatting speeds,
+// sizes and memory.
+type SessionUnits struct {
+	SpeedUnits  []string `json:"speed-units"`
+	SpeedBytes  int      `json:"speed-bytes"`
+	SizeUnits   []string `json:"size-units"`
+	SizeBytes   int      `json:"size-bytes"`
+	MemoryUnits []string `json:"memory-units"`
+	MemoryBytes int      `json:"memory-bytes"`
+}
+
+// sessionJSON has the same fields as Session without its methods.
+type sessionJSON Session
+
+// UnmarshalJSON decodes a session, converting speed limits from KB/s.
+func (s *Session) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*sessionJSON
+		AltSpeedDown   int64 `json:"alt-speed-down"`
+		AltSpeedUp     int64 `json:"alt-speed-up"`
+		SpeedLimitDown int64 `json:"speed-limit-down"`
+		SpeedLimitUp   int64 `json:"speed-limit-up"`
+	}{sessionJSON: (*sessionJSON)(s)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	s.AltSpeedDown = Rate(aux.AltSpeedDown) * KBps
+	s.AltSpeedUp = Rate(aux.AltSpeedUp) * KBps
+	s.SpeedLimitDown = Rate(aux.SpeedLimitDown) * KBps
+	s.SpeedLimitUp = Rate(aux.SpeedLimitUp) * KBps
+	return nil
+}
+
+// MarshalJSON encodes a session in the daemon's format.
+func (s Session) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*sessionJSON
+		AltSpeedDown   int64 `json:"alt-speed-down"`
+		AltSpeedUp     int64 `json:"alt-speed-up"`
+		SpeedLimitDown int64 `json:"speed-limit-down"`
+		SpeedLimitUp   int64 `json:"speed-limit-up"`
+	}{
+		sessionJSON:    (*sessionJSON)(&s),
+		AltSpeedDown:   s.AltSpeedDown.wireKBps(),
+		AltSpeedUp:     s.AltSpeedUp.wireKBps(),
+		SpeedLimitDown: s.SpeedLimitDown.wireKBps(),
+		SpeedLimitUp:   s.SpeedLimitUp.wireKBps(),
+	})
+}
+
+// GetSession get the current session settings
+func (ac *TransmissionClient) GetSession() (Session, error) {
+	var session Session
+	err := ac.rpc("session-get", nil, &session)
+	return session, err
+}
+
+// setSession sends args as a session-set request. Only the keys present in
+// args are changed on the daemon. Obviously invalid values are rejected
+// with a *ValidationError before anything is sent.
+func (ac *TransmissionClient) setSession(args map[string]interface{}) error {
+	return ac.setSessionContext(context.Background(), args)
+}
+
+// setSessionContext is like setSession but aborts the request when ctx is
+// done.
+func (ac *TransmissionClient) setSessionContext(ctx context.Context, args map[string]interface{}) error {
+	err := validateSettings(args)
+	if err != nil {
+		return err
+	}
+	return ac.rpcContext(ctx, "session-set", args, nil)
+}
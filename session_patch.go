@@ -0,0 +1,75 @@
+package transmission
+
+import (
+	"reflect"
+	"strings"
+)
+
+// readOnlySessionKeys are reported by session-get but rejected by
+// session-set.
+var readOnlySessionKeys = map[string]bool{
+	"blocklist-size":          true,
+	"config-dir":              true,
+	"download-dir-free-space": true,
+	"rpc-version":             true,
+	"rpc-version-minimum":     true,
+	"rpc-version-semver":      true,
+	"session-id":              true,
+	"units":                   true,
+	"version":                 true,
+}
+
+// SessionPatch is a set of session arguments to change with session-set,
+// keyed by their RPC name. Arguments that aren't in the patch are left
+// untouched by the daemon.
+type SessionPatch map[string]interface{}
+
+// DiffSession returns a patch with only the settings that differ between
+// from and to. Read-only arguments are never included.
+func DiffSession(from, to Session) SessionPatch {
+	patch := SessionPatch{}
+	fromValue := reflect.ValueOf(from)
+	toValue := reflect.ValueOf(to)
+	sessionType := fromValue.Type()
+
+	for i := 0; i < sessionType.NumField(); i++ {
+		key := strings.Split(sessionType.Field(i).Tag.Get("json"), ",")[0]
+		if readOnlySessionKeys[key] {
+			continue
+		}
+		a := fromValue.Field(i).Interface()
+		b := toValue.Field(i).Interface()
+		if !reflect.DeepEqual(a, b) {
+			patch[key] = wireValue(b)
+		}
+	}
+	return patch
+}
+
+// SetSession send the patch with session-set. An empty patch is a no-op.
+func (ac *TransmissionClient) SetSession(patch SessionPatch) error {
+	if len(patch) == 0 {
+		return nil
+	}
+	return ac.setSession(patch)
+}
+
+// UpdateSession get the session, let update modify it and send back only
+// the settings that changed.
+func (ac *TransmissionClient) UpdateSession(update func(*Session)) error {
+	current, err := ac.GetSession()
+	if err != nil {
+		return err
+	}
+	updated := current
+	update(&updated)
+	return ac.SetSession(DiffSession(current, updated))
+}
+
+// wireValue converts a Session field value to its session-set form.
+func wireValue(value interface{}) interface{} {
+	if rate, ok := value.(Rate); ok {
+		return rate.wireKBps()
+	}
+	return value
+}
@@ -0,0 +1,41 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiffSession(t *testing.T) {
+	Convey("Test only changed settings are in the patch", t, func() {
+		old := Session{SpeedLimitDown: 100 * KBps, SpeedLimitDownEnabled: true, Version: "3.00"}
+		updated := old
+		updated.SpeedLimitDown = 0
+		updated.DHTEnabled = true
+		updated.Version = "4.0.5"
+
+		patch := DiffSession(old, updated)
+		So(patch, ShouldResemble, SessionPatch{
+			"speed-limit-down": int64(0),
+			"dht-enabled":      true,
+		})
+		So(DiffSession(old, old), ShouldBeEmpty)
+	})
+}
+
+func TestUpdateSession(t *testing.T) {
+	tSetup(`{"arguments":{"speed-limit-down":100,"peer-port":51413},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test updating the session through a patch", t, func() {
+		err := transmissionClient.UpdateSession(func(session *Session) {
+			So(session.PeerPort, ShouldEqual, 51413)
+			So(session.SpeedLimitDown, ShouldEqual, 100*KBps)
+			session.SpeedLimitDown = 200 * KBps
+		})
+		So(err, ShouldBeNil)
+
+		So(transmissionClient.SetSession(SessionPatch{}), ShouldBeNil)
+	})
+}
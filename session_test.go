@@ -0,0 +1,58 @@
+package transmission
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// jsonKeys returns the json names of the fields of struct type t.
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		keys[name] = true
+	}
+	return keys
+}
+
+func TestSessionFixtures(t *testing.T) {
+	known := jsonKeys(reflect.TypeOf(Session{}))
+
+	for _, version := range []string{"2.94", "3.00", "4.0"} {
+		fixture, err := ioutil.ReadFile("testdata/session-" + version + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("Test every session argument of "+version+" is covered", t, func() {
+			var raw struct {
+				Arguments map[string]json.RawMessage `json:"arguments"`
+			}
+			So(json.Unmarshal(fixture, &raw), ShouldBeNil)
+			So(raw.Arguments, ShouldNotBeEmpty)
+			for key := range raw.Arguments {
+				So(known, ShouldContainKey, key)
+			}
+		})
+
+		Convey("Test the session of "+version+" decodes", t, func() {
+			tSetup(string(fixture))
+			defer tTeardown()
+
+			session, err := transmissionClient.GetSession()
+			So(err, ShouldBeNil)
+			So(session.Version, ShouldStartWith, version)
+			So(session.PeerPort, ShouldEqual, 51413)
+			So(session.AltSpeedTimeEnd, ShouldEqual, 1020)
+			So(session.SeedRatioLimit, ShouldEqual, 2)
+			So(session.Encryption, ShouldEqual, "preferred")
+			So(session.Units.SpeedUnits, ShouldResemble, []string{"kB/s", "MB/s", "GB/s", "TB/s"})
+			So(session.Units.MemoryBytes, ShouldEqual, 1024)
+		})
+	}
+}
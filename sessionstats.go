@@ -0,0 +1,29 @@
+package transmission
+
+// TransferStats holds the transfer counters of session-stats, either since
+// the daemon started or over its whole life.
+type TransferStats struct {
+	UploadedBytes   ByteSize `json:"uploadedBytes"`
+	DownloadedBytes ByteSize `json:"downloadedBytes"`
+	FilesAdded      int      `json:"filesAdded"`
+	SessionCount    int      `json:"sessionCount"`
+	SecondsActive   int64    `json:"secondsActive"`
+}
+
+// SessionStats holds the daemon statistics returned by session-stats.
+type SessionStats struct {
+	ActiveTorrentCount int           `json:"activeTorrentCount"`
+	PausedTorrentCount int           `json:"pausedTorrentCount"`
+	TorrentCount       int           `json:"torrentCount"`
+	DownloadSpeed      Rate          `json:"downloadSpeed"`
+	UploadSpeed        Rate          `json:"uploadSpeed"`
+	CumulativeStats    TransferStats `json:"cumulative-stats"`
+	CurrentStats       TransferStats `json:"current-stats"`
+}
+
+// GetSessionStats get the statistics of the daemon
+func (ac *TransmissionClient) GetSessionStats() (SessionStats, error) {
+	var stats SessionStats
+	err := ac.rpc("session-stats", nil, &stats)
+	return stats, err
+}
@@ -0,0 +1,25 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetSessionStats(t *testing.T) {
+	tSetup(`{"arguments":{"activeTorrentCount":2,"pausedTorrentCount":1,"torrentCount":3,
+  "downloadSpeed":1500,"uploadSpeed":200,
+  "cumulative-stats":{"uploadedBytes":4096,"downloadedBytes":8192,"filesAdded":5,"sessionCount":7,"secondsActive":3600},
+  "current-stats":{"uploadedBytes":10,"downloadedBytes":20,"filesAdded":1,"sessionCount":1,"secondsActive":60}},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting the session statistics", t, func() {
+		stats, err := transmissionClient.GetSessionStats()
+		So(err, ShouldBeNil)
+		So(stats.TorrentCount, ShouldEqual, 3)
+		So(stats.DownloadSpeed, ShouldEqual, 1500*BytePerSecond)
+		So(stats.CumulativeStats.DownloadedBytes, ShouldEqual, 8*KiB)
+		So(stats.CurrentStats.SecondsActive, ShouldEqual, 60)
+	})
+}
@@ -0,0 +1,138 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SessionState is what a SessionStore keeps for a daemon between runs.
+type SessionState struct {
+	SessionID string       `json:"session-id"`
+	Version   *VersionInfo `json:"version,omitempty"`
+}
+
+// SessionStore persists the session id and version of daemons, so that a
+// short-lived process doesn't need the 409 handshake and a session-get
+// on every run. key identifies the daemon and user.
+type SessionStore interface {
+	// Load returns the saved state, or the zero state if there is none.
+	Load(key string) (SessionState, error)
+	Save(key string, state SessionState) error
+}
+
+// WithSessionStore loads the session id and version from store when the
+// client is created and saves them whenever they change. A stale session
+// id costs a single 409, after which the new one is saved. Failures of the
+// store are logged and otherwise ignored.
+func WithSessionStore(store SessionStore) Option {
+	return func(ac *ApiClient) {
+		ac.sessionStore = store
+	}
+}
+
+// sessionKey identifies the daemon and user in a SessionStore.
+func (ac *ApiClient) sessionKey() string {
+	return ac.username + "@" + ac.url
+}
+
+// loadSession restores the saved state, returning the saved version.
+func (ac *ApiClient) loadSession() *VersionInfo {
+	if ac.sessionStore == nil {
+		return nil
+	}
+	state, err := ac.sessionStore.Load(ac.sessionKey())
+	if err != nil {
+		ac.log(context.Background(), slog.LevelWarn, "loading session failed", slog.Any("error", err))
+		return nil
+	}
+	if state.SessionID != "" {
+		ac.token.replace("", state.SessionID)
+	}
+	return state.Version
+}
+
+// saveSession saves the current session id with version, which may be nil
+// when it isn't known for the session.
+func (ac *ApiClient) saveSession(version *VersionInfo) {
+	if ac.sessionStore == nil {
+		return
+	}
+	err := ac.sessionStore.Save(ac.sessionKey(), SessionState{SessionID: ac.token.peek(), Version: version})
+	if err != nil {
+		ac.log(context.Background(), slog.LevelWarn, "saving session failed", slog.Any("error", err))
+	}
+}
+
+// FileSessionStore is a SessionStore keeping the states of all daemons in
+// a single JSON file, readable only by its owner.
+type FileSessionStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileSessionStore create a store in the file at path. The file is
+// created on the first save.
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{Path: path}
+}
+
+// Load returns the state saved for key.
+func (s *FileSessionStore) Load(key string) (SessionState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.read()
+	return states[key], err
+}
+
+// Save replaces the state saved for key.
+func (s *FileSessionStore) Save(key string, state SessionState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.read()
+	if err != nil {
+		return err
+	}
+	states[key] = state
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write and rename so concurrent processes never see half a file.
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(0600)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+func (s *FileSessionStore) read() (map[string]SessionState, error) {
+	states := make(map[string]SessionState)
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return states, err
+	}
+	err = json.Unmarshal(data, &states)
+	return states, err
+}
@@ -0,0 +1,94 @@
+package transmission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFileSessionStore(t *testing.T) {
+	Convey("Test states are saved per key", t, func() {
+		path := filepath.Join(t.TempDir(), "sessions.json")
+		store := NewFileSessionStore(path)
+
+		state, err := store.Load("a")
+		So(err, ShouldBeNil)
+		So(state, ShouldResemble, SessionState{})
+
+		So(store.Save("a", SessionState{SessionID: "1", Version: &VersionInfo{RPCVersion: 17}}), ShouldBeNil)
+		So(store.Save("b", SessionState{SessionID: "2"}), ShouldBeNil)
+
+		state, err = NewFileSessionStore(path).Load("a")
+		So(err, ShouldBeNil)
+		So(state.SessionID, ShouldEqual, "1")
+		So(state.Version.RPCVersion, ShouldEqual, 17)
+
+		info, err := os.Stat(path)
+		So(err, ShouldBeNil)
+		So(info.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+	})
+
+	Convey("Test a corrupt file is an error", t, func() {
+		path := filepath.Join(t.TempDir(), "sessions.json")
+		os.WriteFile(path, []byte("{"), 0600)
+		_, err := NewFileSessionStore(path).Load("a")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestSessionStoreClient(t *testing.T) {
+	var handshakes, requests int
+	token := "current"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Transmission-Session-Id") != token {
+			handshakes++
+			w.Header().Set("X-Transmission-Session-Id", token)
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Write([]byte(`{"arguments":{"rpc-version":17,"version":"4.0.5"},"result":"success"}`))
+	}))
+	defer server.Close()
+	store := NewFileSessionStore(filepath.Join(t.TempDir(), "sessions.json"))
+
+	Convey("Test the first run saves the session", t, func() {
+		client := New(server.URL, "user", "", WithSessionStore(store))
+		version, err := client.ServerVersion()
+		So(err, ShouldBeNil)
+		So(version.Version, ShouldEqual, "4.0.5")
+		So(handshakes, ShouldEqual, 1)
+
+		state, _ := store.Load("user@" + server.URL + "/transmission/rpc")
+		So(state.SessionID, ShouldEqual, "current")
+		So(state.Version.Version, ShouldEqual, "4.0.5")
+	})
+
+	Convey("Test the next run skips the handshake and session-get", t, func() {
+		handshakes, requests = 0, 0
+		client := New(server.URL, "user", "", WithSessionStore(store))
+		_, err := client.ServerVersion()
+		So(err, ShouldBeNil)
+		So(requests, ShouldEqual, 0)
+
+		_, err = client.GetTorrents()
+		So(err, ShouldBeNil)
+		So(handshakes, ShouldEqual, 0)
+		So(requests, ShouldEqual, 1)
+	})
+
+	Convey("Test a stale session id is replaced and saved", t, func() {
+		token = "restarted"
+		client := New(server.URL, "user", "", WithSessionStore(store))
+		_, err := client.GetTorrents()
+		So(err, ShouldBeNil)
+
+		state, _ := store.Load("user@" + server.URL + "/transmission/rpc")
+		So(state.SessionID, ShouldEqual, "restarted")
+		So(state.Version, ShouldBeNil)
+	})
+}
@@ -0,0 +1,32 @@
+package transmission
+
+// SelectedSize returns the number of bytes in the files selected for
+// download. It equals TotalSize unless some files are skipped.
+func (t Torrent) SelectedSize() ByteSize {
+	return t.SizeWhenDone
+}
+
+// Remaining returns the number of bytes of the selected files that we don't
+// have yet. It is leftUntilDone, which the daemon computes from the wanted
+// pieces it has, verified or not; haveValid and haveUnchecked can't be used
+// as they include pieces of skipped files.
+func (t Torrent) Remaining() ByteSize {
+	if t.LeftUntilDone < 0 {
+		return 0
+	}
+	return t.LeftUntilDone
+}
+
+// Progress returns the fraction of the selected files we have, from 0 to 1.
+func (t Torrent) Progress() float64 {
+	if t.SizeWhenDone <= 0 {
+		return 0
+	}
+	return float64(t.SizeWhenDone-t.Remaining()) / float64(t.SizeWhenDone)
+}
+
+// Obtainable reports whether the remaining bytes can be downloaded from the
+// connected peers.
+func (t Torrent) Obtainable() bool {
+	return t.DesiredAvailable >= t.Remaining()
+}
@@ -0,0 +1,43 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentSizes(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1,"totalSize":1000,
+  "sizeWhenDone":800,"leftUntilDone":400,"haveValid":300,"haveUnchecked":100,
+  "desiredAvailable":200,"pieceCount":10,"pieceSize":100}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the size accounting helpers", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.TotalSize, ShouldEqual, 1000)
+		So(torrent.PieceCount, ShouldEqual, 10)
+		So(torrent.PieceSize, ShouldEqual, 100)
+		So(torrent.SelectedSize(), ShouldEqual, 800)
+		So(torrent.Remaining(), ShouldEqual, 400)
+		So(torrent.Progress(), ShouldEqual, 0.5)
+		So(torrent.Obtainable(), ShouldBeFalse)
+	})
+
+	Convey("Test the helpers with a skipped file whose pieces are present", t, func() {
+		// 200 of the 1000 bytes are skipped but were downloaded with the
+		// pieces they share with wanted files, so haveValid covers them.
+		torrent := Torrent{TotalSize: 1000, SizeWhenDone: 800, LeftUntilDone: 300,
+			HaveValid: 700, DesiredAvailable: 200}
+		So(torrent.Remaining(), ShouldEqual, 300)
+		So(torrent.Progress(), ShouldEqual, 0.625)
+		So(torrent.Obtainable(), ShouldBeFalse)
+	})
+
+	Convey("Test the helpers on an empty torrent", t, func() {
+		So(Torrent{}.Remaining(), ShouldEqual, 0)
+		So(Torrent{}.Progress(), ShouldEqual, 0)
+		So(Torrent{}.Obtainable(), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,40 @@
+package transmission
+
+import "context"
+
+// SetSpeedLimits set the global download and upload limits. A limit of 0
+// disables it. Limits are applied in whole KB/s, and at least 1 KB/s.
+func (ac *TransmissionClient) SetSpeedLimits(down, up Rate) error {
+	return ac.setSpeedLimits(context.Background(), down, up)
+}
+
+// setSpeedLimits is SetSpeedLimits aborting when ctx is done.
+func (ac *TransmissionClient) setSpeedLimits(ctx context.Context, down, up Rate) error {
+	return ac.setSessionContext(ctx, map[string]interface{}{
+		"speed-limit-down":         down.wireKBps(),
+		"speed-limit-down-enabled": down > 0,
+		"speed-limit-up":           up.wireKBps(),
+		"speed-limit-up-enabled":   up > 0,
+	})
+}
+
+// SetAltSpeedLimits set the alternative ("turtle mode") limits. Limits are
+// applied in whole KB/s, and at least 1 KB/s.
+func (ac *TransmissionClient) SetAltSpeedLimits(down, up Rate) error {
+	return ac.setSession(map[string]interface{}{
+		"alt-speed-down": down.wireKBps(),
+		"alt-speed-up":   up.wireKBps(),
+	})
+}
+
+// SetTorrentSpeedLimits set the download and upload limits of a torrent. A
+// limit of 0 disables it. Limits are applied in whole KB/s, and at least
+// 1 KB/s.
+func (ac *TransmissionClient) SetTorrentSpeedLimits(id int, down, up Rate) error {
+	return ac.setTorrent(id, map[string]interface{}{
+		"downloadLimit":   down.wireKBps(),
+		"downloadLimited": down > 0,
+		"uploadLimit":     up.wireKBps(),
+		"uploadLimited":   up > 0,
+	})
+}
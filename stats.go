@@ -0,0 +1,122 @@
+package transmission
+
+import (
+	"sync"
+)
+
+// Stats counts how a client has used the daemon since it was created.
+type Stats struct {
+	// Requests counts the requests sent, by RPC method.
+	Requests map[string]int64
+	// Errors counts failed requests by kind: "network" when the daemon
+	// couldn't be reached, "http" for an HTTP error status, "decode" for a
+	// malformed response, "tag" for a response to another request and
+	// "rpc" when the daemon reported a result other than success, and
+	// "circuit" for requests refused by an open CircuitBreaker.
+	Errors        map[string]int64
+	BytesSent     int64
+	BytesReceived int64
+	// SessionRefreshes counts the 409 responses that made the client fetch
+	// a new session id.
+	SessionRefreshes int64
+	// Retries counts requests resent by a ReconnectPolicy, to a failover
+	// endpoint or by AddTorrentWithRetry.
+	Retries int64
+}
+
+// clientStats collects Stats. It is shared between copies of an ApiClient,
+// and a nil *clientStats ignores everything.
+type clientStats struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+func newClientStats() *clientStats {
+	return &clientStats{stats: Stats{
+		Requests: make(map[string]int64),
+		Errors:   make(map[string]int64),
+	}}
+}
+
+func (s *clientStats) update(update func(*Stats)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	update(&s.stats)
+	s.mu.Unlock()
+}
+
+// request counts a request of size bytes.
+func (s *clientStats) request(method string, size int64) {
+	s.update(func(stats *Stats) {
+		stats.Requests[method]++
+		stats.BytesSent += size
+	})
+}
+
+// transport counts the outcome of a request at the HTTP level.
+func (s *clientStats) transport(status int, received int, err error) {
+	s.update(func(stats *Stats) {
+		stats.BytesReceived += int64(received)
+		switch err.(type) {
+		case nil:
+			if status >= 400 {
+				stats.Errors["http"]++
+			}
+		case CircuitOpenError:
+			stats.Errors["circuit"]++
+		default:
+			stats.Errors["network"]++
+		}
+	})
+}
+
+// response counts the outcome of decoding a response.
+func (s *clientStats) response(result string, err error) {
+	kind := ""
+	switch err.(type) {
+	case nil:
+		if result != "success" {
+			kind = "rpc"
+		}
+	case TagMismatchError:
+		kind = "tag"
+	default:
+		kind = "decode"
+	}
+	if kind == "" {
+		return
+	}
+	s.update(func(stats *Stats) { stats.Errors[kind]++ })
+}
+
+func (s *clientStats) refresh() {
+	s.update(func(stats *Stats) { stats.SessionRefreshes++ })
+}
+
+func (s *clientStats) retry() {
+	s.update(func(stats *Stats) { stats.Retries++ })
+}
+
+// snapshot returns a copy of the counters.
+func (s *clientStats) snapshot() Stats {
+	out := Stats{Requests: make(map[string]int64), Errors: make(map[string]int64)}
+	s.update(func(stats *Stats) {
+		out = *stats
+		out.Requests = make(map[string]int64, len(stats.Requests))
+		for k, v := range stats.Requests {
+			out.Requests[k] = v
+		}
+		out.Errors = make(map[string]int64, len(stats.Errors))
+		for k, v := range stats.Errors {
+			out.Errors[k] = v
+		}
+	})
+	return out
+}
+
+// Stats returns the client's counters.
+func (ac *TransmissionClient) Stats() Stats {
+	return ac.apiclient.stats.snapshot()
+}
@@ -0,0 +1,59 @@
+package transmission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStats(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":1}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test requests and bytes are counted", t, func() {
+		transmissionClient.GetTorrents()
+		transmissionClient.GetTorrents()
+		transmissionClient.StartTorrent(1)
+
+		stats := transmissionClient.Stats()
+		So(stats.Requests["torrent-get"], ShouldEqual, 2)
+		So(stats.Requests["torrent-start"], ShouldEqual, 1)
+		So(stats.BytesSent, ShouldBeGreaterThan, 0)
+		So(stats.BytesReceived, ShouldBeGreaterThan, 0)
+		So(stats.Errors, ShouldBeEmpty)
+
+		// The snapshot doesn't change with later requests.
+		transmissionClient.GetTorrents()
+		So(stats.Requests["torrent-get"], ShouldEqual, 2)
+	})
+}
+
+func TestStatsErrorsAndRefreshes(t *testing.T) {
+	sessions := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "fresh" {
+			sessions++
+			w.Header().Set("X-Transmission-Session-Id", "fresh")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.Write([]byte(`{"arguments":{},"result":"invalid argument"}`))
+	}))
+	defer server.Close()
+
+	Convey("Test failed results and session refreshes are counted", t, func() {
+		client := New(server.URL, "", "")
+		client.StartTorrent(1)
+		client.StartTorrent(1)
+
+		stats := client.Stats()
+		So(stats.Errors["rpc"], ShouldEqual, 2)
+		So(stats.SessionRefreshes, ShouldEqual, sessions)
+
+		server.Close()
+		client.StartTorrent(1)
+		So(client.Stats().Errors["network"], ShouldEqual, 1)
+	})
+}
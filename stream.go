@@ -0,0 +1,123 @@
+package transmission
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+// metainfoPlaceholder marks where the streamed metainfo goes in the JSON of
+// the torrent-add request. It can't occur in base64 or the other arguments'
+// JSON by accident.
+const metainfoPlaceholder = "\x00metainfo\x00"
+
+// AddTorrentStream adds the .torrent read from r. The metainfo is base64
+// encoded into the request as it is sent instead of being built in memory,
+// so adding a torrent of hundreds of megabytes takes little memory. r is
+// read from its current offset to the end, and again from that offset when
+// the request has to be retried.
+//
+// DownloadDirTemplate can't see into the stream, so {name} is empty.
+func (ac *TransmissionClient) AddTorrentStream(ctx context.Context, r io.ReadSeeker, opts AddTorrentOptions) (AddResult, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return AddResult{}, err
+	}
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return AddResult{}, err
+	}
+	if end <= start {
+		return AddResult{}, errors.New("empty metainfo")
+	}
+
+	cmd, _ := NewAddCmd()
+	err = ac.applyAddOptions(cmd, opts)
+	if err != nil {
+		return AddResult{}, err
+	}
+	cmd.Arguments.MetaInfo = metainfoPlaceholder
+	cmd.Tag = nextTag()
+	envelope, err := ac.apiclient.jsonCodec().Marshal(cmd)
+	if err != nil {
+		return AddResult{}, err
+	}
+	quoted, _ := ac.apiclient.jsonCodec().Marshal(metainfoPlaceholder)
+	prefix, suffix, found := bytes.Cut(envelope, quoted[1:len(quoted)-1])
+	if !found {
+		return AddResult{}, errors.New("metainfo placeholder missing from request")
+	}
+
+	body := requestBody{
+		method: cmd.Method,
+		size:   int64(len(prefix)) + int64(base64.StdEncoding.EncodedLen(int(end-start))) + int64(len(suffix)),
+		open: func() (io.Reader, error) {
+			_, err := r.Seek(start, io.SeekStart)
+			if err != nil {
+				return nil, err
+			}
+			return io.MultiReader(bytes.NewReader(prefix), newBase64Reader(r), bytes.NewReader(suffix)), nil
+		},
+	}
+	_, output, err := ac.apiclient.send(ctx, body)
+	if err != nil {
+		return AddResult{}, err
+	}
+
+	var out rpcResponse[torrentAddResult]
+	err = ac.apiclient.jsonCodec().Unmarshal(output, &out)
+	if err == nil {
+		err = checkTag(cmd.Tag, out.Tag)
+	}
+	ac.apiclient.response(cmd.Method, out.Result, err)
+	if err != nil {
+		return AddResult{}, err
+	}
+	err = resultError(out.Result)
+	if err != nil {
+		return AddResult{}, err
+	}
+	return out.Arguments.addResult()
+}
+
+// base64Reader encodes what it reads from src in standard base64.
+type base64Reader struct {
+	src     io.Reader
+	in      [3 * 1024]byte
+	out     [4 * 1024]byte
+	pending []byte
+	done    bool
+}
+
+func newBase64Reader(src io.Reader) *base64Reader {
+	return &base64Reader{src: src}
+}
+
+func (b *base64Reader) Read(p []byte) (int, error) {
+	if len(b.pending) == 0 {
+		if b.done {
+			return 0, io.EOF
+		}
+		// Whole multiples of three bytes encode without padding, so only
+		// the last chunk is padded.
+		n, err := io.ReadFull(b.src, b.in[:])
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			b.done = true
+		default:
+			return 0, err
+		}
+		size := base64.StdEncoding.EncodedLen(n)
+		base64.StdEncoding.Encode(b.out[:size], b.in[:n])
+		b.pending = b.out[:size]
+		if n == 0 {
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,96 @@
+package transmission
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBase64Reader(t *testing.T) {
+	Convey("Test the stream matches encoding all at once", t, func() {
+		for _, size := range []int{0, 1, 2, 3, 3071, 3072, 3073, 10000} {
+			data := bytes.Repeat([]byte("abcdefg"), size/7+1)[:size]
+			encoded, err := io.ReadAll(newBase64Reader(bytes.NewReader(data)))
+			So(err, ShouldBeNil)
+			So(string(encoded), ShouldEqual, base64.StdEncoding.EncodeToString(data))
+		}
+	})
+}
+
+func TestAddTorrentStream(t *testing.T) {
+	var (
+		requests      int
+		contentLength int64
+		request       struct {
+			Method    string `json:"method"`
+			Arguments struct {
+				MetaInfo    string `json:"metainfo"`
+				DownloadDir string `json:"download-dir"`
+				Paused      bool   `json:"paused"`
+			} `json:"arguments"`
+			Tag int `json:"tag"`
+		}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		requests++
+		contentLength = r.ContentLength
+		body, _ := io.ReadAll(r.Body)
+		if int64(len(body)) != r.ContentLength || json.Unmarshal(body, &request) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"arguments":{"torrent-added":{"id":7,"name":"big","hashString":"aaaa"}},"result":"success"}`))
+	}))
+	defer server.Close()
+
+	Convey("Test the metainfo is streamed into the request", t, func() {
+		client := New(server.URL, "", "")
+		metainfo := strings.Repeat("d4:infod4:name3:bige", 1000)
+		reader := strings.NewReader("skip" + metainfo)
+		reader.Seek(4, io.SeekStart)
+
+		added, err := client.AddTorrentStream(context.Background(), reader,
+			AddTorrentOptions{DownloadDir: "/downloads", Paused: true})
+		So(err, ShouldBeNil)
+		So(added.Torrent.ID, ShouldEqual, 7)
+		So(requests, ShouldEqual, 1)
+		So(contentLength, ShouldBeGreaterThan, len(metainfo))
+		So(request.Method, ShouldEqual, "torrent-add")
+		So(request.Arguments.DownloadDir, ShouldEqual, "/downloads")
+		So(request.Arguments.Paused, ShouldBeTrue)
+		decoded, err := base64.StdEncoding.DecodeString(request.Arguments.MetaInfo)
+		So(err, ShouldBeNil)
+		So(string(decoded), ShouldEqual, metainfo)
+	})
+
+	Convey("Test the stream is rewound when the token is refreshed", t, func() {
+		client := New(server.URL, "", "")
+		client.apiclient.token.replace("", "stale")
+		requests = 0
+
+		_, err := client.AddTorrentStream(context.Background(), strings.NewReader("d4:infode"), AddTorrentOptions{})
+		So(err, ShouldBeNil)
+		So(requests, ShouldEqual, 1)
+		decoded, _ := base64.StdEncoding.DecodeString(request.Arguments.MetaInfo)
+		So(string(decoded), ShouldEqual, "d4:infode")
+	})
+
+	Convey("Test an empty stream is rejected", t, func() {
+		client := New(server.URL, "", "")
+		_, err := client.AddTorrentStream(context.Background(), strings.NewReader(""), AddTorrentOptions{})
+		So(err, ShouldNotBeNil)
+	})
+}
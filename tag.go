@@ -0,0 +1,34 @@
+package transmission
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// lastTag is the tag of the most recent request.
+var lastTag int64
+
+// nextTag returns a tag no other request from this process has used.
+func nextTag() int {
+	return int(atomic.AddInt64(&lastTag, 1))
+}
+
+// TagMismatchError is returned when a response carries the tag of another
+// request, which usually means a proxy mixed up responses.
+type TagMismatchError struct {
+	Sent     int
+	Received int
+}
+
+func (e TagMismatchError) Error() string {
+	return fmt.Sprintf("response tag %d does not match request tag %d", e.Received, e.Sent)
+}
+
+// checkTag verifies the tag of a response. Responses without a tag are
+// accepted, as some proxies strip it.
+func checkTag(sent, received int) error {
+	if received != 0 && received != sent {
+		return TagMismatchError{Sent: sent, Received: received}
+	}
+	return nil
+}
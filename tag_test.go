@@ -0,0 +1,51 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// tTagServer answers every request with the given offset added to its tag.
+func tTagServer(offset int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Tag int `json:"tag"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"arguments":{"torrents":[]},"result":"success","tag":%d}`, req.Tag+offset)
+	}))
+}
+
+func TestRequestTags(t *testing.T) {
+	Convey("Test matching tags are accepted", t, func() {
+		server := tTagServer(0)
+		defer server.Close()
+		client := New(server.URL, "", "")
+
+		_, err := client.GetTorrents()
+		So(err, ShouldBeNil)
+		_, err = client.StartTorrent(1)
+		So(err, ShouldBeNil)
+		_, err = client.CallRaw(context.Background(), "session-stats", nil)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Test a response for another request is rejected", t, func() {
+		server := tTagServer(1)
+		defer server.Close()
+		client := New(server.URL, "", "")
+
+		_, err := client.GetTorrents()
+		So(err, ShouldHaveSameTypeAs, TagMismatchError{})
+		_, err = client.StartTorrent(1)
+		So(err, ShouldHaveSameTypeAs, TagMismatchError{})
+		_, err = client.CallRaw(context.Background(), "session-stats", nil)
+		So(err, ShouldHaveSameTypeAs, TagMismatchError{})
+	})
+}
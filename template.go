@@ -0,0 +1,86 @@
+package transmission
+
+import (
+	"encoding/base64"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// expandDownloadDir expands a download directory template. {name} is the
+// sanitized torrent name, {label} its first label, and {yyyy}, {mm} and
+// {dd} today's date.
+func expandDownloadDir(template, name string, labels []string, now time.Time) string {
+	label := ""
+	if len(labels) > 0 {
+		label = sanitizePathElement(labels[0])
+	}
+	dir := strings.NewReplacer(
+		"{name}", sanitizePathElement(name),
+		"{label}", label,
+		"{yyyy}", now.Format("2006"),
+		"{mm}", now.Format("01"),
+		"{dd}", now.Format("02"),
+	).Replace(template)
+	return path.Clean(dir)
+}
+
+// sanitizePathElement makes s safe to use as a single path element: path
+// separators and characters that are invalid on common filesystems are
+// replaced, and names made only of dots are rejected.
+func sanitizePathElement(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			return -1
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, s)
+	s = strings.TrimSpace(s)
+	if strings.Trim(s, ".") == "" {
+		return "_"
+	}
+	return s
+}
+
+// addCmdName guesses the name of the torrent cmd adds, from its metainfo,
+// the display name of a magnet link or the file name of a URL.
+func addCmdName(cmd *Command) string {
+	if cmd.Arguments.MetaInfo != "" {
+		data, err := base64.StdEncoding.DecodeString(cmd.Arguments.MetaInfo)
+		if err == nil {
+			if name := metainfoName(data); name != "" {
+				return name
+			}
+		}
+	}
+
+	source := cmd.Arguments.Filename
+	if strings.HasPrefix(source, "magnet:") {
+		u, err := url.Parse(source)
+		if err == nil {
+			return u.Query().Get("dn")
+		}
+		return ""
+	}
+	u, err := url.Parse(source)
+	if err == nil {
+		source = u.Path
+	}
+	return strings.TrimSuffix(path.Base(source), ".torrent")
+}
+
+// metainfoName returns the name in the info dictionary of a .torrent.
+func metainfoName(data []byte) string {
+	v, err := decodeBencode(data)
+	if err != nil {
+		return ""
+	}
+	meta, _ := v.(map[string]interface{})
+	info, _ := meta["info"].(map[string]interface{})
+	name, _ := info["name"].(string)
+	return name
+}
@@ -0,0 +1,59 @@
+package transmission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExpandDownloadDir(t *testing.T) {
+	now := time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC)
+
+	Convey("Test placeholders are expanded", t, func() {
+		dir := expandDownloadDir("/data/{label}/{yyyy}-{mm}-{dd}/{name}", "Show S01", []string{"tv", "hd"}, now)
+		So(dir, ShouldEqual, "/data/tv/2024-03-07/Show S01")
+
+		dir = expandDownloadDir("/data/{label}/{name}", "x", nil, now)
+		So(dir, ShouldEqual, "/data/x")
+	})
+
+	Convey("Test names can't escape the directory", t, func() {
+		So(expandDownloadDir("/data/{name}", "../../etc", nil, now), ShouldEqual, "/data/.._.._etc")
+		So(expandDownloadDir("/data/{name}", "..", nil, now), ShouldEqual, "/data/_")
+		So(expandDownloadDir("/data/{name}", "a/b\\c:d\x00", nil, now), ShouldEqual, "/data/a_b_c_d")
+		So(expandDownloadDir("/data/{label}", "x", []string{"../tv"}, now), ShouldEqual, "/data/.._tv")
+	})
+}
+
+func TestAddCmdName(t *testing.T) {
+	Convey("Test the name is taken from the source", t, func() {
+		cmd, _ := NewAddCmdByMagnet("magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193&dn=debian+12.iso")
+		So(addCmdName(cmd), ShouldEqual, "debian 12.iso")
+
+		cmd, _ = NewAddCmdByURL("https://example.com/files/debian.torrent?key=1")
+		So(addCmdName(cmd), ShouldEqual, "debian")
+
+		cmd, _ = NewAddCmd()
+		cmd.Arguments.MetaInfo = "ZDQ6aW5mb2Q0Om5hbWU1OmFsYnVtZWU=" // d4:infod4:name5:albumee
+		So(addCmdName(cmd), ShouldEqual, "album")
+	})
+}
+
+func TestAddTorrentDownloadDirTemplate(t *testing.T) {
+	tSetup(`{"arguments":{"torrent-added":{"id":1}},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the template is applied unless a directory is given", t, func() {
+		opts := AddTorrentOptions{DownloadDirTemplate: "/data/{name}"}
+		cmd, err := transmissionClient.newAddCmd(context.Background(), "magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193&dn=debian", opts)
+		So(err, ShouldBeNil)
+		So(cmd.Arguments.DownloadDir, ShouldEqual, "/data/debian")
+
+		opts.DownloadDir = "/elsewhere"
+		cmd, err = transmissionClient.newAddCmd(context.Background(), "magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193&dn=debian", opts)
+		So(err, ShouldBeNil)
+		So(cmd.Arguments.DownloadDir, ShouldEqual, "/elsewhere")
+	})
+}
@@ -0,0 +1,86 @@
+package transmission
+
+import (
+	"context"
+	"sync"
+)
+
+// sessionToken holds the X-Transmission-Session-Id shared by all requests
+// of a client. It is safe for concurrent use and shared between copies of
+// the ApiClient.
+type sessionToken struct {
+	mu sync.Mutex
+	id string
+	// fetching is closed when the fetch in progress, if any, is done.
+	fetching chan struct{}
+}
+
+// get returns the current token, calling fetch to obtain one if there is
+// none. Concurrent callers wait for a single fetch, but stop waiting when
+// ctx is done; if the fetch fails the next caller tries again.
+func (t *sessionToken) get(ctx context.Context, fetch func() (string, error)) (string, error) {
+	for {
+		t.mu.Lock()
+		if t.id != "" {
+			id := t.id
+			t.mu.Unlock()
+			return id, nil
+		}
+		wait := t.fetching
+		if wait == nil {
+			done := make(chan struct{})
+			t.fetching = done
+			t.mu.Unlock()
+			return t.fetch(fetch, done)
+		}
+		t.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-wait:
+		}
+	}
+}
+
+// fetch runs the fetch of get and wakes up the callers waiting for it.
+func (t *sessionToken) fetch(fetch func() (string, error), done chan struct{}) (string, error) {
+	id, err := fetch()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil && t.id == "" {
+		t.id = id
+	}
+	t.fetching = nil
+	close(done)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// replace swaps in the token from a 409 response, unless another request
+// already replaced the stale token it was sent with. This keeps goroutines
+// that were rejected at the same time from overwriting a newer token with
+// an older one.
+func (t *sessionToken) replace(stale, fresh string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.id == stale || t.id == "" {
+		t.id = fresh
+	}
+}
+
+// reset forgets the token so that the next request fetches a new one.
+func (t *sessionToken) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.id = ""
+}
+
+// peek returns the current token without fetching one.
+func (t *sessionToken) peek() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.id
+}
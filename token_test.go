@@ -0,0 +1,56 @@
+package transmission
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSessionTokenConcurrency(t *testing.T) {
+	Convey("Test concurrent requests fetch the token once", t, func() {
+		var fetches int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Transmission-Session-Id") != "abc" {
+				atomic.AddInt32(&fetches, 1)
+				w.Header().Set("X-Transmission-Session-Id", "abc")
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			fmt.Fprint(w, `{"arguments":{},"result":"success"}`)
+		}))
+		defer server.Close()
+
+		client := New(server.URL, "", "")
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.StartTorrent(1)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			So(err, ShouldBeNil)
+		}
+		So(atomic.LoadInt32(&fetches), ShouldEqual, 1)
+	})
+
+	Convey("Test a stale 409 doesn't overwrite a newer token", t, func() {
+		token := &sessionToken{id: "new"}
+		token.replace("old", "older")
+		So(token.id, ShouldEqual, "new")
+
+		token.replace("new", "newer")
+		So(token.id, ShouldEqual, "newer")
+	})
+}
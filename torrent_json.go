@@ -0,0 +1,78 @@
+package transmission
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// torrentJSON has the same fields as Torrent without its methods, so it can
+// be embedded to decode the bulk of a torrent without recursing.
+type torrentJSON Torrent
+
+// UnmarshalJSON decodes a torrent, converting durations and dates from the
+// whole seconds the daemon reports.
+func (t *Torrent) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*torrentJSON
+		SecondsDownloading int64 `json:"secondsDownloading"`
+		SecondsSeeding     int64 `json:"secondsSeeding"`
+		DateCreated        int64 `json:"dateCreated"`
+		ActivityDate       int64 `json:"activityDate"`
+		DoneDate           int64 `json:"doneDate"`
+		StartDate          int64 `json:"startDate"`
+		EditDate           int64 `json:"editDate"`
+	}{torrentJSON: (*torrentJSON)(t)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	t.SecondsDownloading = time.Duration(aux.SecondsDownloading) * time.Second
+	t.SecondsSeeding = time.Duration(aux.SecondsSeeding) * time.Second
+	t.DateCreated = unixTime(aux.DateCreated)
+	t.ActivityDate = unixTime(aux.ActivityDate)
+	t.DoneDate = unixTime(aux.DoneDate)
+	t.StartDate = unixTime(aux.StartDate)
+	t.EditDate = unixTime(aux.EditDate)
+	return nil
+}
+
+// MarshalJSON encodes a torrent in the daemon's format, so that it
+// round-trips through UnmarshalJSON.
+func (t Torrent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*torrentJSON
+		SecondsDownloading int64 `json:"secondsDownloading"`
+		SecondsSeeding     int64 `json:"secondsSeeding"`
+		DateCreated        int64 `json:"dateCreated"`
+		ActivityDate       int64 `json:"activityDate"`
+		DoneDate           int64 `json:"doneDate"`
+		StartDate          int64 `json:"startDate"`
+		EditDate           int64 `json:"editDate"`
+	}{
+		torrentJSON:        (*torrentJSON)(&t),
+		SecondsDownloading: int64(t.SecondsDownloading / time.Second),
+		SecondsSeeding:     int64(t.SecondsSeeding / time.Second),
+		DateCreated:        unixSeconds(t.DateCreated),
+		ActivityDate:       unixSeconds(t.ActivityDate),
+		DoneDate:           unixSeconds(t.DoneDate),
+		StartDate:          unixSeconds(t.StartDate),
+		EditDate:           unixSeconds(t.EditDate),
+	})
+}
+
+// unixTime converts a timestamp from the daemon, where 0 means unset.
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// unixSeconds is the inverse of unixTime.
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
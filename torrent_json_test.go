@@ -0,0 +1,33 @@
+package transmission
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentDurations(t *testing.T) {
+	Convey("Test durations are decoded from seconds", t, func() {
+		var torrent Torrent
+		err := json.Unmarshal([]byte(`{"id":1,"name":"Test",
+  "secondsDownloading":3600,"secondsSeeding":1209600}`), &torrent)
+		So(err, ShouldBeNil)
+		So(torrent.ID, ShouldEqual, 1)
+		So(torrent.Name, ShouldEqual, "Test")
+		So(torrent.SecondsDownloading, ShouldEqual, time.Hour)
+		So(torrent.SecondsSeeding, ShouldEqual, 14*24*time.Hour)
+	})
+
+	Convey("Test a torrent round-trips through JSON", t, func() {
+		torrent := Torrent{ID: 1, SecondsSeeding: 90 * time.Second}
+		data, err := json.Marshal(torrent)
+		So(err, ShouldBeNil)
+		So(string(data), ShouldContainSubstring, `"secondsSeeding":90`)
+
+		var decoded Torrent
+		So(json.Unmarshal(data, &decoded), ShouldBeNil)
+		So(decoded.SecondsSeeding, ShouldEqual, 90*time.Second)
+	})
+}
@@ -0,0 +1,30 @@
+package transmission
+
+// TorrentTotals sums the transfer state of a list of torrents.
+type TorrentTotals struct {
+	Count        int
+	RateDownload Rate
+	RateUpload   Rate
+	TotalSize    ByteSize
+	SizeWhenDone ByteSize
+	// LeftUntilDone is what remains to download of the wanted files.
+	LeftUntilDone ByteSize
+	// ByStatus counts the torrents per Status* constant.
+	ByStatus map[int]int
+}
+
+// Totals sums the rates and sizes of the torrents and counts them by
+// status.
+func (t Torrents) Totals() TorrentTotals {
+	totals := TorrentTotals{ByStatus: make(map[int]int)}
+	for _, torrent := range t {
+		totals.Count++
+		totals.RateDownload += torrent.RateDownload
+		totals.RateUpload += torrent.RateUpload
+		totals.TotalSize += torrent.TotalSize
+		totals.SizeWhenDone += torrent.SizeWhenDone
+		totals.LeftUntilDone += torrent.LeftUntilDone
+		totals.ByStatus[torrent.Status]++
+	}
+	return totals
+}
@@ -0,0 +1,30 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTorrentsTotals(t *testing.T) {
+	Convey("Test rates, sizes and statuses are summed", t, func() {
+		torrents := Torrents{
+			{Status: StatusDownload, RateDownload: 100 * KBps, TotalSize: 10 * MiB, SizeWhenDone: 8 * MiB, LeftUntilDone: 4 * MiB},
+			{Status: StatusDownload, RateDownload: 50 * KBps, RateUpload: 10 * KBps, TotalSize: 1 * MiB, SizeWhenDone: 1 * MiB, LeftUntilDone: 1 * MiB},
+			{Status: StatusSeed, RateUpload: 20 * KBps, TotalSize: 2 * MiB, SizeWhenDone: 2 * MiB},
+		}
+
+		totals := torrents.Totals()
+		So(totals, ShouldResemble, TorrentTotals{
+			Count:         3,
+			RateDownload:  150 * KBps,
+			RateUpload:    30 * KBps,
+			TotalSize:     13 * MiB,
+			SizeWhenDone:  11 * MiB,
+			LeftUntilDone: 5 * MiB,
+			ByStatus:      map[int]int{StatusDownload: 2, StatusSeed: 1},
+		})
+
+		So(Torrents{}.Totals().Count, ShouldEqual, 0)
+	})
+}
@@ -0,0 +1,121 @@
+package transmission
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ParseTrackerTiers split a tracker list in the daemon's text format (one
+// announce URL per line, tiers separated by a blank line) into tiers.
+func ParseTrackerTiers(list string) [][]string {
+	var tiers [][]string
+	var tier []string
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(tier) > 0 {
+				tiers = append(tiers, tier)
+				tier = nil
+			}
+			continue
+		}
+		tier = append(tier, line)
+	}
+	if len(tier) > 0 {
+		tiers = append(tiers, tier)
+	}
+	return tiers
+}
+
+// FormatTrackerTiers is the inverse of ParseTrackerTiers.
+func FormatTrackerTiers(tiers [][]string) string {
+	parts := make([]string, 0, len(tiers))
+	for _, tier := range tiers {
+		if len(tier) == 0 {
+			continue
+		}
+		parts = append(parts, strings.Join(tier, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// GetDefaultTrackers get the trackers the daemon appends to public torrents.
+// Requires RPC version 17 (Transmission 4.0).
+func (ac *TransmissionClient) GetDefaultTrackers() ([][]string, error) {
+	err := ac.requireRPCVersion("default-trackers", 17)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		DefaultTrackers string `json:"default-trackers"`
+	}
+	err = ac.rpc("session-get", map[string]interface{}{
+		"fields": []string{"default-trackers"},
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTrackerTiers(out.DefaultTrackers), nil
+}
+
+// SetDefaultTrackers set the trackers the daemon appends to public torrents.
+// Requires RPC version 17 (Transmission 4.0).
+func (ac *TransmissionClient) SetDefaultTrackers(tiers [][]string) error {
+	err := ac.requireRPCVersion("default-trackers", 17)
+	if err != nil {
+		return err
+	}
+	return ac.setSession(map[string]interface{}{
+		"default-trackers": FormatTrackerTiers(tiers),
+	})
+}
+
+// SetTorrentTrackers replace the trackers of the torrent with the given
+// tiers. Requires RPC version 17 (Transmission 4.0).
+func (ac *TransmissionClient) SetTorrentTrackers(id int, tiers [][]string) error {
+	err := ac.requireRPCVersion("trackerList", 17)
+	if err != nil {
+		return err
+	}
+	return ac.setTorrent(id, map[string]interface{}{
+		"trackerList": FormatTrackerTiers(tiers),
+	})
+}
+
+// Trackers returns the torrent's trackerList split into tiers.
+func (t Torrent) Trackers() [][]string {
+	return ParseTrackerTiers(t.TrackerList)
+}
+
+// TrackerIdentity reduces an announce URL or host name to the tracker it
+// belongs to, so URLs differing only in scheme, port, path, passkey or
+// subdomain compare equal. "https://tracker.example.com:443/a/announce?passkey=x"
+// and "example.com" both become "example.com". Without a public suffix
+// list the registrable domain is guessed: two-letter country domains with
+// a short second level, like co.uk, keep three labels. IP addresses are
+// returned as they are.
+func TrackerIdentity(announce string) string {
+	raw := strings.TrimSpace(announce)
+	if !strings.Contains(raw, "://") {
+		raw = "//" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return strings.ToLower(strings.TrimSpace(announce))
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	labels := strings.Split(host, ".")
+	keep := 2
+	if n := len(labels); n >= 3 && len(labels[n-1]) == 2 && len(labels[n-2]) <= 3 {
+		keep = 3
+	}
+	if len(labels) <= keep {
+		return host
+	}
+	return strings.Join(labels[len(labels)-keep:], ".")
+}
@@ -0,0 +1,80 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTrackerTiers(t *testing.T) {
+	Convey("Test parsing and formatting tracker tiers", t, func() {
+		list := "udp://a.example.com:6969\nudp://b.example.com:6969\n\n\nhttp://c.example.com/announce\n"
+		tiers := ParseTrackerTiers(list)
+		So(tiers, ShouldResemble, [][]string{
+			{"udp://a.example.com:6969", "udp://b.example.com:6969"},
+			{"http://c.example.com/announce"},
+		})
+		So(FormatTrackerTiers(tiers), ShouldEqual,
+			"udp://a.example.com:6969\nudp://b.example.com:6969\n\nhttp://c.example.com/announce")
+		So(ParseTrackerTiers(""), ShouldBeEmpty)
+	})
+}
+
+func TestDefaultTrackers(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":17,
+  "default-trackers":"udp://a.example.com:6969\n\nudp://b.example.com:6969"},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting and setting default trackers", t, func() {
+		tiers, err := transmissionClient.GetDefaultTrackers()
+		So(err, ShouldBeNil)
+		So(len(tiers), ShouldEqual, 2)
+		So(tiers[1][0], ShouldEqual, "udp://b.example.com:6969")
+
+		So(transmissionClient.SetDefaultTrackers(tiers), ShouldBeNil)
+	})
+}
+
+func TestTorrentTrackers(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":17,"torrents":[{"id":1,
+  "trackerList":"udp://a.example.com:6969\n\nudp://b.example.com:6969"}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test reading and replacing a torrent's trackers", t, func() {
+		torrent, err := transmissionClient.GetTorrent(1)
+		So(err, ShouldBeNil)
+		So(torrent.Trackers(), ShouldResemble, [][]string{
+			{"udp://a.example.com:6969"},
+			{"udp://b.example.com:6969"},
+		})
+
+		So(transmissionClient.SetTorrentTrackers(1, torrent.Trackers()), ShouldBeNil)
+	})
+}
+
+func TestTrackerIdentity(t *testing.T) {
+	Convey("Test announce URLs are reduced to their tracker", t, func() {
+		for announce, identity := range map[string]string{
+			"https://tracker.example.com:443/announce?passkey=x": "example.com",
+			"http://Example.COM/abcdef/announce":                 "example.com",
+			"example.com":                                        "example.com",
+			"udp://tracker.opentrackr.org:1337/announce":         "opentrackr.org",
+			"https://t.bbc.co.uk/announce":                       "bbc.co.uk",
+			"http://192.168.1.10:8080/announce":                  "192.168.1.10",
+			"http://[::1]:6969/announce":                         "::1",
+			"localhost:6969":                                     "localhost",
+		} {
+			So(TrackerIdentity(announce), ShouldEqual, identity)
+		}
+	})
+
+	Convey("Test rules match trackers by identity", t, func() {
+		torrent := Torrent{PercentDone: 1, TrackerStats: []TrackerStat{
+			{Announce: "https://tracker.example.com:443/announce?passkey=x"}}}
+		So(RemovalRule{Tracker: "example.com"}.Matches(torrent), ShouldBeTrue)
+		So(RemovalRule{Tracker: "https://www.example.com/announce"}.Matches(torrent), ShouldBeTrue)
+		So(RemovalRule{Tracker: "example.org"}.Matches(torrent), ShouldBeFalse)
+	})
+}
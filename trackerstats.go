@@ -0,0 +1,84 @@
+package transmission
+
+import "sort"
+
+// TrackerSummary aggregates the torrents of one tracker, as grouped by
+// TrackerIdentity.
+type TrackerSummary struct {
+	Tracker  string
+	Torrents int
+	// Seeding counts the torrents that are seeding or queued to seed.
+	Seeding    int
+	Uploaded   ByteSize
+	Downloaded ByteSize
+	// Seeders and Leechers are summed from the latest scrapes.
+	Seeders  int
+	Leechers int
+	// Announces counts the announce URLs that have announced, and
+	// FailedAnnounces the ones whose last announce failed.
+	Announces       int
+	FailedAnnounces int
+}
+
+// Ratio is the combined upload ratio of the tracker's torrents, or -1 if
+// nothing was downloaded from it.
+func (s TrackerSummary) Ratio() float64 {
+	if s.Downloaded == 0 {
+		return -1
+	}
+	return float64(s.Uploaded) / float64(s.Downloaded)
+}
+
+// ErrorRate is the share of announce URLs whose last announce failed.
+func (s TrackerSummary) ErrorRate() float64 {
+	if s.Announces == 0 {
+		return 0
+	}
+	return float64(s.FailedAnnounces) / float64(s.Announces)
+}
+
+// TrackerReport summarizes the torrents per tracker, sorted by tracker.
+// trackerStats, uploadedEver and downloadedEver must have been requested.
+func (t Torrents) TrackerReport() []TrackerSummary {
+	summaries := map[string]*TrackerSummary{}
+	for _, torrent := range t {
+		counted := map[string]bool{}
+		for _, stat := range torrent.TrackerStats {
+			tracker := trackerHost(stat)
+			summary := summaries[tracker]
+			if summary == nil {
+				summary = &TrackerSummary{Tracker: tracker}
+				summaries[tracker] = summary
+			}
+			if stat.HasAnnounced {
+				summary.Announces++
+				if !stat.LastAnnounceSucceeded {
+					summary.FailedAnnounces++
+				}
+			}
+			if counted[tracker] {
+				continue
+			}
+			counted[tracker] = true
+			summary.Torrents++
+			if torrent.Status == StatusSeed || torrent.Status == StatisSeedWait {
+				summary.Seeding++
+			}
+			summary.Uploaded += torrent.UploadedEver
+			summary.Downloaded += torrent.DownloadedEver
+			if stat.SeederCount > 0 {
+				summary.Seeders += stat.SeederCount
+			}
+			if stat.LeecherCount > 0 {
+				summary.Leechers += stat.LeecherCount
+			}
+		}
+	}
+
+	report := make([]TrackerSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		report = append(report, *summary)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Tracker < report[j].Tracker })
+	return report
+}
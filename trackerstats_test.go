@@ -0,0 +1,47 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTrackerReport(t *testing.T) {
+	Convey("Test torrents are summarized per tracker", t, func() {
+		torrents := Torrents{
+			{Status: StatusSeed, UploadedEver: 300, DownloadedEver: 100, TrackerStats: []TrackerStat{
+				{Announce: "https://tracker.example.com/announce?passkey=a", HasAnnounced: true,
+					LastAnnounceSucceeded: true, SeederCount: 10, LeecherCount: 2},
+				{Announce: "https://backup.example.com/announce", HasAnnounced: true},
+			}},
+			{Status: StatusDownload, UploadedEver: 0, DownloadedEver: 100, TrackerStats: []TrackerStat{
+				{Announce: "https://tracker.example.com/announce?passkey=a", HasAnnounced: true,
+					LastAnnounceSucceeded: true, SeederCount: 5, LeecherCount: -1},
+			}},
+			{Status: StatusSeed, TrackerStats: []TrackerStat{
+				{Announce: "udp://open.example.org:6969"},
+			}},
+		}
+
+		report := torrents.TrackerReport()
+		So(report, ShouldHaveLength, 2)
+
+		com := report[0]
+		So(com.Tracker, ShouldEqual, "example.com")
+		So(com.Torrents, ShouldEqual, 2)
+		So(com.Seeding, ShouldEqual, 1)
+		So(com.Uploaded, ShouldEqual, 300)
+		So(com.Downloaded, ShouldEqual, 200)
+		So(com.Ratio(), ShouldEqual, 1.5)
+		So(com.Seeders, ShouldEqual, 15)
+		So(com.Leechers, ShouldEqual, 2)
+		So(com.Announces, ShouldEqual, 3)
+		So(com.FailedAnnounces, ShouldEqual, 1)
+		So(com.ErrorRate(), ShouldAlmostEqual, 1.0/3)
+
+		org := report[1]
+		So(org.Tracker, ShouldEqual, "example.org")
+		So(org.Ratio(), ShouldEqual, -1)
+		So(org.ErrorRate(), ShouldEqual, 0)
+	})
+}
@@ -1,11 +1,15 @@
 package transmission
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"net/http"
 	"sort"
+	"strings"
+	"time"
 )
 
 const (
@@ -27,19 +31,27 @@ type Command struct {
 	Method    string    `json:"method,omitempty"`
 	Arguments arguments `json:"arguments,omitempty"`
 	Result    string    `json:"result,omitempty"`
+	Tag       int       `json:"tag,omitempty"`
 }
 
 type arguments struct {
-	Fields       []string     `json:"fields,omitempty"`
-	Torrents     Torrents     `json:"torrents,omitempty"`
-	Ids          []int        `json:"ids,omitempty"`
-	DeleteData   bool         `json:"delete-local-data,omitempty"`
-	DownloadDir  string       `json:"download-dir,omitempty"`
-	MetaInfo     string       `json:"metainfo,omitempty"`
-	Filename     string       `json:"filename,omitempty"`
-	TorrentAdded TorrentAdded `json:"torrent-added"`
-	Paused       bool         `json:"paused,omitempty"`
-	Location     string       `json:"location,omitempty"`
+	Fields       []string      `json:"fields,omitempty"`
+	Torrents     Torrents      `json:"torrents,omitempty"`
+	Ids          TorrentIDs    `json:"ids,omitempty"`
+	DeleteData   bool          `json:"delete-local-data,omitempty"`
+	DownloadDir  string        `json:"download-dir,omitempty"`
+	MetaInfo     string        `json:"metainfo,omitempty"`
+	Filename     string        `json:"filename,omitempty"`
+	TorrentAdded *TorrentAdded `json:"torrent-added,omitempty"`
+	Paused       bool          `json:"paused,omitempty"`
+	Location     string        `json:"location,omitempty"`
+	Cookies      string        `json:"cookies,omitempty"`
+	Labels       []string      `json:"labels,omitempty"`
+	Move         bool          `json:"move,omitempty"`
+
+	// TorrentDuplicate is returned instead of TorrentAdded when the
+	// daemon already has the torrent.
+	TorrentDuplicate *TorrentAdded `json:"torrent-duplicate,omitempty"`
 }
 
 //TrackerStat struct for tracker stats.
@@ -74,31 +86,69 @@ type TrackerStat struct {
 
 //File struct for tracker stats.
 type File struct {
-	BytesCompleted int64
-	Length         int64
+	BytesCompleted ByteSize
+	Length         ByteSize
 	Name           string
 }
 
 //Torrent struct for torrents
 type Torrent struct {
-	ID            int           `json:"id"`
-	Name          string        `json:"name"`
-	Status        int           `json:"status"`
-	AddedDate     int           `json:"addedDate"`
-	LeftUntilDone int64         `json:"leftUntilDone"`
-	Eta           int           `json:"eta"`
-	UploadRatio   float64       `json:"uploadRatio"`
-	RateDownload  int           `json:"rateDownload"`
-	RateUpload    int           `json:"rateUpload"`
-	DownloadDir   string        `json:"downloadDir"`
-	IsFinished    bool          `json:"isFinished"`
-	PercentDone   float64       `json:"percentDone"`
-	SeedRatioMode int           `json:"seedRatioMode"`
-	HashString    string        `json:"hashString"`
-	Error         int           `json:"error"`
-	ErrorString   string        `json:"errorString"`
-	TrackerStats  []TrackerStat `json:"trackerStats"`
-	Files         []File        `json:"files"`
+	ID                      int           `json:"id"`
+	Name                    string        `json:"name"`
+	Status                  int           `json:"status"`
+	AddedDate               int           `json:"addedDate"`
+	LeftUntilDone           ByteSize      `json:"leftUntilDone"`
+	Eta                     int           `json:"eta"`
+	UploadRatio             float64       `json:"uploadRatio"`
+	RateDownload            Rate          `json:"rateDownload"`
+	RateUpload              Rate          `json:"rateUpload"`
+	DownloadDir             string        `json:"downloadDir"`
+	IsFinished              bool          `json:"isFinished"`
+	PercentDone             float64       `json:"percentDone"`
+	SeedRatioMode           int           `json:"seedRatioMode"`
+	HashString              string        `json:"hashString"`
+	Error                   int           `json:"error"`
+	ErrorString             string        `json:"errorString"`
+	TrackerStats            []TrackerStat `json:"trackerStats"`
+	Files                   []File        `json:"files"`
+	Stalled                 bool          `json:"isStalled"`
+	Availability            []int         `json:"availability"`
+	FileCount               int           `json:"file-count"`
+	PrimaryMimeType         string        `json:"primary-mime-type"`
+	PercentComplete         float64       `json:"percentComplete"`
+	Group                   string        `json:"group"`
+	SequentialDownload      bool          `json:"sequential_download"`
+	Webseeds                []string      `json:"webseeds"`
+	WebseedsSendingToUs     int           `json:"webseedsSendingToUs"`
+	EtaIdle                 int           `json:"etaIdle"`
+	ManualAnnounceTime      int64         `json:"manualAnnounceTime"`
+	MagnetLink              string        `json:"magnetLink"`
+	TorrentFile             string        `json:"torrentFile"`
+	DownloadedEver          ByteSize      `json:"downloadedEver"`
+	UploadedEver            ByteSize      `json:"uploadedEver"`
+	CorruptEver             ByteSize      `json:"corruptEver"`
+	SecondsDownloading      time.Duration `json:"secondsDownloading"`
+	SecondsSeeding          time.Duration `json:"secondsSeeding"`
+	Creator                 string        `json:"creator"`
+	Comment                 string        `json:"comment"`
+	DateCreated             time.Time     `json:"dateCreated"`
+	IsPrivate               bool          `json:"isPrivate"`
+	TotalSize               ByteSize      `json:"totalSize"`
+	SizeWhenDone            ByteSize      `json:"sizeWhenDone"`
+	HaveValid               ByteSize      `json:"haveValid"`
+	HaveUnchecked           ByteSize      `json:"haveUnchecked"`
+	DesiredAvailable        ByteSize      `json:"desiredAvailable"`
+	PieceCount              int           `json:"pieceCount"`
+	PieceSize               ByteSize      `json:"pieceSize"`
+	QueuePosition           int           `json:"queuePosition"`
+	ActivityDate            time.Time     `json:"activityDate"`
+	DoneDate                time.Time     `json:"doneDate"`
+	StartDate               time.Time     `json:"startDate"`
+	EditDate                time.Time     `json:"editDate"`
+	RecheckProgress         float64       `json:"recheckProgress"`
+	MetadataPercentComplete float64       `json:"metadataPercentComplete"`
+	Labels                  []string      `json:"labels"`
+	TrackerList             string        `json:"trackerList"`
 }
 
 // Torrents represent []Torrent
@@ -155,95 +205,158 @@ type TorrentAdded struct {
 	Name       string `json:"name"`
 }
 
+// AddResult is the outcome of adding a torrent.
+type AddResult struct {
+	Torrent TorrentAdded
+	// Duplicate is set when the daemon already had the torrent, in which
+	// case nothing was added.
+	Duplicate bool
+}
+
+// addResult returns the torrent of the response.
+func (a torrentAddResult) addResult() (AddResult, error) {
+	switch {
+	case a.TorrentDuplicate != nil:
+		return AddResult{Torrent: *a.TorrentDuplicate, Duplicate: true}, nil
+	case a.TorrentAdded != nil:
+		return AddResult{Torrent: *a.TorrentAdded}, nil
+	}
+	return AddResult{}, errors.New("torrent-add response has no torrent")
+}
+
 //New create new transmission torrent
-func New(url string, username string, password string) TransmissionClient {
-	apiclient := NewClient(url, username, password)
-	tc := TransmissionClient{apiclient: apiclient}
-	return tc
+func New(url string, username string, password string, opts ...Option) TransmissionClient {
+	apiclient := NewClient(url, username, password, opts...)
+	return TransmissionClient{apiclient: apiclient}
 }
 
 //GetTorrents get a list of torrents
 func (ac *TransmissionClient) GetTorrents() (Torrents, error) {
-	cmd, err := NewGetTorrentsCmd()
-
-	out, err := ac.ExecuteCommand(cmd)
-	if err != nil {
-		return nil, err
-	}
+	return ac.fetchTorrents(context.Background(), torrentGetFields, nil)
+}
 
-	return out.Arguments.Torrents, nil
+// fetchTorrents gets fields of the torrents with ids, or of all torrents
+// if ids is nil.
+func (ac *TransmissionClient) fetchTorrents(ctx context.Context, fields []string, ids TorrentIDs) (Torrents, error) {
+	result, err := invoke[torrentGetResult](ctx, ac, "torrent-get",
+		torrentGetArgs{Fields: fields, Ids: ids})
+	return result.Torrents, err
 }
 
 //GetTorrent get a torrent by its ID
 func (ac *TransmissionClient) GetTorrent(id int) (Torrent, error) {
-	cmd, err := NewGetTorrentsCmd()
+	return ac.getTorrent(IDs(id))
+}
+
+func (ac *TransmissionClient) getTorrent(ids TorrentIDs) (Torrent, error) {
+	return ac.fetchTorrent(context.Background(), torrentGetFields, ids)
+}
 
-	cmd.Arguments.Ids = []int{id}
+// ErrTorrentNotFound is returned when the daemon has no torrent with the
+// id or hash asked for.
+var ErrTorrentNotFound = errors.New("no results found")
 
-	out, err := ac.ExecuteCommand(cmd)
+// fetchTorrent is fetchTorrents for exactly one torrent.
+func (ac *TransmissionClient) fetchTorrent(ctx context.Context, fields []string, ids TorrentIDs) (Torrent, error) {
+	torrents, err := ac.fetchTorrents(ctx, fields, ids)
 	if err != nil {
 		return Torrent{}, err
 	}
 
-	if len(out.Arguments.Torrents) != 1 {
-		return Torrent{}, errors.New("no results found")
+	switch len(torrents) {
+	case 0:
+		return Torrent{}, ErrTorrentNotFound
+	case 1:
+	default:
+		return Torrent{}, errors.New("more than one torrent found")
 	}
 
-	return out.Arguments.Torrents[0], nil
+	return torrents[0], nil
 }
 
 //StartTorrent start the torrent
 func (ac *TransmissionClient) StartTorrent(id int) (string, error) {
-	return ac.sendSimpleCommand("torrent-start", id)
+	return ac.sendSimpleCommand("torrent-start", IDs(id))
 }
 
 //StopTorrent start the torrent
 func (ac *TransmissionClient) StopTorrent(id int) (string, error) {
-	return ac.sendSimpleCommand("torrent-stop", id)
+	return ac.sendSimpleCommand("torrent-stop", IDs(id))
 }
 
 //VerifyTorrent verify the torrent
 func (ac *TransmissionClient) VerifyTorrent(id int) (string, error) {
-	return ac.sendSimpleCommand("torrent-verify", id)
+	return ac.sendSimpleCommand("torrent-verify", IDs(id))
 }
 
+// torrentGetFields are the fields requested for torrents.
+var torrentGetFields = []string{"id", "name", "hashString",
+	"status", "addedDate", "leftUntilDone", "eta", "uploadRatio",
+	"rateDownload", "rateUpload", "downloadDir", "isFinished",
+	"percentDone", "seedRatioMode", "error", "errorString",
+	"trackerStats", "files", "file-count", "primary-mime-type",
+	"percentComplete", "group", "sequential_download", "isStalled",
+	"webseeds", "webseedsSendingToUs", "etaIdle", "manualAnnounceTime",
+	"torrentFile", "downloadedEver", "uploadedEver", "corruptEver",
+	"secondsDownloading", "secondsSeeding", "totalSize", "sizeWhenDone",
+	"haveValid", "haveUnchecked", "desiredAvailable", "pieceCount",
+	"pieceSize", "queuePosition", "activityDate", "doneDate", "startDate",
+	"editDate", "recheckProgress", "metadataPercentComplete", "labels",
+	"trackerList"}
+
+// NewGetTorrentsCmd returns a torrent-get command with the default fields.
+//
+// Deprecated: Use TorrentGet, which validates the request.
 func NewGetTorrentsCmd() (*Command, error) {
 	cmd := &Command{}
 
 	cmd.Method = "torrent-get"
-	cmd.Arguments.Fields = []string{"id", "name", "hashString",
-		"status", "addedDate", "leftUntilDone", "eta", "uploadRatio",
-		"rateDownload", "rateUpload", "downloadDir", "isFinished",
-		"percentDone", "seedRatioMode", "error", "errorString",
-		"trackerStats", "files"}
+	cmd.Arguments.Fields = append([]string(nil), torrentGetFields...)
 
 	return cmd, nil
 }
 
+// NewAddCmd returns an empty torrent-add command.
+//
+// Deprecated: Use TorrentAdd, which validates the request.
 func NewAddCmd() (*Command, error) {
 	cmd := &Command{}
 	cmd.Method = "torrent-add"
 	return cmd, nil
 }
 
+// NewAddCmdByMagnet returns a torrent-add command for a magnet link.
+//
+// Deprecated: Use TorrentAdd().Magnet.
 func NewAddCmdByMagnet(magnetLink string) (*Command, error) {
 	cmd, _ := NewAddCmd()
 	cmd.Arguments.Filename = magnetLink
 	return cmd, nil
 }
 
+// NewAddCmdByURL returns a torrent-add command for an http(s) URL.
+//
+// Deprecated: Use TorrentAdd().URL.
 func NewAddCmdByURL(url string) (*Command, error) {
 	cmd, _ := NewAddCmd()
 	cmd.Arguments.Filename = url
 	return cmd, nil
 }
 
+// NewAddCmdByFilename returns a torrent-add command for a .torrent file on
+// the daemon's host.
+//
+// Deprecated: Use TorrentAdd().Filename.
 func NewAddCmdByFilename(filename string) (*Command, error) {
 	cmd, _ := NewAddCmd()
 	cmd.Arguments.Filename = filename
 	return cmd, nil
 }
 
+// NewAddCmdByFile returns a torrent-add command uploading a local .torrent
+// file.
+//
+// Deprecated: Use TorrentAdd().MetaInfo with the content of the file.
 func NewAddCmdByFile(file string) (*Command, error) {
 	cmd, _ := NewAddCmd()
 
@@ -261,46 +374,76 @@ func (cmd *Command) SetDownloadDir(dir string) {
 	cmd.Arguments.DownloadDir = dir
 }
 
+// SetCookies set the cookies the daemon sends when fetching a torrent URL
+func (cmd *Command) SetCookies(cookies []*http.Cookie) {
+	parts := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		parts = append(parts, cookie.Name+"="+cookie.Value)
+	}
+	cmd.Arguments.Cookies = strings.Join(parts, "; ")
+}
+
+// AddFields request additional fields besides the default ones
+func (cmd *Command) AddFields(fields ...string) {
+	cmd.Arguments.Fields = append(cmd.Arguments.Fields, fields...)
+}
+
 func NewSetCmd(id int) (*Command, error) {
 	cmd := &Command{}
 	cmd.Method = "torrent-set"
-	cmd.Arguments.Ids = []int{id}
+	cmd.Arguments.Ids = IDs(id)
+	return cmd, nil
+}
+
+func NewSetLocationCmd(id int, location string, move bool) (*Command, error) {
+	cmd := &Command{}
+	cmd.Method = "torrent-set-location"
+	cmd.Arguments.Ids = IDs(id)
+	cmd.Arguments.Location = location
+	cmd.Arguments.Move = move
 	return cmd, nil
 }
 
 func NewDelCmd(id int, removeFile bool) (*Command, error) {
 	cmd := &Command{}
 	cmd.Method = "torrent-remove"
-	cmd.Arguments.Ids = []int{id}
+	cmd.Arguments.Ids = IDs(id)
 	cmd.Arguments.DeleteData = removeFile
 	return cmd, nil
 }
 
 func (ac *TransmissionClient) ExecuteCommand(cmd *Command) (*Command, error) {
 	out := &Command{}
+	err := ac.execute(cmd, out)
+	return out, err
+}
 
-	body, err := json.Marshal(cmd)
+// execute sends cmd and decodes the response into out.
+func (ac *TransmissionClient) execute(cmd *Command, out *Command) error {
+	tagged := *cmd
+	tagged.Tag = nextTag()
+	body, err := ac.apiclient.jsonCodec().Marshal(tagged)
 	if err != nil {
-		return out, err
+		return err
 	}
-	output, err := ac.apiclient.Post(string(body))
+	_, output, err := ac.apiclient.post(context.Background(), tagged.Method, body)
 	if err != nil {
-		return out, err
+		return err
 	}
-	err = json.Unmarshal(output, &out)
-	if err != nil {
-		return out, err
+	err = ac.apiclient.jsonCodec().Unmarshal(output, out)
+	if err == nil {
+		err = checkTag(tagged.Tag, out.Tag)
 	}
-
-	return out, nil
+	ac.apiclient.response(cmd.Method, out.Result, err)
+	return err
 }
 
-func (ac *TransmissionClient) ExecuteAddCommand(addCmd *Command) (TorrentAdded, error) {
-	outCmd, err := ac.ExecuteCommand(addCmd)
+func (ac *TransmissionClient) ExecuteAddCommand(addCmd *Command) (AddResult, error) {
+	result, err := invoke[torrentAddResult](context.Background(), ac, addCmd.Method, addCmd.Arguments)
 	if err != nil {
-		return TorrentAdded{}, err
+		return AddResult{}, err
 	}
-	return outCmd.Arguments.TorrentAdded, nil
+	return result.addResult()
 }
 
 func encodeFile(file string) (string, error) {
@@ -312,25 +455,83 @@ func encodeFile(file string) (string, error) {
 	return base64.StdEncoding.EncodeToString(fileData), nil
 }
 
-func (ac *TransmissionClient) sendSimpleCommand(method string, id int) (result string, err error) {
-	cmd := Command{Method: method}
-	cmd.Arguments.Ids = []int{id}
-	resp, err := ac.sendCommand(cmd)
-	return resp.Result, err
+func (ac *TransmissionClient) sendSimpleCommand(method string, ids TorrentIDs) (result string, err error) {
+	return ac.sendSimpleCommandContext(context.Background(), method, ids)
 }
 
-func (ac *TransmissionClient) sendCommand(cmd Command) (response Command, err error) {
-	body, err := json.Marshal(cmd)
-	if err != nil {
-		return
+// sendSimpleCommandContext is like sendSimpleCommand but aborts the request
+// when ctx is done.
+func (ac *TransmissionClient) sendSimpleCommandContext(ctx context.Context, method string, ids TorrentIDs) (result string, err error) {
+	response, err := roundTrip[struct{}](ctx, ac, method, idsArgs{Ids: ids})
+	return response.Result, err
+}
+
+// resultError turns a result other than "success" into an error.
+func resultError(result string) error {
+	if result != "success" {
+		return errors.New(result)
 	}
-	output, err := ac.apiclient.Post(string(body))
+	return nil
+}
+
+// rpcRequest is the envelope for methods that don't fit the Command
+// arguments struct.
+type rpcRequest struct {
+	Method    string      `json:"method"`
+	Arguments interface{} `json:"arguments,omitempty"`
+	Tag       int         `json:"tag,omitempty"`
+}
+
+// rpc sends method with args and decodes the response arguments into out.
+// A result other than "success" is returned as an error.
+func (ac *TransmissionClient) rpc(method string, args interface{}, out interface{}) error {
+	return ac.rpcContext(context.Background(), method, args, out)
+}
+
+// rpcContext is like rpc but aborts the request when ctx is done.
+func (ac *TransmissionClient) rpcContext(ctx context.Context, method string, args interface{}, out interface{}) error {
+	arguments, err := ac.CallRaw(ctx, method, args)
 	if err != nil {
-		return
+		return err
+	}
+	if out == nil || len(arguments) == 0 {
+		return nil
 	}
-	err = json.Unmarshal(output, &response)
+	return ac.apiclient.jsonCodec().Unmarshal(arguments, out)
+}
+
+// Call sends an arbitrary RPC request with args and decodes the arguments
+// of the response into out, which may be nil to discard them.
+func (ac *TransmissionClient) Call(ctx context.Context, method string, args map[string]interface{}, out interface{}) error {
+	if args == nil {
+		return ac.rpcContext(ctx, method, nil, out)
+	}
+	return ac.rpcContext(ctx, method, args, out)
+}
+
+// CallRaw sends an arbitrary RPC request and returns the arguments of the
+// response undecoded. It is meant for methods and arguments the typed API
+// doesn't cover yet. args is marshalled as is and may be nil.
+func (ac *TransmissionClient) CallRaw(ctx context.Context, method string, args interface{}) (json.RawMessage, error) {
+	return invoke[json.RawMessage](ctx, ac, method, args)
+}
+
+// setTorrent sends args as a torrent-set request for the torrent with id.
+// Obviously invalid values are rejected with a *ValidationError.
+func (ac *TransmissionClient) setTorrent(id int, args map[string]interface{}) error {
+	return ac.setTorrents(IDs(id), args)
+}
+
+// setTorrents is like setTorrent for several torrents at once.
+func (ac *TransmissionClient) setTorrents(ids TorrentIDs, args map[string]interface{}) error {
+	return ac.setTorrentsContext(context.Background(), ids, args)
+}
+
+func (ac *TransmissionClient) setTorrentsContext(ctx context.Context, ids TorrentIDs, args map[string]interface{}) error {
+	err := validateSettings(args)
 	if err != nil {
-		return
+		return err
 	}
-	return response, nil
+	args["ids"] = ids
+	return ac.rpcContext(ctx, "torrent-set", args, nil)
 }
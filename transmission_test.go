@@ -1,6 +1,8 @@
 package transmission
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -77,8 +79,8 @@ func TestAddTorrentByFilename(t *testing.T) {
 		result, err := transmissionClient.ExecuteAddCommand(addCmd)
 		So(err, ShouldBeNil)
 
-		So(result.Name, ShouldEqual, "Test Name")
-		So(result.ID, ShouldEqual, 23)
+		So(result.Torrent.Name, ShouldEqual, "Test Name")
+		So(result.Torrent.ID, ShouldEqual, 23)
 	})
 }
 
@@ -96,7 +98,153 @@ func TestAddTorrentByMagnet(t *testing.T) {
 		result, err := transmissionClient.ExecuteAddCommand(addCmd)
 		So(err, ShouldBeNil)
 
-		So(result.Name, ShouldEqual, "CentOS 7.0 x64")
-		So(result.ID, ShouldEqual, 23)
+		So(result.Torrent.Name, ShouldEqual, "CentOS 7.0 x64")
+		So(result.Torrent.ID, ShouldEqual, 23)
+	})
+}
+
+func TestAddTorrentDuplicate(t *testing.T) {
+	tSetup(`{"arguments":{"torrent-duplicate":
+  {"hashString":"875a2d90068c32b4ce7992eaf56cd03f5be0d193",
+  "id":23,"name":"CentOS 7.0 x64"}}
+  ,"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test adding a torrent the daemon already has", t, func() {
+		addCmd, err := NewAddCmdByMagnet("magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193")
+		So(err, ShouldBeNil)
+
+		result, err := transmissionClient.ExecuteAddCommand(addCmd)
+		So(err, ShouldBeNil)
+		So(result.Torrent.ID, ShouldEqual, 23)
+		So(result.Duplicate, ShouldBeTrue)
+	})
+
+	Convey("Test requests don't carry torrent-duplicate", t, func() {
+		addCmd, _ := NewAddCmdByMagnet("magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193")
+		body, err := json.Marshal(addCmd)
+		So(err, ShouldBeNil)
+		So(string(body), ShouldNotContainSubstring, "torrent-duplicate")
+		So(string(body), ShouldNotContainSubstring, "torrent-added")
+	})
+}
+
+func TestAddTorrentMissing(t *testing.T) {
+	tSetup(`{"arguments":{},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test a torrent-add response without a torrent fails", t, func() {
+		addCmd, _ := NewAddCmdByMagnet("magnet:?xt=urn:btih:875a2d90068c32b4ce7992eaf56cd03f5be0d193")
+		_, err := transmissionClient.ExecuteAddCommand(addCmd)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestAddTorrentFailed(t *testing.T) {
+	tSetup(`{"arguments":{},"result":"invalid or corrupt torrent file"}`)
+	defer tTeardown()
+
+	Convey("Test a failed torrent-add returns the result as error", t, func() {
+		addCmd, _ := NewAddCmdByURL("http://example.com/broken.torrent")
+		_, err := transmissionClient.ExecuteAddCommand(addCmd)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "invalid or corrupt torrent file")
+	})
+}
+
+func TestGetTorrentSummaryFields(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":5,"name":"Test",
+  "file-count":3,"primary-mime-type":"video/x-matroska",
+  "percentComplete":0.5}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the summary fields are decoded", t, func() {
+		torrent, err := transmissionClient.GetTorrent(5)
+		So(err, ShouldBeNil)
+		So(torrent.FileCount, ShouldEqual, 3)
+		So(torrent.PrimaryMimeType, ShouldEqual, "video/x-matroska")
+		So(torrent.PercentComplete, ShouldEqual, 0.5)
+	})
+}
+
+func TestGetTorrentWebseeds(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":5,
+  "webseeds":["https://mirror.example.com/debian.iso"],
+  "webseedsSendingToUs":1}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the webseed fields are decoded", t, func() {
+		torrent, err := transmissionClient.GetTorrent(5)
+		So(err, ShouldBeNil)
+		So(torrent.Webseeds, ShouldResemble, []string{"https://mirror.example.com/debian.iso"})
+		So(torrent.WebseedsSendingToUs, ShouldEqual, 1)
+	})
+}
+
+func TestGetTorrentFile(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":5,
+  "torrentFile":"/var/lib/transmission/torrents/875a2d90068c32b4.torrent"}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the torrent file path is decoded", t, func() {
+		torrent, err := transmissionClient.GetTorrent(5)
+		So(err, ShouldBeNil)
+		So(torrent.TorrentFile, ShouldEqual, "/var/lib/transmission/torrents/875a2d90068c32b4.torrent")
+	})
+}
+
+func TestGetTorrentProgressFields(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[{"id":5,"status":2,
+  "recheckProgress":0.25,"metadataPercentComplete":1}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the verification and metadata progress are decoded", t, func() {
+		torrent, err := transmissionClient.GetTorrent(5)
+		So(err, ShouldBeNil)
+		So(torrent.RecheckProgress, ShouldEqual, 0.25)
+		So(torrent.MetadataPercentComplete, ShouldEqual, 1)
+	})
+}
+
+func TestCallRaw(t *testing.T) {
+	tSetup(`{"arguments":{"path":"/downloads","size-bytes":1024},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the raw response arguments are returned", t, func() {
+		raw, err := transmissionClient.CallRaw(context.Background(), "free-space",
+			map[string]interface{}{"path": "/downloads"})
+		So(err, ShouldBeNil)
+		So(string(raw), ShouldEqual, `{"path":"/downloads","size-bytes":1024}`)
+	})
+}
+
+func TestCallRawFailure(t *testing.T) {
+	tSetup(`{"arguments":{},"result":"method name not recognized"}`)
+	defer tTeardown()
+
+	Convey("Test a failed result is returned as an error", t, func() {
+		_, err := transmissionClient.CallRaw(context.Background(), "no-such-method", nil)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "method name not recognized")
+	})
+}
+
+func TestCall(t *testing.T) {
+	tSetup(`{"arguments":{"path":"/downloads","size-bytes":1024},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the response arguments are decoded into out", t, func() {
+		var out struct {
+			Path      string `json:"path"`
+			SizeBytes int64  `json:"size-bytes"`
+		}
+		err := transmissionClient.Call(context.Background(), "free-space",
+			map[string]interface{}{"path": "/downloads"}, &out)
+		So(err, ShouldBeNil)
+		So(out.Path, ShouldEqual, "/downloads")
+		So(out.SizeBytes, ShouldEqual, 1024)
+
+		So(transmissionClient.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
 	})
 }
@@ -0,0 +1,91 @@
+package transmission
+
+import (
+	"net/http"
+	"time"
+)
+
+// TransportOptions tunes the connections of a client. Zero values keep the
+// settings of http.DefaultTransport.
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept to the
+	// daemon. Go's default of 2 makes concurrent pollers open and close a
+	// connection for nearly every request.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer. Set it below the
+	// idle timeout of a proxy in front of the daemon to avoid reusing
+	// connections the proxy already closed.
+	IdleConnTimeout time.Duration
+	// DisableKeepAlives uses a new connection for every request, for
+	// devices that run out of sockets holding connections open.
+	DisableKeepAlives bool
+	// HTTP selects the HTTP versions used. Defaults to HTTPAuto.
+	HTTP HTTPMode
+}
+
+// HTTPMode selects the HTTP versions a client speaks.
+type HTTPMode int
+
+const (
+	// HTTPAuto negotiates HTTP/2 over TLS and uses HTTP/1.1 otherwise,
+	// like http.DefaultTransport.
+	HTTPAuto HTTPMode = iota
+	// HTTP1Only never uses HTTP/2, for reverse proxies that misbehave
+	// with it.
+	HTTP1Only
+	// HTTP2Only requires HTTP/2 over TLS.
+	HTTP2Only
+	// HTTP2Cleartext speaks HTTP/2 without TLS (h2c) to http:// URLs and
+	// HTTP/2 over TLS to https:// URLs. The server must accept h2c
+	// connections without an upgrade.
+	HTTP2Cleartext
+)
+
+// WithTransportOptions tunes the connections of the client. The options
+// survive the transport resets of WithReconnect.
+func WithTransportOptions(opts TransportOptions) Option {
+	return func(ac *ApiClient) {
+		ac.transportOpts = &opts
+		ac.conn.mu.Lock()
+		defer ac.conn.mu.Unlock()
+		ac.conn.client.Transport = ac.newTransport()
+	}
+}
+
+// newTransport returns a fresh transport with the client's options.
+func (ac *ApiClient) newTransport() http.RoundTripper {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return http.DefaultTransport
+	}
+	transport := base.Clone()
+	opts := ac.transportOpts
+	if opts == nil {
+		return transport
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+		if transport.MaxIdleConns != 0 && transport.MaxIdleConns < opts.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = opts.MaxIdleConnsPerHost
+		}
+	}
+	if opts.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	transport.DisableKeepAlives = opts.DisableKeepAlives
+
+	protocols := new(http.Protocols)
+	switch opts.HTTP {
+	case HTTP1Only:
+		protocols.SetHTTP1(true)
+	case HTTP2Only:
+		protocols.SetHTTP2(true)
+	case HTTP2Cleartext:
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		protocols = nil
+	}
+	transport.Protocols = protocols
+	return transport
+}
@@ -0,0 +1,92 @@
+package transmission
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransportOptions(t *testing.T) {
+	Convey("Test the options are applied to the transport", t, func() {
+		client := NewClient("http://localhost:9091", "", "", WithTransportOptions(TransportOptions{
+			MaxIdleConnsPerHost: 16,
+			IdleConnTimeout:     5 * time.Second,
+			DisableKeepAlives:   true,
+		}))
+		transport, ok := client.conn.client.Transport.(*http.Transport)
+		So(ok, ShouldBeTrue)
+		So(transport.MaxIdleConnsPerHost, ShouldEqual, 16)
+		So(transport.IdleConnTimeout, ShouldEqual, 5*time.Second)
+		So(transport.DisableKeepAlives, ShouldBeTrue)
+		So(transport != http.DefaultTransport, ShouldBeTrue)
+	})
+
+	Convey("Test the options survive a transport reset", t, func() {
+		client := NewClient("http://localhost:9091", "", "",
+			WithTransportOptions(TransportOptions{MaxIdleConnsPerHost: 8}))
+		before := client.conn.client.Transport
+		client.resetTransport()
+		So(client.conn.client.Transport, ShouldNotEqual, before)
+		So(client.conn.client.Transport.(*http.Transport).MaxIdleConnsPerHost, ShouldEqual, 8)
+	})
+
+	Convey("Test zero options keep the defaults", t, func() {
+		client := NewClient("http://localhost:9091", "", "", WithTransportOptions(TransportOptions{}))
+		transport := client.conn.client.Transport.(*http.Transport)
+		defaults := http.DefaultTransport.(*http.Transport)
+		So(transport.MaxIdleConnsPerHost, ShouldEqual, defaults.MaxIdleConnsPerHost)
+		So(transport.IdleConnTimeout, ShouldEqual, defaults.IdleConnTimeout)
+	})
+}
+
+// tProtoServer records the HTTP version of the requests it answers.
+func tProtoServer(protos *[]int) *httptest.Server {
+	return httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*protos = append(*protos, r.ProtoMajor)
+		w.Header().Set("X-Transmission-Session-Id", "token")
+		w.Write([]byte(`{"arguments":{},"result":"success"}`))
+	}))
+}
+
+func TestHTTPModes(t *testing.T) {
+	Convey("Test forcing HTTP/1.1 against a server offering HTTP/2", t, func() {
+		var protos []int
+		server := tProtoServer(&protos)
+		server.EnableHTTP2 = true
+		server.StartTLS()
+		defer server.Close()
+
+		client := New(server.URL, "", "", WithTransportOptions(TransportOptions{HTTP: HTTP1Only}))
+		client.apiclient.conn.client.Transport.(*http.Transport).TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+		So(client.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(protos, ShouldNotBeEmpty)
+		So(protos[len(protos)-1], ShouldEqual, 1)
+	})
+
+	Convey("Test speaking h2c to a server without TLS", t, func() {
+		var protos []int
+		server := tProtoServer(&protos)
+		server.Config.Protocols = new(http.Protocols)
+		server.Config.Protocols.SetHTTP1(true)
+		server.Config.Protocols.SetUnencryptedHTTP2(true)
+		server.Start()
+		defer server.Close()
+
+		client := New(server.URL, "", "", WithTransportOptions(TransportOptions{HTTP: HTTP2Cleartext}))
+		So(client.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(protos[len(protos)-1], ShouldEqual, 2)
+
+		plain := New(server.URL, "", "")
+		So(plain.Call(context.Background(), "session-stats", nil, nil), ShouldBeNil)
+		So(protos[len(protos)-1], ShouldEqual, 1)
+	})
+
+	Convey("Test the default mode leaves the protocols alone", t, func() {
+		client := NewClient("http://localhost:9091", "", "", WithTransportOptions(TransportOptions{}))
+		So(client.conn.client.Transport.(*http.Transport).Protocols, ShouldBeNil)
+	})
+}
@@ -0,0 +1,93 @@
+package transmission
+
+import "fmt"
+
+// Rate is a transfer rate in bytes per second. The daemon reports torrent
+// rates in B/s but takes speed limits in KB/s; both are converted to Rate
+// so callers never have to track which is which.
+type Rate int64
+
+// Rate units, using the daemon's decimal kilobyte.
+const (
+	BytePerSecond Rate = 1
+	KBps               = 1000 * BytePerSecond
+	MBps               = 1000 * KBps
+	GBps               = 1000 * MBps
+)
+
+// KBps returns the rate in kilobytes per second.
+func (r Rate) KBps() float64 {
+	return float64(r) / float64(KBps)
+}
+
+// MBps returns the rate in megabytes per second.
+func (r Rate) MBps() float64 {
+	return float64(r) / float64(MBps)
+}
+
+// wireKBps returns the rate in the whole KB/s the daemon takes for speed
+// limits. Positive rates below 1 KB/s become 1 KB/s rather than 0, which
+// the daemon takes as a limit that stops the transfer.
+func (r Rate) wireKBps() int64 {
+	if r > 0 && r < KBps {
+		return 1
+	}
+	return int64(r / KBps)
+}
+
+func (r Rate) String() string {
+	switch {
+	case r >= GBps || r <= -GBps:
+		return fmt.Sprintf("%.2f GB/s", float64(r)/float64(GBps))
+	case r >= MBps || r <= -MBps:
+		return fmt.Sprintf("%.2f MB/s", r.MBps())
+	case r >= KBps || r <= -KBps:
+		return fmt.Sprintf("%.1f kB/s", r.KBps())
+	}
+	return fmt.Sprintf("%d B/s", int64(r))
+}
+
+// ByteSize is a size in bytes.
+type ByteSize int64
+
+// ByteSize units.
+const (
+	Byte ByteSize = 1
+	KiB           = 1024 * Byte
+	MiB           = 1024 * KiB
+	GiB           = 1024 * MiB
+	TiB           = 1024 * GiB
+)
+
+// KiB returns the size in kibibytes.
+func (s ByteSize) KiB() float64 {
+	return float64(s) / float64(KiB)
+}
+
+// MiB returns the size in mebibytes.
+func (s ByteSize) MiB() float64 {
+	return float64(s) / float64(MiB)
+}
+
+// GiB returns the size in gibibytes.
+func (s ByteSize) GiB() float64 {
+	return float64(s) / float64(GiB)
+}
+
+func (s ByteSize) String() string {
+	abs := s
+	if abs < 0 {
+		abs = -abs
+	}
+	switch {
+	case abs >= TiB:
+		return fmt.Sprintf("%.2f TiB", float64(s)/float64(TiB))
+	case abs >= GiB:
+		return fmt.Sprintf("%.2f GiB", s.GiB())
+	case abs >= MiB:
+		return fmt.Sprintf("%.1f MiB", s.MiB())
+	case abs >= KiB:
+		return fmt.Sprintf("%.1f KiB", s.KiB())
+	}
+	return fmt.Sprintf("%d B", int64(s))
+}
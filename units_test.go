@@ -0,0 +1,78 @@
+package transmission
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRate(t *testing.T) {
+	Convey("Test rate conversions and formatting", t, func() {
+		So(Rate(1500).KBps(), ShouldEqual, 1.5)
+		So((2 * MBps).MBps(), ShouldEqual, 2)
+		So((1500 * BytePerSecond).wireKBps(), ShouldEqual, 1)
+		So(Rate(500).wireKBps(), ShouldEqual, 1)
+		So(Rate(0).wireKBps(), ShouldEqual, 0)
+		So(Rate(512).String(), ShouldEqual, "512 B/s")
+		So(Rate(1500).String(), ShouldEqual, "1.5 kB/s")
+		So((2500 * KBps).String(), ShouldEqual, "2.50 MB/s")
+	})
+}
+
+func TestByteSize(t *testing.T) {
+	Convey("Test size conversions and formatting", t, func() {
+		So((512 * MiB).GiB(), ShouldEqual, 0.5)
+		So(ByteSize(100).String(), ShouldEqual, "100 B")
+		So((1536 * KiB).String(), ShouldEqual, "1.5 MiB")
+		So((3 * GiB).String(), ShouldEqual, "3.00 GiB")
+		So((-2 * KiB).String(), ShouldEqual, "-2.0 KiB")
+	})
+}
+
+func TestSessionSpeeds(t *testing.T) {
+	tSetup(`{"arguments":{"speed-limit-down":100,"speed-limit-up":50,
+  "alt-speed-down":20,"alt-speed-up":10,"download-dir-free-space":1073741824},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test session speed limits are converted from KB/s", t, func() {
+		session, err := transmissionClient.GetSession()
+		So(err, ShouldBeNil)
+		So(session.SpeedLimitDown, ShouldEqual, 100*KBps)
+		So(session.SpeedLimitUp, ShouldEqual, 50*KBps)
+		So(session.AltSpeedDown, ShouldEqual, 20*KBps)
+		So(session.DownloadDirFreeSpace, ShouldEqual, GiB)
+
+		So(transmissionClient.SetSpeedLimits(MBps, 0), ShouldBeNil)
+		So(transmissionClient.SetAltSpeedLimits(100*KBps, 50*KBps), ShouldBeNil)
+		So(transmissionClient.SetTorrentSpeedLimits(1, 0, 10*KBps), ShouldBeNil)
+	})
+}
+
+func TestSubKBpsLimits(t *testing.T) {
+	var request string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Transmission-Session-Id") != "token" {
+			w.Header().Set("X-Transmission-Session-Id", "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		request = string(body)
+		w.Write([]byte(`{"arguments":{},"result":"success"}`))
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	Convey("Test a limit below 1 KB/s doesn't stop the transfer", t, func() {
+		So(client.SetSpeedLimits(Rate(500), 0), ShouldBeNil)
+		So(request, ShouldContainSubstring, `"speed-limit-down":1`)
+		So(request, ShouldContainSubstring, `"speed-limit-down-enabled":true`)
+
+		So(client.SetTorrentSpeedLimits(1, 0, Rate(500)), ShouldBeNil)
+		So(request, ShouldContainSubstring, `"uploadLimit":1`)
+	})
+}
@@ -0,0 +1,92 @@
+package transmission
+
+import "sort"
+
+// DiskUsage totals the sizes of a set of torrents.
+type DiskUsage struct {
+	Torrents int
+	// SizeWhenDone is the space the torrents take once complete.
+	SizeWhenDone ByteSize
+	// HaveValid is the verified data already on disk.
+	HaveValid ByteSize
+}
+
+func (u *DiskUsage) add(t Torrent) {
+	u.Torrents++
+	u.SizeWhenDone += t.SizeWhenDone
+	u.HaveValid += t.HaveValid
+}
+
+// DirUsage is the usage of a download directory.
+type DirUsage struct {
+	Dir string
+	DiskUsage
+	// Free is the free space of the directory's filesystem.
+	Free ByteSize
+	// Pending is the space the torrents still need to complete.
+	Pending ByteSize
+}
+
+// LabelUsage is the usage of the torrents with a label.
+type LabelUsage struct {
+	Label string
+	DiskUsage
+}
+
+// DiskReport is the result of DiskUsageReport.
+type DiskReport struct {
+	Total DiskUsage
+	// Dirs is sorted by directory.
+	Dirs []DirUsage
+	// Labels is sorted by label. A torrent is counted for each of its
+	// labels, and under Unlabeled if it has none.
+	Labels    []LabelUsage
+	Unlabeled DiskUsage
+}
+
+// DiskUsageReport totals the torrents by download directory and by label,
+// along with the free space of each directory.
+func (ac *TransmissionClient) DiskUsageReport() (DiskReport, error) {
+	torrents, err := ac.GetTorrents()
+	if err != nil {
+		return DiskReport{}, err
+	}
+
+	var report DiskReport
+	dirs := map[string]*DirUsage{}
+	labels := map[string]*LabelUsage{}
+	for _, torrent := range torrents {
+		report.Total.add(torrent)
+
+		dir := cleanDir(torrent.DownloadDir)
+		if dirs[dir] == nil {
+			dirs[dir] = &DirUsage{Dir: dir}
+		}
+		dirs[dir].add(torrent)
+		dirs[dir].Pending += torrent.LeftUntilDone
+
+		if len(torrent.Labels) == 0 {
+			report.Unlabeled.add(torrent)
+		}
+		for _, label := range torrent.Labels {
+			if labels[label] == nil {
+				labels[label] = &LabelUsage{Label: label}
+			}
+			labels[label].add(torrent)
+		}
+	}
+
+	for _, usage := range dirs {
+		usage.Free, err = ac.FreeSpace(usage.Dir)
+		if err != nil {
+			return DiskReport{}, err
+		}
+		report.Dirs = append(report.Dirs, *usage)
+	}
+	sort.Slice(report.Dirs, func(i, j int) bool { return report.Dirs[i].Dir < report.Dirs[j].Dir })
+	for _, usage := range labels {
+		report.Labels = append(report.Labels, *usage)
+	}
+	sort.Slice(report.Labels, func(i, j int) bool { return report.Labels[i].Label < report.Labels[j].Label })
+	return report, nil
+}
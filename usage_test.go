@@ -0,0 +1,32 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiskUsageReport(t *testing.T) {
+	tSetup(`{"arguments":{"size-bytes":1000,"torrents":[
+  {"id":1,"downloadDir":"/data/tv/","labels":["tv","hd"],"sizeWhenDone":100,"haveValid":100,"leftUntilDone":0},
+  {"id":2,"downloadDir":"/data/tv","labels":["tv"],"sizeWhenDone":50,"haveValid":20,"leftUntilDone":30},
+  {"id":3,"downloadDir":"/data/misc","labels":[],"sizeWhenDone":10,"haveValid":0,"leftUntilDone":10}]},
+  "result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test usage is grouped by directory and label", t, func() {
+		report, err := transmissionClient.DiskUsageReport()
+		So(err, ShouldBeNil)
+		So(report.Total, ShouldResemble, DiskUsage{Torrents: 3, SizeWhenDone: 160, HaveValid: 120})
+
+		So(report.Dirs, ShouldResemble, []DirUsage{
+			{Dir: "/data/misc", DiskUsage: DiskUsage{1, 10, 0}, Free: 1000, Pending: 10},
+			{Dir: "/data/tv", DiskUsage: DiskUsage{2, 150, 120}, Free: 1000, Pending: 30},
+		})
+		So(report.Labels, ShouldResemble, []LabelUsage{
+			{Label: "hd", DiskUsage: DiskUsage{1, 100, 100}},
+			{Label: "tv", DiskUsage: DiskUsage{2, 150, 120}},
+		})
+		So(report.Unlabeled, ShouldResemble, DiskUsage{1, 10, 0})
+	})
+}
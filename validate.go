@@ -0,0 +1,93 @@
+package transmission
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValidationError is returned when a setting is rejected before it is sent
+// to the daemon.
+type ValidationError struct {
+	Key    string
+	Value  interface{}
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid value %v for %s: %s", e.Value, e.Key, e.Reason)
+}
+
+// nonNegativeKeys are session-set and torrent-set arguments that must not
+// be negative.
+var nonNegativeKeys = []string{
+	"alt-speed-down", "alt-speed-up", "speed-limit-down", "speed-limit-up",
+	"cache-size-mb", "download-queue-size", "seed-queue-size",
+	"idle-seeding-limit", "queue-stalled-minutes", "peer-limit-global",
+	"peer-limit-per-torrent", "seedRatioLimit", "downloadLimit",
+	"uploadLimit", "peer-limit", "seedIdleLimit", "queuePosition",
+}
+
+// validEncryption are the values accepted for the encryption setting.
+var validEncryption = map[string]bool{
+	"required":  true,
+	"preferred": true,
+	"tolerated": true,
+}
+
+// validateSettings checks the session-set or torrent-set arguments for
+// values the daemon would reject.
+func validateSettings(args map[string]interface{}) error {
+	for _, key := range nonNegativeKeys {
+		value, ok := args[key]
+		if !ok {
+			continue
+		}
+		if n, ok := toFloat(value); ok && n < 0 {
+			return &ValidationError{key, value, "must not be negative"}
+		}
+	}
+
+	if value, ok := args["peer-port"]; ok {
+		if n, ok := toFloat(value); ok && (n < 1 || n > 65535) {
+			return &ValidationError{"peer-port", value, "must be between 1 and 65535"}
+		}
+	}
+
+	for _, key := range []string{"alt-speed-time-begin", "alt-speed-time-end"} {
+		value, ok := args[key]
+		if !ok {
+			continue
+		}
+		if n, ok := toFloat(value); ok && (n < 0 || n >= 24*60) {
+			return &ValidationError{key, value, "must be minutes after midnight (0-1439)"}
+		}
+	}
+
+	if value, ok := args["alt-speed-time-day"]; ok {
+		if n, ok := toFloat(value); ok && (n < 0 || n > 127) {
+			return &ValidationError{"alt-speed-time-day", value, "must be a bitmask of days (0-127)"}
+		}
+	}
+
+	if value, ok := args["encryption"]; ok {
+		if s, ok := value.(string); !ok || !validEncryption[s] {
+			return &ValidationError{"encryption", value, `must be "required", "preferred" or "tolerated"`}
+		}
+	}
+
+	return nil
+}
+
+// toFloat converts any numeric value to a float64.
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,54 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateSettings(t *testing.T) {
+	Convey("Test valid settings pass", t, func() {
+		So(validateSettings(map[string]interface{}{
+			"speed-limit-down":     100,
+			"peer-port":            51413,
+			"alt-speed-time-begin": 540,
+			"alt-speed-time-end":   1020,
+			"alt-speed-time-day":   127,
+			"encryption":           "required",
+			"seedRatioLimit":       1.5,
+		}), ShouldBeNil)
+	})
+
+	Convey("Test invalid settings are rejected", t, func() {
+		for _, args := range []map[string]interface{}{
+			{"speed-limit-up": -1},
+			{"downloadLimit": int64(-5)},
+			{"peer-port": 0},
+			{"peer-port": 65536},
+			{"alt-speed-time-begin": 1440},
+			{"alt-speed-time-end": -1},
+			{"alt-speed-time-day": 128},
+			{"encryption": "always"},
+			{"encryption": 1},
+		} {
+			err := validateSettings(args)
+			So(err, ShouldNotBeNil)
+			_, ok := err.(*ValidationError)
+			So(ok, ShouldBeTrue)
+		}
+	})
+}
+
+func TestSetSessionValidation(t *testing.T) {
+	tSetup(`{"arguments":{},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test invalid settings never reach the daemon", t, func() {
+		err := transmissionClient.SetPeerPort(70000, false)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "invalid value 70000 for peer-port: must be between 1 and 65535")
+
+		err = transmissionClient.SetQueuePosition(1, -1)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,122 @@
+package transmission
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Verifier verifies torrents in rolling batches to catch data that rotted
+// on disk, without ever having more than MaxChecking torrents checking at
+// once. The daemon doesn't record when a torrent was last verified, so
+// the Verifier keeps track in LastVerified.
+type Verifier struct {
+	// MaxChecking is the most torrents checking or waiting to be checked
+	// at once. Defaults to 1.
+	MaxChecking int
+	// MinAge skips torrents verified more recently. Defaults to 30 days.
+	MinAge time.Duration
+	// Interval between steps in Run. Defaults to ten minutes.
+	Interval time.Duration
+	// LastVerified maps hash strings to when the Verifier last started
+	// verifying the torrent. Fill it from and save it to storage to keep
+	// the schedule across restarts.
+	LastVerified map[string]time.Time
+	// OnVerify is called for every torrent the Verifier starts verifying.
+	OnVerify func(Torrent)
+	// OnError is called when a step fails in Run.
+	OnError func(error)
+
+	client *TransmissionClient
+	now    func() time.Time
+}
+
+// NewVerifier create a verifier acting through client
+func NewVerifier(client *TransmissionClient) *Verifier {
+	return &Verifier{
+		MaxChecking:  1,
+		MinAge:       30 * 24 * time.Hour,
+		Interval:     10 * time.Minute,
+		LastVerified: make(map[string]time.Time),
+		client:       client,
+		now:          time.Now,
+	}
+}
+
+// Step starts verifying as many torrents as there are free slots, the
+// ones verified longest ago first, and returns their IDs.
+func (v *Verifier) Step() ([]int, error) {
+	return v.StepContext(context.Background())
+}
+
+// StepContext is like Step but aborts the requests when ctx is done.
+func (v *Verifier) StepContext(ctx context.Context) ([]int, error) {
+	torrents, err := v.client.fetchTorrents(ctx, torrentGetFields, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	maxChecking := v.MaxChecking
+	if maxChecking <= 0 {
+		maxChecking = 1
+	}
+	now := v.now()
+	var candidates Torrents
+	for _, torrent := range torrents {
+		if torrent.Status == StatusCheck || torrent.Status == StatusWait {
+			maxChecking--
+			continue
+		}
+		if torrent.HashString == "" || torrent.HaveValid == 0 {
+			continue
+		}
+		last, ok := v.LastVerified[torrent.HashString]
+		if ok && now.Sub(last) < v.MinAge {
+			continue
+		}
+		candidates = append(candidates, torrent)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return v.LastVerified[candidates[i].HashString].Before(v.LastVerified[candidates[j].HashString])
+	})
+
+	var started []int
+	for _, torrent := range candidates {
+		if len(started) >= maxChecking {
+			break
+		}
+		_, err = v.client.sendSimpleCommandContext(ctx, "torrent-verify", IDs(torrent.ID))
+		if err != nil {
+			return started, err
+		}
+		v.LastVerified[torrent.HashString] = now
+		started = append(started, torrent.ID)
+		if v.OnVerify != nil {
+			v.OnVerify(torrent)
+		}
+	}
+	return started, nil
+}
+
+// Run steps every interval until ctx is done.
+func (v *Verifier) Run(ctx context.Context) error {
+	interval := v.Interval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := v.StepContext(ctx)
+		if err != nil && v.OnError != nil {
+			v.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
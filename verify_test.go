@@ -0,0 +1,50 @@
+package transmission
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestVerifierStep(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"hashString":"aaaa","status":6,"haveValid":100},
+  {"id":2,"hashString":"bbbb","status":6,"haveValid":100},
+  {"id":3,"hashString":"cccc","status":0,"haveValid":100},
+  {"id":4,"hashString":"dddd","status":4,"haveValid":0},
+  {"id":5,"hashString":"eeee","status":2,"haveValid":100}]},"result":"success"}`)
+	defer tTeardown()
+
+	now := time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC)
+
+	Convey("Test batches respect the checking limit and the minimum age", t, func() {
+		v := NewVerifier(&transmissionClient)
+		v.now = func() time.Time { return now }
+		v.MaxChecking = 3
+		v.LastVerified["aaaa"] = now.Add(-40 * 24 * time.Hour)
+		v.LastVerified["bbbb"] = now.Add(-24 * time.Hour)
+
+		// Torrent 5 is already checking, which leaves two slots. Torrent 3
+		// was never verified and goes first, torrent 2 is too recent and
+		// torrent 4 has no data yet.
+		started, err := v.Step()
+		So(err, ShouldBeNil)
+		So(started, ShouldResemble, []int{3, 1})
+		So(v.LastVerified["cccc"], ShouldResemble, now)
+
+		started, err = v.Step()
+		So(err, ShouldBeNil)
+		So(started, ShouldBeEmpty)
+	})
+
+	Convey("Test Run returns while the daemon hangs", t, func() {
+		server, release := tHungDaemon(func(w http.ResponseWriter, r *http.Request) {})
+		defer server.Close()
+		defer close(release)
+		client := New(server.URL, "", "")
+		So(tRunFor(NewVerifier(&client).Run, 50*time.Millisecond), ShouldEqual, context.DeadlineExceeded)
+	})
+}
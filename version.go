@@ -0,0 +1,101 @@
+package transmission
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ErrUnsupportedRPCVersion is returned by methods that need a newer RPC
+// version than the daemon speaks.
+type ErrUnsupportedRPCVersion struct {
+	Feature  string
+	Required int
+	Actual   int
+}
+
+func (e ErrUnsupportedRPCVersion) Error() string {
+	return fmt.Sprintf("%s requires RPC version %d, daemon has %d",
+		e.Feature, e.Required, e.Actual)
+}
+
+// VersionInfo identifies the daemon.
+type VersionInfo struct {
+	Version           string `json:"version"`
+	RPCVersion        int    `json:"rpc-version"`
+	RPCVersionMinimum int    `json:"rpc-version-minimum"`
+	// RPCVersionSemver is only reported by Transmission 4.0 and newer.
+	RPCVersionSemver string `json:"rpc-version-semver"`
+}
+
+// versionCache holds the daemon's version once it is known. It is safe for
+// concurrent use and shared between copies of the ApiClient.
+type versionCache struct {
+	mu   sync.Mutex
+	info *VersionInfo
+}
+
+// get returns the cached version, or nil if it isn't known.
+func (c *versionCache) get() *VersionInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.info
+}
+
+// set replaces the cached version; nil forgets it.
+func (c *versionCache) set(info *VersionInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.info = info
+}
+
+// ServerVersion get the daemon's version. It is fetched with session-get on
+// first use and cached on the client.
+func (ac *TransmissionClient) ServerVersion() (VersionInfo, error) {
+	return ac.serverVersion(context.Background())
+}
+
+func (ac *TransmissionClient) serverVersion(ctx context.Context) (VersionInfo, error) {
+	if cached := ac.apiclient.version.get(); cached != nil {
+		return *cached, nil
+	}
+	var version VersionInfo
+	err := ac.rpcContext(ctx, "session-get", map[string]interface{}{
+		"fields": []string{"version", "rpc-version", "rpc-version-minimum",
+			"rpc-version-semver"},
+	}, &version)
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	ac.apiclient.version.set(&version)
+	ac.apiclient.saveSession(&version)
+	return version, nil
+}
+
+// getRPCVersion returns the daemon's cached RPC version.
+func (ac *TransmissionClient) getRPCVersion() (int, error) {
+	version, err := ac.ServerVersion()
+	return version.RPCVersion, err
+}
+
+// requireRPCVersion returns ErrUnsupportedRPCVersion if the daemon is older
+// than required. feature names what is being gated for the error message.
+func (ac *TransmissionClient) requireRPCVersion(feature string, required int) error {
+	return ac.requireRPCVersionContext(context.Background(), feature, required)
+}
+
+func (ac *TransmissionClient) requireRPCVersionContext(ctx context.Context, feature string, required int) error {
+	version, err := ac.serverVersion(ctx)
+	if err != nil {
+		return err
+	}
+	actual := version.RPCVersion
+	if actual < required {
+		return ErrUnsupportedRPCVersion{
+			Feature:  feature,
+			Required: required,
+			Actual:   actual,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package transmission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnsupportedRPCVersion(t *testing.T) {
+	tSetup(`{"arguments":{"rpc-version":15},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test newer features return ErrUnsupportedRPCVersion", t, func() {
+		for _, call := range []func() error{
+			func() error { return transmissionClient.SetTorrentLabels(1, []string{"tv"}) },
+			func() error { return transmissionClient.SetTorrentGroup(1, "slow") },
+			func() error { return transmissionClient.SetTorrentTrackers(1, nil) },
+			func() error { return transmissionClient.SetSequentialDownload(1, true) },
+		} {
+			err := call()
+			versionErr, ok := err.(ErrUnsupportedRPCVersion)
+			So(ok, ShouldBeTrue)
+			So(versionErr.Actual, ShouldEqual, 15)
+			So(versionErr.Required, ShouldBeGreaterThan, 15)
+		}
+	})
+
+	Convey("Test the error message", t, func() {
+		err := ErrUnsupportedRPCVersion{Feature: "labels", Required: 16, Actual: 15}
+		So(err.Error(), ShouldEqual, "labels requires RPC version 16, daemon has 15")
+	})
+}
+
+func TestServerVersion(t *testing.T) {
+	tSetup(`{"arguments":{"version":"4.0.5 (a6fe2a64aa)","rpc-version":17,
+  "rpc-version-minimum":14,"rpc-version-semver":"5.3.0"},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test getting the server version", t, func() {
+		version, err := transmissionClient.ServerVersion()
+		So(err, ShouldBeNil)
+		So(version.Version, ShouldEqual, "4.0.5 (a6fe2a64aa)")
+		So(version.RPCVersion, ShouldEqual, 17)
+		So(version.RPCVersionMinimum, ShouldEqual, 14)
+		So(version.RPCVersionSemver, ShouldEqual, "5.3.0")
+	})
+
+	Convey("Test the server version is cached", t, func() {
+		tServer.Close()
+
+		version, err := transmissionClient.ServerVersion()
+		So(err, ShouldBeNil)
+		So(version.RPCVersion, ShouldEqual, 17)
+	})
+}
+
+func TestServerVersionCache(t *testing.T) {
+	var upgraded int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := "old"
+		if atomic.LoadInt32(&upgraded) == 1 {
+			token = "new"
+		}
+		if r.Header.Get("X-Transmission-Session-Id") != token {
+			w.Header().Set("X-Transmission-Session-Id", token)
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if token == "new" {
+			w.Write([]byte(`{"arguments":{"rpc-version":18},"result":"success"}`))
+			return
+		}
+		w.Write([]byte(`{"arguments":{"rpc-version":17},"result":"success"}`))
+	}))
+	defer server.Close()
+	client := New(server.URL, "", "")
+
+	Convey("Test copies of the client share the cache safely", t, func() {
+		var wg sync.WaitGroup
+		versions := make([]int, 8)
+		for i := range versions {
+			wg.Add(1)
+			go func(i int, client TransmissionClient) {
+				defer wg.Done()
+				version, _ := client.ServerVersion()
+				versions[i] = version.RPCVersion
+			}(i, client)
+		}
+		wg.Wait()
+		for _, version := range versions {
+			So(version, ShouldEqual, 17)
+		}
+	})
+
+	Convey("Test a new session id clears the cache", t, func() {
+		atomic.StoreInt32(&upgraded, 1)
+		_, err := client.GetSession()
+		So(err, ShouldBeNil)
+
+		version, err := client.ServerVersion()
+		So(err, ShouldBeNil)
+		So(version.RPCVersion, ShouldEqual, 18)
+	})
+}
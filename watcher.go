@@ -0,0 +1,229 @@
+package transmission
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// EventType tells what happened to a torrent.
+type EventType int
+
+const (
+	// EventAdded is sent for a torrent that wasn't in the previous poll.
+	EventAdded EventType = iota
+	// EventRemoved is sent for a torrent that disappeared.
+	EventRemoved
+	// EventChanged is sent for a torrent whose fields changed.
+	EventChanged
+	// EventCompleted is sent when a torrent finishes downloading. It
+	// follows the EventChanged for the same poll.
+	EventCompleted
+	// EventDaemonRestarted is sent when the daemon was restarted since the
+	// previous poll. It comes before the other events of the poll.
+	EventDaemonRestarted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventAdded:
+		return "added"
+	case EventRemoved:
+		return "removed"
+	case EventChanged:
+		return "changed"
+	case EventCompleted:
+		return "completed"
+	case EventDaemonRestarted:
+		return "daemon restarted"
+	}
+	return "unknown"
+}
+
+// Event describes a change to a single torrent seen by a Watcher.
+type Event struct {
+	Type    EventType
+	Torrent Torrent
+	// Fields holds the changed field names for EventChanged.
+	Fields []string
+	// Remapped maps old torrent IDs to new ones for EventDaemonRestarted.
+	Remapped map[int]int
+	Time     time.Time
+}
+
+// Watcher polls the torrent list and reports what changed since the
+// previous poll.
+//
+// A restarted daemon may hand out new IDs to the same torrents. The
+// watcher notices this when the IDs of known hashes change, or when the
+// daemon comes back with a new session id after a failed poll. It then
+// sends EventDaemonRestarted and matches torrents by hash, so the restart
+// doesn't show up as every torrent being removed and added again.
+type Watcher struct {
+	// Interval between polls in Run. Defaults to five seconds.
+	Interval time.Duration
+	// OnEvent is called for every event, in the order they happened.
+	OnEvent func(Event)
+	// OnError is called when a poll fails in Run.
+	OnError func(error)
+	// Journal, if set, records the events of every poll for Replay.
+	// Failures to record them are logged and otherwise ignored.
+	Journal JournalStore
+
+	client *TransmissionClient
+	last   Torrents
+	primed bool
+	failed bool
+	token  string
+}
+
+// NewWatcher create a watcher polling through client
+func NewWatcher(client *TransmissionClient) *Watcher {
+	return &Watcher{
+		Interval: 5 * time.Second,
+		client:   client,
+	}
+}
+
+// Poll fetches the torrents and returns the events since the previous
+// poll. The first poll only records the current state.
+func (w *Watcher) Poll() ([]Event, error) {
+	return w.PollContext(context.Background())
+}
+
+// PollContext is like Poll but aborts the request when ctx is done.
+func (w *Watcher) PollContext(ctx context.Context) ([]Event, error) {
+	torrents, err := w.client.fetchTorrents(ctx, torrentGetFields, nil)
+	if err != nil {
+		w.failed = true
+		w.client.apiclient.log(ctx, slog.LevelWarn, "watcher poll failed", slog.Any("error", err))
+		return nil, err
+	}
+	token := w.client.apiclient.token.peek()
+	if !w.primed {
+		w.last = torrents
+		w.primed = true
+		w.token = token
+		return nil, nil
+	}
+
+	remapped := remapIDs(w.last, torrents)
+	restarted := len(remapped) > 0 || w.failed && w.token != "" && token != w.token
+	w.failed = false
+	w.token = token
+	previous := w.last
+	if len(remapped) > 0 {
+		previous = make(Torrents, len(w.last))
+		for i, torrent := range w.last {
+			if id, ok := remapped[torrent.ID]; ok {
+				torrent.ID = id
+			}
+			previous[i] = torrent
+		}
+	}
+
+	diff := torrents.Diff(previous)
+	w.last = torrents
+	now := time.Now()
+
+	var events []Event
+	if restarted {
+		events = append(events, Event{Type: EventDaemonRestarted, Remapped: remapped, Time: now})
+	}
+	for _, torrent := range diff.Added {
+		events = append(events, Event{Type: EventAdded, Torrent: torrent, Time: now})
+	}
+	for _, torrent := range diff.Removed {
+		events = append(events, Event{Type: EventRemoved, Torrent: torrent, Time: now})
+	}
+	for _, change := range diff.Changed {
+		events = append(events, Event{Type: EventChanged, Torrent: change.Torrent, Fields: change.Fields, Time: now})
+		if completed(change) {
+			events = append(events, Event{Type: EventCompleted, Torrent: change.Torrent, Time: now})
+		}
+	}
+
+	if w.Journal != nil && len(events) > 0 {
+		err = w.Journal.Append(events...)
+		if err != nil {
+			w.client.apiclient.log(ctx, slog.LevelWarn, "journal append failed", slog.Any("error", err))
+		}
+	}
+	for _, event := range events {
+		w.client.apiclient.logEvent(event)
+		if w.OnEvent != nil {
+			w.OnEvent(event)
+		}
+	}
+	return events, nil
+}
+
+// ResolveHash returns the current ID of the torrent with hash, as of the
+// last poll. IDs kept across a daemon restart should be resolved again.
+func (w *Watcher) ResolveHash(hash string) (int, bool) {
+	for _, torrent := range w.last {
+		if torrent.HashString == hash {
+			return torrent.ID, true
+		}
+	}
+	return 0, false
+}
+
+// remapIDs returns the old and new IDs of the torrents in current whose
+// hash has a different ID in previous.
+func remapIDs(previous, current Torrents) map[int]int {
+	ids := make(map[string]int, len(current))
+	for _, torrent := range current {
+		if torrent.HashString != "" {
+			ids[torrent.HashString] = torrent.ID
+		}
+	}
+	var remapped map[int]int
+	for _, torrent := range previous {
+		id, ok := ids[torrent.HashString]
+		if !ok || id == torrent.ID {
+			continue
+		}
+		if remapped == nil {
+			remapped = make(map[int]int)
+		}
+		remapped[torrent.ID] = id
+	}
+	return remapped
+}
+
+// completed reports whether the change finished the download.
+func completed(change TorrentChange) bool {
+	if change.Torrent.PercentDone < 1 {
+		return false
+	}
+	for _, field := range change.Fields {
+		if field == "PercentDone" {
+			return true
+		}
+	}
+	return false
+}
+
+// Run polls every interval until ctx is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := w.PollContext(ctx)
+		if err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package transmission
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"name":"a","leftUntilDone":0,"percentDone":1,"sizeWhenDone":100},
+  {"id":3,"name":"c"}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test the first poll only records the state", t, func() {
+		w := NewWatcher(&transmissionClient)
+		events, err := w.Poll()
+		So(err, ShouldBeNil)
+		So(events, ShouldBeEmpty)
+
+		events, err = w.Poll()
+		So(err, ShouldBeNil)
+		So(events, ShouldBeEmpty)
+	})
+
+	Convey("Test events since the previous poll", t, func() {
+		var seen []Event
+		w := NewWatcher(&transmissionClient)
+		w.OnEvent = func(event Event) { seen = append(seen, event) }
+		w.primed = true
+		w.last = Torrents{
+			{ID: 1, Name: "a", LeftUntilDone: 50, PercentDone: 0.5, SizeWhenDone: 100},
+			{ID: 2, Name: "b"},
+		}
+
+		events, err := w.Poll()
+		So(err, ShouldBeNil)
+		So(events, ShouldHaveLength, 4)
+		So(events[0].Type, ShouldEqual, EventAdded)
+		So(events[0].Torrent.ID, ShouldEqual, 3)
+		So(events[1].Type, ShouldEqual, EventRemoved)
+		So(events[1].Torrent.ID, ShouldEqual, 2)
+		So(events[2].Type, ShouldEqual, EventChanged)
+		So(events[2].Fields, ShouldResemble, []string{"LeftUntilDone", "PercentDone"})
+		So(events[3].Type, ShouldEqual, EventCompleted)
+		So(events[3].Type.String(), ShouldEqual, "completed")
+		So(seen, ShouldResemble, events)
+	})
+}
+
+func TestWatcherDaemonRestart(t *testing.T) {
+	tSetup(`{"arguments":{"torrents":[
+  {"id":1,"hashString":"bbbb","name":"b"},
+  {"id":2,"hashString":"aaaa","name":"a"}]},"result":"success"}`)
+	defer tTeardown()
+
+	Convey("Test new IDs for known hashes are a restart", t, func() {
+		w := NewWatcher(&transmissionClient)
+		w.primed = true
+		w.last = Torrents{
+			{ID: 7, HashString: "aaaa", Name: "a"},
+			{ID: 8, HashString: "bbbb", Name: "b"},
+		}
+
+		events, err := w.Poll()
+		So(err, ShouldBeNil)
+		So(events, ShouldHaveLength, 1)
+		So(events[0].Type, ShouldEqual, EventDaemonRestarted)
+		So(events[0].Remapped, ShouldResemble, map[int]int{7: 2, 8: 1})
+
+		id, ok := w.ResolveHash("aaaa")
+		So(ok, ShouldBeTrue)
+		So(id, ShouldEqual, 2)
+	})
+
+	Convey("Test a new session id after a failed poll is a restart", t, func() {
+		w := NewWatcher(&transmissionClient)
+		_, err := w.Poll()
+		So(err, ShouldBeNil)
+
+		w.token = "before-restart"
+		w.failed = true
+		events, err := w.Poll()
+		So(err, ShouldBeNil)
+		So(events, ShouldHaveLength, 1)
+		So(events[0].Type, ShouldEqual, EventDaemonRestarted)
+		So(events[0].Remapped, ShouldBeEmpty)
+
+		events, err = w.Poll()
+		So(err, ShouldBeNil)
+		So(events, ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,336 @@
+// Package wsgateway streams torrent events and snapshots to browsers over
+// WebSocket. Each connection can narrow what it receives with query
+// parameters:
+//
+//	/ws?label=tv&label=movies   only torrents with one of the labels
+//	/ws?hash=875a2d90...        only the torrents with the hashes
+//	/ws?event=added&event=completed
+//	                            only those event types, see EventType.String
+//	/ws?snapshots=false         no periodic snapshots
+//
+// Messages are JSON objects with a "type" of "event" or "snapshot".
+//
+// Browsers let any page open a WebSocket to any host, so handshakes from
+// pages of other origins are refused unless AllowedOrigins or CheckOrigin
+// accept them, and Authorize can require credentials.
+package wsgateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tubbebubbe/transmission"
+)
+
+// Message is sent to clients as JSON.
+type Message struct {
+	Type string `json:"type"`
+	// Event, Torrent and Fields are set for events.
+	Event   string                `json:"event,omitempty"`
+	Torrent *transmission.Torrent `json:"torrent,omitempty"`
+	Fields  []string              `json:"fields,omitempty"`
+	// Torrents is set for snapshots.
+	Torrents transmission.Torrents `json:"torrents,omitempty"`
+	Time     time.Time             `json:"time"`
+}
+
+// Filter selects what a connection receives. Empty lists match everything.
+type Filter struct {
+	Labels    []string
+	Hashes    []string
+	Events    []string
+	Snapshots bool
+}
+
+// ParseFilter reads a Filter from the query parameters of r.
+func ParseFilter(r *http.Request) Filter {
+	query := r.URL.Query()
+	return Filter{
+		Labels:    query["label"],
+		Hashes:    query["hash"],
+		Events:    query["event"],
+		Snapshots: query.Get("snapshots") != "false",
+	}
+}
+
+// Torrent reports whether the torrent passes the filter.
+func (f Filter) Torrent(t transmission.Torrent) bool {
+	if len(f.Hashes) > 0 && !contains(f.Hashes, t.HashString) {
+		return false
+	}
+	if len(f.Labels) == 0 {
+		return true
+	}
+	for _, label := range f.Labels {
+		if t.HasLabel(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// Event reports whether the event passes the filter.
+func (f Filter) Event(event transmission.Event) bool {
+	if len(f.Events) > 0 && !contains(f.Events, event.Type.String()) {
+		return false
+	}
+	// Restarts aren't about a single torrent.
+	return event.Type == transmission.EventDaemonRestarted || f.Torrent(event.Torrent)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Gateway fans out events and snapshots of a client to WebSocket
+// connections. It is an http.Handler; Run must be running for anything to
+// be sent.
+type Gateway struct {
+	// PollInterval is how often the watcher polls. Defaults to five
+	// seconds.
+	PollInterval time.Duration
+	// SnapshotInterval is how often a snapshot of all torrents is sent.
+	// Defaults to thirty seconds.
+	SnapshotInterval time.Duration
+	// Buffer is the number of messages queued per connection. A client
+	// that falls further behind is disconnected. Defaults to 64.
+	Buffer int
+	// OnError is called when polling fails.
+	OnError func(error)
+	// Authorize rejects handshakes for which it returns false with 401
+	// Unauthorized.
+	Authorize func(*http.Request) bool
+	// AllowedOrigins are the origins, such as "https://example.com", whose
+	// pages may connect besides the gateway's own. "*" allows all of them.
+	AllowedOrigins []string
+	// CheckOrigin replaces the origin check when set, returning whether a
+	// handshake is allowed.
+	CheckOrigin func(*http.Request) bool
+
+	client *transmission.TransmissionClient
+	mu     sync.Mutex
+	conns  map[*conn]bool
+}
+
+// New create a gateway for client
+func New(client *transmission.TransmissionClient) *Gateway {
+	return &Gateway{
+		PollInterval:     5 * time.Second,
+		SnapshotInterval: 30 * time.Second,
+		Buffer:           64,
+		client:           client,
+		conns:            make(map[*conn]bool),
+	}
+}
+
+// conn is a client connection with its own send queue.
+type conn struct {
+	filter Filter
+	send   chan []byte
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (c *conn) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// ServeHTTP upgrades the request to a WebSocket and streams messages to it
+// until either side closes the connection.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if g.Authorize != nil && !g.Authorize(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !g.originAllowed(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	netConn, rw, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer netConn.Close()
+
+	buffer := g.Buffer
+	if buffer <= 0 {
+		buffer = 64
+	}
+	c := &conn{filter: ParseFilter(r), send: make(chan []byte, buffer), done: make(chan struct{})}
+	g.mu.Lock()
+	g.conns[c] = true
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		delete(g.conns, c)
+		g.mu.Unlock()
+	}()
+
+	pongs := make(chan []byte, 1)
+	go g.readLoop(c, rw.Reader, pongs)
+	g.writeLoop(c, netConn, rw.Writer, pongs)
+}
+
+// originAllowed reports whether the page that opened the connection may
+// use the gateway. Clients other than browsers send no Origin.
+func (g *Gateway) originAllowed(r *http.Request) bool {
+	if g.CheckOrigin != nil {
+		return g.CheckOrigin(r)
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range g.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// readLoop handles control frames from the client until it disconnects.
+func (g *Gateway) readLoop(c *conn, r *bufio.Reader, pongs chan<- []byte) {
+	defer c.close()
+	for {
+		opcode, payload, err := readFrame(r)
+		if err != nil || opcode == opClose {
+			return
+		}
+		if opcode == opPing {
+			select {
+			case pongs <- payload:
+			default:
+			}
+		}
+	}
+}
+
+// writeTimeout bounds a frame write, so a client that stops reading can't
+// hold its connection open.
+const writeTimeout = 10 * time.Second
+
+func (g *Gateway) writeLoop(c *conn, netConn net.Conn, w *bufio.Writer, pongs <-chan []byte) {
+	for {
+		var err error
+		select {
+		case <-c.done:
+			netConn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			writeFrame(w, opClose, nil)
+			return
+		case payload := <-pongs:
+			netConn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			err = writeFrame(w, opPong, payload)
+		case message := <-c.send:
+			netConn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			err = writeFrame(w, opText, message)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// broadcast queues a message for every connection whose filter accepts
+// it. filter returns the message for a connection, or false to skip it.
+func (g *Gateway) broadcast(filter func(Filter) (Message, bool)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for c := range g.conns {
+		message, ok := filter(c.filter)
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(message)
+		if err != nil {
+			continue
+		}
+		select {
+		case c.send <- data:
+		default:
+			// Too slow to keep up; drop it rather than block everyone.
+			c.close()
+		}
+	}
+}
+
+func (g *Gateway) sendEvent(event transmission.Event) {
+	g.broadcast(func(f Filter) (Message, bool) {
+		if !f.Event(event) {
+			return Message{}, false
+		}
+		message := Message{Type: "event", Event: event.Type.String(), Fields: event.Fields, Time: event.Time}
+		if event.Type != transmission.EventDaemonRestarted {
+			torrent := event.Torrent
+			message.Torrent = &torrent
+		}
+		return message, true
+	})
+}
+
+func (g *Gateway) sendSnapshot(torrents transmission.Torrents) {
+	now := time.Now()
+	g.broadcast(func(f Filter) (Message, bool) {
+		if !f.Snapshots {
+			return Message{}, false
+		}
+		selected := transmission.Torrents{}
+		for _, torrent := range torrents {
+			if f.Torrent(torrent) {
+				selected = append(selected, torrent)
+			}
+		}
+		return Message{Type: "snapshot", Torrents: selected, Time: now}, true
+	})
+}
+
+// Run watches the client and sends events and snapshots until ctx is done.
+func (g *Gateway) Run(ctx context.Context) error {
+	events, err := g.client.Events(ctx, transmission.EventOptions{
+		Interval: g.PollInterval,
+		OnError:  g.OnError,
+	})
+	if err != nil {
+		return err
+	}
+	interval := g.SnapshotInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return ctx.Err()
+			}
+			g.sendEvent(event)
+		case <-ticker.C:
+			torrents, err := g.client.GetTorrents()
+			if err != nil {
+				if g.OnError != nil {
+					g.OnError(err)
+				}
+				continue
+			}
+			g.sendSnapshot(torrents)
+		}
+	}
+}
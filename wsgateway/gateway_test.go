@@ -0,0 +1,226 @@
+package wsgateway
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tubbebubbe/transmission"
+)
+
+// tDial opens a WebSocket to the gateway behind server.
+func tDial(server *httptest.Server, query string) (net.Conn, *bufio.Reader, error) {
+	return tDialHeaders(server, query, "")
+}
+
+// tDialHeaders is tDial sending extra header lines.
+func tDialHeaders(server *httptest.Server, query, headers string) (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		return nil, nil, err
+	}
+	_, err = conn.Write([]byte("GET /ws?" + query + " HTTP/1.1\r\n" +
+		"Host: localhost\r\n" + headers +
+		"Connection: Upgrade\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"))
+	if err != nil {
+		return nil, nil, err
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, nil, fmt.Errorf("handshake: %s", resp.Status)
+	}
+	return conn, r, nil
+}
+
+// tReadMessage reads a text frame sent by the server.
+func tReadMessage(conn net.Conn, r *bufio.Reader) (Message, error) {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var header [2]byte
+	_, err := io.ReadFull(r, header[:])
+	if err != nil {
+		return Message{}, err
+	}
+	length := int(header[1])
+	if length == 126 {
+		var n [2]byte
+		_, err = io.ReadFull(r, n[:])
+		length = int(n[0])<<8 | int(n[1])
+	}
+	payload := make([]byte, length)
+	if err == nil {
+		_, err = io.ReadFull(r, payload)
+	}
+	if err != nil {
+		return Message{}, err
+	}
+	var message Message
+	err = json.Unmarshal(payload, &message)
+	return message, err
+}
+
+// tWaitConns waits until the gateway has n connections.
+func tWaitConns(g *Gateway, n int) {
+	for i := 0; i < 100; i++ {
+		g.mu.Lock()
+		count := len(g.conns)
+		g.mu.Unlock()
+		if count == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	Convey("Test the accept key from RFC 6455", t, func() {
+		So(acceptKey("dGhlIHNhbXBsZSBub25jZQ=="), ShouldEqual, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+	})
+}
+
+func TestFilter(t *testing.T) {
+	tv := transmission.Torrent{HashString: "aaaa", Labels: []string{"tv"}}
+	movie := transmission.Torrent{HashString: "bbbb", Labels: []string{"movies"}}
+
+	Convey("Test filters parsed from the query", t, func() {
+		r := httptest.NewRequest("GET", "/ws?label=tv&event=added&snapshots=false", nil)
+		f := ParseFilter(r)
+		So(f.Labels, ShouldResemble, []string{"tv"})
+		So(f.Snapshots, ShouldBeFalse)
+		So(ParseFilter(httptest.NewRequest("GET", "/ws", nil)).Snapshots, ShouldBeTrue)
+	})
+
+	Convey("Test filtering torrents and events", t, func() {
+		So(Filter{}.Torrent(movie), ShouldBeTrue)
+		So(Filter{Labels: []string{"tv"}}.Torrent(tv), ShouldBeTrue)
+		So(Filter{Labels: []string{"tv"}}.Torrent(movie), ShouldBeFalse)
+		So(Filter{Hashes: []string{"bbbb"}}.Torrent(movie), ShouldBeTrue)
+		So(Filter{Hashes: []string{"bbbb"}}.Torrent(tv), ShouldBeFalse)
+
+		f := Filter{Events: []string{"completed"}, Labels: []string{"tv"}}
+		So(f.Event(transmission.Event{Type: transmission.EventCompleted, Torrent: tv}), ShouldBeTrue)
+		So(f.Event(transmission.Event{Type: transmission.EventAdded, Torrent: tv}), ShouldBeFalse)
+		So(f.Event(transmission.Event{Type: transmission.EventCompleted, Torrent: movie}), ShouldBeFalse)
+		So(Filter{Labels: []string{"tv"}}.Event(transmission.Event{Type: transmission.EventDaemonRestarted}), ShouldBeTrue)
+	})
+}
+
+func TestGateway(t *testing.T) {
+	g := New(nil)
+	server := httptest.NewServer(g)
+	defer server.Close()
+
+	Convey("Test plain requests are refused", t, func() {
+		resp, err := http.Get(server.URL + "/ws")
+		So(err, ShouldBeNil)
+		resp.Body.Close()
+		So(resp.StatusCode, ShouldEqual, http.StatusUpgradeRequired)
+	})
+
+	Convey("Test messages fan out through per-connection filters", t, func() {
+		all, allReader, err := tDial(server, "")
+		So(err, ShouldBeNil)
+		defer all.Close()
+		tv, tvReader, err := tDial(server, "label=tv&snapshots=false")
+		So(err, ShouldBeNil)
+		defer tv.Close()
+		tWaitConns(g, 2)
+
+		g.sendEvent(transmission.Event{Type: transmission.EventAdded,
+			Torrent: transmission.Torrent{ID: 1, HashString: "bbbb", Labels: []string{"movies"}}})
+		g.sendSnapshot(transmission.Torrents{
+			{ID: 1, HashString: "bbbb", Labels: []string{"movies"}},
+			{ID: 2, HashString: "aaaa", Labels: []string{"tv"}},
+		})
+		g.sendEvent(transmission.Event{Type: transmission.EventCompleted,
+			Torrent: transmission.Torrent{ID: 2, HashString: "aaaa", Labels: []string{"tv"}}})
+
+		message, err := tReadMessage(all, allReader)
+		So(err, ShouldBeNil)
+		So(message.Type, ShouldEqual, "event")
+		So(message.Event, ShouldEqual, "added")
+		So(message.Torrent.ID, ShouldEqual, 1)
+
+		message, err = tReadMessage(all, allReader)
+		So(err, ShouldBeNil)
+		So(message.Type, ShouldEqual, "snapshot")
+		So(message.Torrents, ShouldHaveLength, 2)
+
+		message, err = tReadMessage(all, allReader)
+		So(err, ShouldBeNil)
+		So(message.Event, ShouldEqual, "completed")
+
+		message, err = tReadMessage(tv, tvReader)
+		So(err, ShouldBeNil)
+		So(message.Event, ShouldEqual, "completed")
+		So(message.Torrent.HashString, ShouldEqual, "aaaa")
+	})
+
+	Convey("Test closed connections are forgotten", t, func() {
+		conn, _, err := tDial(server, "")
+		So(err, ShouldBeNil)
+		tWaitConns(g, 1)
+		conn.Close()
+		tWaitConns(g, 0)
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		So(g.conns, ShouldBeEmpty)
+	})
+}
+
+func TestGatewayAccess(t *testing.T) {
+	Convey("Test handshakes from other origins are refused", t, func() {
+		g := New(nil)
+		server := httptest.NewServer(g)
+		defer server.Close()
+
+		conn, _, err := tDialHeaders(server, "", "Origin: https://evil.example\r\n")
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "403")
+
+		conn, _, err = tDialHeaders(server, "", "Origin: http://localhost\r\n")
+		So(err, ShouldBeNil)
+		conn.Close()
+
+		g.AllowedOrigins = []string{"https://dashboard.example"}
+		conn, _, err = tDialHeaders(server, "", "Origin: https://dashboard.example\r\n")
+		So(err, ShouldBeNil)
+		conn.Close()
+
+		g.CheckOrigin = func(r *http.Request) bool { return false }
+		_, _, err = tDial(server, "")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test Authorize guards the handshake", t, func() {
+		g := New(nil)
+		g.Authorize = func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == "Bearer secret"
+		}
+		server := httptest.NewServer(g)
+		defer server.Close()
+
+		_, _, err := tDial(server, "")
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "401")
+
+		conn, _, err := tDialHeaders(server, "", "Authorization: Bearer secret\r\n")
+		So(err, ShouldBeNil)
+		conn.Close()
+	})
+}